@@ -6,7 +6,9 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/mcp/client"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -96,9 +98,10 @@ func (a *AutoCodeTemplateApi) MCPTest(c *gin.Context) {
 		return
 	}
 
-	// 创建MCP客户端
+	// 创建MCP客户端，携带当前用户的 x-token 以便需要授权的工具识别调用者
 	baseUrl := fmt.Sprintf("http://127.0.0.1:%d%s", global.GVA_CONFIG.System.Addr, global.GVA_CONFIG.MCP.SSEPath)
-	testClient, err := client.NewClient(baseUrl, "testClient", "v1.0.0", global.GVA_CONFIG.MCP.Name)
+	testClient, err := client.NewClient(baseUrl, "testClient", "v1.0.0", global.GVA_CONFIG.MCP.Name,
+		transport.WithHeaders(map[string]string{"x-token": utils.GetToken(c)}))
 	if err != nil {
 		response.FailWithMessage("创建MCP客户端失败:"+err.Error(), c)
 		return
@@ -24,30 +24,57 @@ type ApiGroup struct {
 	SysParamsApi
 	SysVersionApi
 	SysErrorApi
+	ApiKeyApi
 	UserBalanceApi
 	UserPointApi
+	RiskBirdResetScheduleApi
+	RiskBirdJobApi
+	RiskBirdPointAuditApi
+	RiskBirdSyntheticOrderApi
+	RiskBirdAccountApi
+	RiskBirdFlowApi
+	RiskBirdHealthApi
+	RiskBirdConfigApi
+	RiskBirdImpersonationApi
+	RiskBirdReconciliationApi
+	RiskBirdGeneratorApi
+	RiskBirdNotifyApi
 }
 
 var (
-	apiService              = service.ServiceGroupApp.SystemServiceGroup.ApiService
-	jwtService              = service.ServiceGroupApp.SystemServiceGroup.JwtService
-	menuService             = service.ServiceGroupApp.SystemServiceGroup.MenuService
-	userService             = service.ServiceGroupApp.SystemServiceGroup.UserService
-	initDBService           = service.ServiceGroupApp.SystemServiceGroup.InitDBService
-	casbinService           = service.ServiceGroupApp.SystemServiceGroup.CasbinService
-	baseMenuService         = service.ServiceGroupApp.SystemServiceGroup.BaseMenuService
-	authorityService        = service.ServiceGroupApp.SystemServiceGroup.AuthorityService
-	dictionaryService       = service.ServiceGroupApp.SystemServiceGroup.DictionaryService
-	authorityBtnService     = service.ServiceGroupApp.SystemServiceGroup.AuthorityBtnService
-	systemConfigService     = service.ServiceGroupApp.SystemServiceGroup.SystemConfigService
-	sysParamsService        = service.ServiceGroupApp.SystemServiceGroup.SysParamsService
-	operationRecordService  = service.ServiceGroupApp.SystemServiceGroup.OperationRecordService
-	dictionaryDetailService = service.ServiceGroupApp.SystemServiceGroup.DictionaryDetailService
-	autoCodeService         = service.ServiceGroupApp.SystemServiceGroup.AutoCodeService
-	autoCodePluginService   = service.ServiceGroupApp.SystemServiceGroup.AutoCodePlugin
-	autoCodePackageService  = service.ServiceGroupApp.SystemServiceGroup.AutoCodePackage
-	autoCodeHistoryService  = service.ServiceGroupApp.SystemServiceGroup.AutoCodeHistory
-	autoCodeTemplateService = service.ServiceGroupApp.SystemServiceGroup.AutoCodeTemplate
-	sysVersionService       = service.ServiceGroupApp.SystemServiceGroup.SysVersionService
-	sysErrorService         = service.ServiceGroupApp.SystemServiceGroup.SysErrorService
+	apiService                    = service.ServiceGroupApp.SystemServiceGroup.ApiService
+	jwtService                    = service.ServiceGroupApp.SystemServiceGroup.JwtService
+	menuService                   = service.ServiceGroupApp.SystemServiceGroup.MenuService
+	userService                   = service.ServiceGroupApp.SystemServiceGroup.UserService
+	initDBService                 = service.ServiceGroupApp.SystemServiceGroup.InitDBService
+	casbinService                 = service.ServiceGroupApp.SystemServiceGroup.CasbinService
+	baseMenuService               = service.ServiceGroupApp.SystemServiceGroup.BaseMenuService
+	authorityService              = service.ServiceGroupApp.SystemServiceGroup.AuthorityService
+	dictionaryService             = service.ServiceGroupApp.SystemServiceGroup.DictionaryService
+	authorityBtnService           = service.ServiceGroupApp.SystemServiceGroup.AuthorityBtnService
+	systemConfigService           = service.ServiceGroupApp.SystemServiceGroup.SystemConfigService
+	sysParamsService              = service.ServiceGroupApp.SystemServiceGroup.SysParamsService
+	operationRecordService        = service.ServiceGroupApp.SystemServiceGroup.OperationRecordService
+	dictionaryDetailService       = service.ServiceGroupApp.SystemServiceGroup.DictionaryDetailService
+	autoCodeService               = service.ServiceGroupApp.SystemServiceGroup.AutoCodeService
+	autoCodePluginService         = service.ServiceGroupApp.SystemServiceGroup.AutoCodePlugin
+	autoCodePackageService        = service.ServiceGroupApp.SystemServiceGroup.AutoCodePackage
+	autoCodeHistoryService        = service.ServiceGroupApp.SystemServiceGroup.AutoCodeHistory
+	autoCodeTemplateService       = service.ServiceGroupApp.SystemServiceGroup.AutoCodeTemplate
+	sysVersionService             = service.ServiceGroupApp.SystemServiceGroup.SysVersionService
+	sysErrorService               = service.ServiceGroupApp.SystemServiceGroup.SysErrorService
+	apiKeyService                 = service.ServiceGroupApp.SystemServiceGroup.ApiKeyService
+	riskBirdResetScheduleService  = service.ServiceGroupApp.SystemServiceGroup.RiskBirdResetScheduleService
+	riskBirdJobService            = service.ServiceGroupApp.SystemServiceGroup.RiskBirdJobService
+	riskBirdPointAuditService     = service.ServiceGroupApp.SystemServiceGroup.RiskBirdPointAuditService
+	riskBirdSyntheticOrderService = service.ServiceGroupApp.SystemServiceGroup.RiskBirdSyntheticOrderService
+	riskBirdAccountService        = service.ServiceGroupApp.SystemServiceGroup.RiskBirdAccountService
+	riskBirdFlowService           = service.ServiceGroupApp.SystemServiceGroup.RiskBirdFlowService
+	riskBirdHealthService         = service.ServiceGroupApp.SystemServiceGroup.RiskBirdHealthService
+	riskBirdConfigService         = service.ServiceGroupApp.SystemServiceGroup.RiskBirdConfigService
+	riskBirdImpersonationService  = service.ServiceGroupApp.SystemServiceGroup.RiskBirdImpersonationService
+	riskBirdReconciliationService = service.ServiceGroupApp.SystemServiceGroup.RiskBirdReconciliationService
+	riskBirdGeneratorService      = service.ServiceGroupApp.SystemServiceGroup.RiskBirdGeneratorService
+	riskBirdScopeService          = service.ServiceGroupApp.SystemServiceGroup.RiskBirdScopeService
+	riskBirdNotifyService         = service.ServiceGroupApp.SystemServiceGroup.RiskBirdNotifyService
 )
@@ -0,0 +1,193 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdAccountApi struct{}
+
+// CreateAccount 登记 RiskBird 账号
+// @Tags     RiskBirdAccount
+// @Summary  登记已有的 RiskBird 账号
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      system.RiskBirdAccount         true  "环境, 手机号, 密码, 标签"
+// @Success  200   {object}  response.Response{msg=string}  "创建成功"
+// @Router   /riskbird/account/createAccount [post]
+func (a *RiskBirdAccountApi) CreateAccount(c *gin.Context) {
+	var account system.RiskBirdAccount
+	err := c.ShouldBindJSON(&account)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdAccountService.CreateAccount(&account)
+	if err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("创建成功", c)
+}
+
+// DeleteAccount 删除登记的 RiskBird 账号
+// @Tags     RiskBirdAccount
+// @Summary  删除登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                true  "账号ID"
+// @Success  200   {object}  response.Response{msg=string}  "删除成功"
+// @Router   /riskbird/account/deleteAccount [delete]
+func (a *RiskBirdAccountApi) DeleteAccount(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdAccountService.DeleteAccount(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("删除失败!", zap.Error(err))
+		response.FailWithMessage("删除失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("删除成功", c)
+}
+
+// UpdateAccount 更新登记的 RiskBird 账号
+// @Tags     RiskBirdAccount
+// @Summary  更新登记的 RiskBird 账号，密码为空时保持不变
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      system.RiskBirdAccount         true  "手机号, 密码, 标签, 备注"
+// @Success  200   {object}  response.Response{msg=string}  "更新成功"
+// @Router   /riskbird/account/updateAccount [put]
+func (a *RiskBirdAccountApi) UpdateAccount(c *gin.Context) {
+	var account system.RiskBirdAccount
+	err := c.ShouldBindJSON(&account)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdAccountService.UpdateAccount(account)
+	if err != nil {
+		global.GVA_LOG.Error("更新失败!", zap.Error(err))
+		response.FailWithMessage("更新失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("更新成功", c)
+}
+
+// FindAccount 用id查询登记的 RiskBird 账号
+// @Tags     RiskBirdAccount
+// @Summary  用id查询登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     request.GetById                                           true  "账号ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdAccount,msg=string}  "查询成功"
+// @Router   /riskbird/account/findAccount [get]
+func (a *RiskBirdAccountApi) FindAccount(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	account, err := riskBirdAccountService.GetAccount(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithData(account, c)
+}
+
+// GetAccountList 分页获取登记的 RiskBird 账号
+// @Tags     RiskBirdAccount
+// @Summary  分页获取登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdAccountSearch                          true  "环境, 手机号, 标签"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/account/getAccountList [get]
+func (a *RiskBirdAccountApi) GetAccountList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdAccountSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdAccountService.GetAccountList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// ProvisionAccount 注册并登记 RiskBird 测试账号
+// @Tags     RiskBirdAccount
+// @Summary  注册新的 RiskBird 测试用户，可选设置初始余额和积分
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.ProvisionRiskBirdAccount                         true  "环境, 手机号, 密码, 标签, 初始余额和积分"
+// @Success  200   {object}  response.Response{data=system.RiskBirdAccount,msg=string}  "开通成功"
+// @Router   /riskbird/account/provisionAccount [post]
+func (a *RiskBirdAccountApi) ProvisionAccount(c *gin.Context) {
+	var req systemReq.ProvisionRiskBirdAccount
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorID = utils.GetUserID(c)
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	account, err := riskBirdAccountService.ProvisionAccount(req)
+	if err != nil {
+		global.GVA_LOG.Error("开通账号失败!", zap.Error(err))
+		response.FailWithDetailed(account, "开通失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(account, "开通成功", c)
+}
+
+// GetAccountTimeline 获取账号变更时间线
+// @Tags     RiskBirdAccount
+// @Summary  合并流程执行记录、代登录审计、合成订单、积分获取记录、重置、核对和配置恢复，按时间倒序返回账号的变更时间线
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdAccountTimelineSearch                                  true  "账号ID或手机号, 环境, 时间范围"
+// @Success  200   {object}  response.Response{data=systemRes.RiskBirdAccountTimeline,msg=string}  "获取成功"
+// @Router   /riskbird/account/getAccountTimeline [get]
+func (a *RiskBirdAccountApi) GetAccountTimeline(c *gin.Context) {
+	var req systemReq.RiskBirdAccountTimelineSearch
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	timeline, err := riskBirdAccountService.GetAccountTimeline(req, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(timeline, "获取成功", c)
+}
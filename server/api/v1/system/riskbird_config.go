@@ -0,0 +1,195 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdConfigApi struct{}
+
+// DiffConfig 比对RiskBird价格和充值套餐配置
+// @Tags     RiskBirdConfig
+// @Summary  比对两个环境的 p_product_cfg 和 p_recharge_product，或比对一个环境与基线
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.DiffRiskBirdConfig                                          true  "左侧环境, 右侧环境或基线ID"
+// @Success  200   {object}  response.Response{data=systemRes.RiskBirdConfigDiffResult,msg=string}  "比对完成"
+// @Router   /riskbird/config/diffConfig [get]
+func (a *RiskBirdConfigApi) DiffConfig(c *gin.Context) {
+	var req systemReq.DiffRiskBirdConfig
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	result, err := riskBirdConfigService.DiffConfig(req, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("比对失败!", zap.Error(err))
+		response.FailWithMessage("比对失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(result, "比对完成", c)
+}
+
+// CreateBaseline 从环境快照创建配置基线
+// @Tags     RiskBirdConfig
+// @Summary  将环境当前的价格和充值套餐配置保存为基线
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.CreateRiskBirdConfigBaseline                              true  "基线名称, 环境"
+// @Success  200   {object}  response.Response{data=system.RiskBirdConfigBaseline,msg=string}  "创建成功"
+// @Router   /riskbird/config/createBaseline [post]
+func (a *RiskBirdConfigApi) CreateBaseline(c *gin.Context) {
+	var req systemReq.CreateRiskBirdConfigBaseline
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	baseline, err := riskBirdConfigService.CreateBaseline(req, utils.GetUserID(c), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(baseline, "创建成功", c)
+}
+
+// DeleteBaseline 删除配置基线
+// @Tags     RiskBirdConfig
+// @Summary  删除配置基线
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                true  "基线ID"
+// @Success  200   {object}  response.Response{msg=string}  "删除成功"
+// @Router   /riskbird/config/deleteBaseline [delete]
+func (a *RiskBirdConfigApi) DeleteBaseline(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdConfigService.DeleteBaseline(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("删除失败!", zap.Error(err))
+		response.FailWithMessage("删除失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("删除成功", c)
+}
+
+// FindBaseline 根据ID获取配置基线
+// @Tags     RiskBirdConfig
+// @Summary  根据ID获取配置基线，包含快照内容
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     request.GetById                                                  true  "基线ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdConfigBaseline,msg=string}  "获取成功"
+// @Router   /riskbird/config/findBaseline [get]
+func (a *RiskBirdConfigApi) FindBaseline(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	baseline, err := riskBirdConfigService.GetBaseline(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(baseline, "获取成功", c)
+}
+
+// GetBaselineList 分页获取配置基线
+// @Tags     RiskBirdConfig
+// @Summary  分页获取配置基线
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdConfigBaselineSearch                 true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/config/getBaselineList [get]
+func (a *RiskBirdConfigApi) GetBaselineList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdConfigBaselineSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdConfigService.GetBaselineList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// RestoreBaseline 按基线恢复环境配置
+// @Tags     RiskBirdConfig
+// @Summary  将环境中与基线不同的配置恢复为基线值，并记录恢复前的差异
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.RestoreRiskBirdConfigBaseline                            true  "基线ID, 环境"
+// @Success  200   {object}  response.Response{data=system.RiskBirdConfigRestore,msg=string}  "恢复成功"
+// @Router   /riskbird/config/restoreBaseline [post]
+func (a *RiskBirdConfigApi) RestoreBaseline(c *gin.Context) {
+	var req systemReq.RestoreRiskBirdConfigBaseline
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	restore, err := riskBirdConfigService.RestoreBaseline(req, utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("恢复失败!", zap.Error(err))
+		response.FailWithMessage("恢复失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(restore, "恢复成功", c)
+}
+
+// GetRestoreList 分页获取配置恢复记录
+// @Tags     RiskBirdConfig
+// @Summary  分页获取配置恢复记录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdConfigRestoreSearch                  true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/config/getRestoreList [get]
+func (a *RiskBirdConfigApi) GetRestoreList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdConfigRestoreSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdConfigService.GetRestoreList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
@@ -0,0 +1,197 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SSE 心跳间隔，避免代理因连接空闲断开
+const riskBirdFlowHeartbeat = 15 * time.Second
+
+type RiskBirdFlowApi struct{}
+
+// StartModifyUserBalance 异步修改用户余额
+// @Tags     RiskBirdFlow
+// @Summary  异步修改用户余额，返回流程执行记录，进度通过 streamRunEvents 订阅
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.ModifyUserBalance                                 true  "环境, 手机号和密码或验证码登录或代登录目标, 充值金额, 赠送金额, 续跑的执行记录ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdFlowRun,msg=string}  "已开始执行"
+// @Router   /riskbird/flow/startModifyUserBalance [post]
+func (a *RiskBirdFlowApi) StartModifyUserBalance(c *gin.Context) {
+	var req systemReq.ModifyUserBalance
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorID = utils.GetUserID(c)
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	run, err := riskBirdFlowService.StartModifyUserBalance(req, req.OperatorID)
+	if err != nil {
+		global.GVA_LOG.Error("修改用户余额失败!", zap.Error(err))
+		response.FailWithMessage("修改用户余额失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(run, "已开始执行", c)
+}
+
+// StartModifyUserPoint 异步修改用户积分
+// @Tags     RiskBirdFlow
+// @Summary  异步修改用户积分，返回流程执行记录，进度通过 streamRunEvents 订阅
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.ModifyUserPoint                                   true  "环境, 手机号和密码或验证码登录或代登录目标, 修改积分, 修改方式, 续跑的执行记录ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdFlowRun,msg=string}  "已开始执行"
+// @Router   /riskbird/flow/startModifyUserPoint [post]
+func (a *RiskBirdFlowApi) StartModifyUserPoint(c *gin.Context) {
+	var req systemReq.ModifyUserPoint
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorID = utils.GetUserID(c)
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	run, err := riskBirdFlowService.StartModifyUserPoint(req, req.OperatorID)
+	if err != nil {
+		global.GVA_LOG.Error("修改用户积分失败!", zap.Error(err))
+		response.FailWithMessage("修改用户积分失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(run, "已开始执行", c)
+}
+
+// StreamRunEvents 订阅流程步骤事件
+// @Tags     RiskBirdFlow
+// @Summary  以 Server-Sent Events 推送流程步骤事件，支持 Last-Event-ID 断线续传，流程结束后关闭连接
+// @Security ApiKeyAuth
+// @Produce  text/event-stream
+// @Param    data  query     request.GetById           true  "流程执行记录ID"
+// @Success  200   {object}  system.RiskBirdStepEvent  "event: step"
+// @Router   /riskbird/flow/streamRunEvents [get]
+func (a *RiskBirdFlowApi) StreamRunEvents(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	history, events, cancel, err := riskBirdFlowService.SubscribeRun(reqId.Uint(), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("订阅流程失败!", zap.Error(err))
+		response.FailWithMessage("订阅失败:"+err.Error(), c)
+		return
+	}
+	defer cancel()
+
+	lastSeq, _ := strconv.Atoi(c.GetHeader("Last-Event-ID"))
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(200)
+	for _, event := range history {
+		if event.Seq > lastSeq {
+			writeRiskBirdStepEvent(c.Writer, event)
+		}
+	}
+	c.Writer.Flush()
+	if events == nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(riskBirdFlowHeartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			writeRiskBirdStepEvent(w, event)
+			return true
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func writeRiskBirdStepEvent(w io.Writer, event system.RiskBirdStepEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		global.GVA_LOG.Error("序列化流程事件失败!", zap.Error(err))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "id: %d\nevent: step\ndata: %s\n\n", event.Seq, data)
+}
+
+// FindFlowRun 用id查询流程执行记录
+// @Tags     RiskBirdFlow
+// @Summary  用id查询流程执行记录，包含全部步骤事件
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     request.GetById                                           true  "记录ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdFlowRun,msg=string}  "查询成功"
+// @Router   /riskbird/flow/findFlowRun [get]
+func (a *RiskBirdFlowApi) FindFlowRun(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	run, err := riskBirdFlowService.GetFlowRun(reqId.Uint(), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithData(run, c)
+}
+
+// GetFlowRunList 分页获取流程执行记录
+// @Tags     RiskBirdFlow
+// @Summary  分页获取流程执行记录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdFlowRunSearch                        true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/flow/getFlowRunList [get]
+func (a *RiskBirdFlowApi) GetFlowRunList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdFlowRunSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdFlowService.GetFlowRunList(pageInfo, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
@@ -0,0 +1,199 @@
+package system
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdGeneratorApi struct{}
+
+// CreateJob 创建批量造数任务
+// @Tags     RiskBirdGenerator
+// @Summary  按数量和数据分布批量注册或复用账号并生成余额、订单历史和积分，创建后立即在后台执行
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.CreateRiskBirdGeneratorJob                              true  "任务名称, 环境, 数量, 手机号前缀, 密码, 数据分布"
+// @Success  200   {object}  response.Response{data=system.RiskBirdGeneratorJob,msg=string}  "已开始执行"
+// @Router   /riskbird/generator/createJob [post]
+func (a *RiskBirdGeneratorApi) CreateJob(c *gin.Context) {
+	var req systemReq.CreateRiskBirdGeneratorJob
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	job, err := riskBirdGeneratorService.CreateJob(req, utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(job, "已开始执行", c)
+}
+
+// ResumeJob 继续执行造数任务
+// @Tags     RiskBirdGenerator
+// @Summary  继续执行已停止、中断或部分失败的造数任务，只处理未完成的账号
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                                                   true  "任务ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdGeneratorJob,msg=string}  "已开始执行"
+// @Router   /riskbird/generator/resumeJob [post]
+func (a *RiskBirdGeneratorApi) ResumeJob(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	job, err := riskBirdGeneratorService.ResumeJob(reqId.Uint(), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("继续执行失败!", zap.Error(err))
+		response.FailWithMessage("继续执行失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(job, "已开始执行", c)
+}
+
+// StopJob 停止造数任务
+// @Tags     RiskBirdGenerator
+// @Summary  停止执行中的造数任务，正在处理的账号完成当前阶段后停止
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                true  "任务ID"
+// @Success  200   {object}  response.Response{msg=string}  "已停止"
+// @Router   /riskbird/generator/stopJob [post]
+func (a *RiskBirdGeneratorApi) StopJob(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdGeneratorService.StopJob(reqId.Uint(), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("停止失败!", zap.Error(err))
+		response.FailWithMessage("停止失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("已停止", c)
+}
+
+// FindJob 用id查询造数任务
+// @Tags     RiskBirdGenerator
+// @Summary  用id查询造数任务
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     request.GetById                                                   true  "任务ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdGeneratorJob,msg=string}  "查询成功"
+// @Router   /riskbird/generator/findJob [get]
+func (a *RiskBirdGeneratorApi) FindJob(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	job, err := riskBirdGeneratorService.GetJob(reqId.Uint(), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithData(job, c)
+}
+
+// GetJobList 分页获取造数任务
+// @Tags     RiskBirdGenerator
+// @Summary  分页获取造数任务
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdGeneratorJobSearch                   true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/generator/getJobList [get]
+func (a *RiskBirdGeneratorApi) GetJobList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdGeneratorJobSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdGeneratorService.GetJobList(pageInfo, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// GetItemList 分页获取造数任务的账号清单
+// @Tags     RiskBirdGenerator
+// @Summary  分页获取造数任务的账号清单，包含每个账号的目标值和完成阶段
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdGeneratorItemSearch                  true  "任务ID, 页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/generator/getItemList [get]
+func (a *RiskBirdGeneratorApi) GetItemList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdGeneratorItemSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdGeneratorService.GetItemList(pageInfo, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// ExportManifest 导出造数任务的账号清单
+// @Tags     RiskBirdGenerator
+// @Summary  导出造数任务的账号清单为 CSV，包含手机号和登录密码
+// @Security ApiKeyAuth
+// @Produce  text/csv
+// @Param    data  query     request.GetById  true  "任务ID"
+// @Success  200   {file}    file             "账号清单"
+// @Router   /riskbird/generator/exportManifest [get]
+func (a *RiskBirdGeneratorApi) ExportManifest(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	data, job, err := riskBirdGeneratorService.ExportManifest(reqId.Uint(), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("导出失败!", zap.Error(err))
+		response.FailWithMessage("导出失败:"+err.Error(), c)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=riskbird_generator_%d.csv", job.ID))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
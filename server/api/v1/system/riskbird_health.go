@@ -0,0 +1,82 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdHealthApi struct{}
+
+// CheckEnv 立即检查RiskBird环境
+// @Tags     RiskBirdHealth
+// @Summary  检查环境的数据库连通性、依赖的表字段、固定数据以及用户端和后台接口，返回检查报告
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.CheckRiskBirdHealth                                    true  "环境"
+// @Success  200   {object}  response.Response{data=system.RiskBirdHealthReport,msg=string}  "检查完成"
+// @Router   /riskbird/health/checkEnv [get]
+func (a *RiskBirdHealthApi) CheckEnv(c *gin.Context) {
+	var req systemReq.CheckRiskBirdHealth
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	report, err := riskBirdHealthService.CheckEnv(req.Env, "manual", utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("健康检查失败!", zap.Error(err))
+		response.FailWithMessage("健康检查失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(report, "检查完成", c)
+}
+
+// GetLatestReports 获取各环境最近一次健康检查报告
+// @Tags     RiskBirdHealth
+// @Summary  获取各环境最近一次健康检查报告
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Success  200  {object}  response.Response{data=[]system.RiskBirdHealthReport,msg=string}  "获取成功"
+// @Router   /riskbird/health/getLatestReports [get]
+func (a *RiskBirdHealthApi) GetLatestReports(c *gin.Context) {
+	list, err := riskBirdHealthService.GetLatestReports(utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(list, "获取成功", c)
+}
+
+// GetHealthReportList 分页获取健康检查报告
+// @Tags     RiskBirdHealth
+// @Summary  分页获取健康检查报告
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdHealthReportSearch                   true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/health/getHealthReportList [get]
+func (a *RiskBirdHealthApi) GetHealthReportList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdHealthReportSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdHealthService.GetHealthReportList(pageInfo, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
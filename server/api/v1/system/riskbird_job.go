@@ -0,0 +1,110 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdJobApi struct{}
+
+// GetJobCatalog 获取定时任务接口目录
+// @Tags     RiskBirdJob
+// @Summary  获取指定环境的定时任务接口目录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    env   query     string                                                 false  "RiskBird环境"
+// @Success  200   {object}  response.Response{data=[]config.RiskBirdJob,msg=string}  "获取成功"
+// @Router   /riskbird/job/getJobCatalog [get]
+func (a *RiskBirdJobApi) GetJobCatalog(c *gin.Context) {
+	jobs, err := riskBirdJobService.GetJobCatalog(c.Query("env"))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(jobs, "获取成功", c)
+}
+
+// TriggerJob 触发定时任务
+// @Tags     RiskBirdJob
+// @Summary  触发 RiskBird 定时任务接口
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.TriggerRiskBirdJob                                true  "环境, 任务标识, 请求参数"
+// @Success  200   {object}  response.Response{data=system.RiskBirdJobRecord,msg=string}  "触发成功"
+// @Router   /riskbird/job/triggerJob [post]
+func (a *RiskBirdJobApi) TriggerJob(c *gin.Context) {
+	var req systemReq.TriggerRiskBirdJob
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	record, err := riskBirdJobService.TriggerJob(req, utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("触发定时任务失败!", zap.Error(err))
+		response.FailWithDetailed(record, "触发失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(record, "触发成功", c)
+}
+
+// FindJobRecord 用id查询定时任务调用记录
+// @Tags     RiskBirdJob
+// @Summary  用id查询定时任务调用记录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     request.GetById                                             true  "记录ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdJobRecord,msg=string}  "查询成功"
+// @Router   /riskbird/job/findJobRecord [get]
+func (a *RiskBirdJobApi) FindJobRecord(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	record, err := riskBirdJobService.GetJobRecord(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithData(record, c)
+}
+
+// GetJobRecordList 分页获取定时任务调用记录
+// @Tags     RiskBirdJob
+// @Summary  分页获取定时任务调用记录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdJobRecordSearch                        true  "环境, 任务标识"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/job/getJobRecordList [get]
+func (a *RiskBirdJobApi) GetJobRecordList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdJobRecordSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdJobService.GetJobRecordList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
@@ -0,0 +1,71 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdNotifyApi struct{}
+
+// GetPreference 获取当前用户的通知偏好
+// @Tags     RiskBirdNotify
+// @Summary  获取当前用户的通知偏好
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Success  200  {object}  response.Response{data=system.RiskBirdNotifyPreference,msg=string}  "获取成功"
+// @Router   /riskbird/notify/getPreference [get]
+func (a *RiskBirdNotifyApi) GetPreference(c *gin.Context) {
+	pref, err := riskBirdNotifyService.GetPreference(utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(pref, "获取成功", c)
+}
+
+// SetPreference 设置当前用户的通知偏好
+// @Tags     RiskBirdNotify
+// @Summary  设置当前用户接收的通知事件和渠道
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.SetRiskBirdNotifyPreference                               true  "事件, 邮件, webhook"
+// @Success  200   {object}  response.Response{data=system.RiskBirdNotifyPreference,msg=string}  "设置成功"
+// @Router   /riskbird/notify/setPreference [put]
+func (a *RiskBirdNotifyApi) SetPreference(c *gin.Context) {
+	var req systemReq.SetRiskBirdNotifyPreference
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	pref, err := riskBirdNotifyService.SetPreference(utils.GetUserID(c), req)
+	if err != nil {
+		global.GVA_LOG.Error("设置失败!", zap.Error(err))
+		response.FailWithMessage("设置失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(pref, "设置成功", c)
+}
+
+// SendTestNotification 发送测试通知
+// @Tags     RiskBirdNotify
+// @Summary  按当前用户的通知偏好发送一条测试通知，用于验证邮箱和 webhook 配置
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Success  200  {object}  response.Response{msg=string}  "发送成功"
+// @Router   /riskbird/notify/sendTestNotification [post]
+func (a *RiskBirdNotifyApi) SendTestNotification(c *gin.Context) {
+	err := riskBirdNotifyService.SendTestNotification(utils.GetUserID(c))
+	if err != nil {
+		global.GVA_LOG.Error("发送失败!", zap.Error(err))
+		response.FailWithMessage("发送失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("发送成功", c)
+}
@@ -0,0 +1,67 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdPointAuditApi struct{}
+
+// GetPendingPointList 分页获取待审核的积分获取记录
+// @Tags     RiskBirdPointAudit
+// @Summary  分页获取待审核的积分获取记录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdPendingPointSearch                     true  "环境, 用户ID"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/pointAudit/getPendingPointList [get]
+func (a *RiskBirdPointAuditApi) GetPendingPointList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdPendingPointSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdPointAuditService.GetPendingPointAcquisitions(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// AuditPointAcquisitions 批量审核积分获取记录
+// @Tags     RiskBirdPointAudit
+// @Summary  批量通过或驳回积分获取记录
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.AuditRiskBirdPointAcquisitions       true  "环境, 记录ID或用户ID, 审核结果, 审核类型"
+// @Success  200   {object}  response.Response{data=map[string]int,msg=string}  "审核成功"
+// @Router   /riskbird/pointAudit/auditPointAcquisitions [post]
+func (a *RiskBirdPointAuditApi) AuditPointAcquisitions(c *gin.Context) {
+	var req systemReq.AuditRiskBirdPointAcquisitions
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	audited, err := riskBirdPointAuditService.AuditPointAcquisitions(req)
+	if err != nil {
+		global.GVA_LOG.Error("积分审核失败!", zap.Error(err))
+		response.FailWithDetailed(gin.H{"audited": audited}, "审核失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(gin.H{"audited": audited}, "审核成功", c)
+}
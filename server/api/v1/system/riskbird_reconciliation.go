@@ -0,0 +1,67 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdReconciliationApi struct{}
+
+// ReconcileAccounts 核对账号库中账号的余额和积分
+// @Tags     RiskBirdReconciliation
+// @Summary  按数据库计算账号的总余额和可用积分，与接口返回值比较，返回每个账号的核对结果
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.ReconcileRiskBirdAccounts                                true  "账号库ID, 或环境和标签"
+// @Success  200   {object}  response.Response{data=[]system.RiskBirdReconciliation,msg=string}  "核对完成"
+// @Router   /riskbird/reconcile/reconcileAccounts [post]
+func (a *RiskBirdReconciliationApi) ReconcileAccounts(c *gin.Context) {
+	var req systemReq.ReconcileRiskBirdAccounts
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	list, err := riskBirdReconciliationService.ReconcileAccounts(req, "manual")
+	if err != nil {
+		global.GVA_LOG.Error("账务核对失败!", zap.Error(err))
+		response.FailWithMessage("账务核对失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(list, "核对完成", c)
+}
+
+// GetReconciliationList 分页获取账务核对结果
+// @Tags     RiskBirdReconciliation
+// @Summary  分页获取账务核对结果
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdReconciliationSearch                 true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/reconcile/getReconciliationList [get]
+func (a *RiskBirdReconciliationApi) GetReconciliationList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdReconciliationSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdReconciliationService.GetReconciliationList(pageInfo, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
@@ -0,0 +1,201 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdResetScheduleApi struct{}
+
+// CreateResetSchedule 创建账号重置计划
+// @Tags     RiskBirdResetSchedule
+// @Summary  创建账号重置计划
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      system.RiskBirdResetSchedule   true  "计划名称, cron表达式, 账号列表, 基准余额和积分"
+// @Success  200   {object}  response.Response{msg=string}  "创建成功"
+// @Router   /riskbird/resetSchedule/createResetSchedule [post]
+func (a *RiskBirdResetScheduleApi) CreateResetSchedule(c *gin.Context) {
+	var schedule system.RiskBirdResetSchedule
+	err := c.ShouldBindJSON(&schedule)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	schedule.AuthorityId = utils.GetUserAuthorityId(c)
+	err = riskBirdResetScheduleService.CreateResetSchedule(&schedule)
+	if err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("创建成功", c)
+}
+
+// DeleteResetSchedule 删除账号重置计划
+// @Tags     RiskBirdResetSchedule
+// @Summary  删除账号重置计划
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                true  "计划ID"
+// @Success  200   {object}  response.Response{msg=string}  "删除成功"
+// @Router   /riskbird/resetSchedule/deleteResetSchedule [delete]
+func (a *RiskBirdResetScheduleApi) DeleteResetSchedule(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdResetScheduleService.DeleteResetSchedule(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("删除失败!", zap.Error(err))
+		response.FailWithMessage("删除失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("删除成功", c)
+}
+
+// UpdateResetSchedule 更新账号重置计划
+// @Tags     RiskBirdResetSchedule
+// @Summary  更新账号重置计划
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      system.RiskBirdResetSchedule   true  "计划名称, cron表达式, 账号列表, 基准余额和积分"
+// @Success  200   {object}  response.Response{msg=string}  "更新成功"
+// @Router   /riskbird/resetSchedule/updateResetSchedule [put]
+func (a *RiskBirdResetScheduleApi) UpdateResetSchedule(c *gin.Context) {
+	var schedule system.RiskBirdResetSchedule
+	err := c.ShouldBindJSON(&schedule)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	schedule.AuthorityId = utils.GetUserAuthorityId(c)
+	err = riskBirdResetScheduleService.UpdateResetSchedule(schedule)
+	if err != nil {
+		global.GVA_LOG.Error("更新失败!", zap.Error(err))
+		response.FailWithMessage("更新失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("更新成功", c)
+}
+
+// FindResetSchedule 用id查询账号重置计划
+// @Tags     RiskBirdResetSchedule
+// @Summary  用id查询账号重置计划
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  query     request.GetById                                                 true  "计划ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdResetSchedule,msg=string}  "查询成功"
+// @Router   /riskbird/resetSchedule/findResetSchedule [get]
+func (a *RiskBirdResetScheduleApi) FindResetSchedule(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindQuery(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	schedule, err := riskBirdResetScheduleService.GetResetSchedule(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("查询失败!", zap.Error(err))
+		response.FailWithMessage("查询失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithData(schedule, c)
+}
+
+// GetResetScheduleList 分页获取账号重置计划列表
+// @Tags     RiskBirdResetSchedule
+// @Summary  分页获取账号重置计划列表
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdResetScheduleSearch                true  "分页获取账号重置计划列表"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/resetSchedule/getResetScheduleList [get]
+func (a *RiskBirdResetScheduleApi) GetResetScheduleList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdResetScheduleSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdResetScheduleService.GetResetScheduleList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// RunResetSchedule 立即执行账号重置计划
+// @Tags     RiskBirdResetSchedule
+// @Summary  立即执行账号重置计划
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                                            true  "计划ID"
+// @Success  200   {object}  response.Response{data=system.RiskBirdResetRun,msg=string}  "执行完成"
+// @Router   /riskbird/resetSchedule/runResetSchedule [post]
+func (a *RiskBirdResetScheduleApi) RunResetSchedule(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	run, err := riskBirdResetScheduleService.RunResetSchedule(reqId.Uint(), "manual", utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("执行失败!", zap.Error(err))
+		response.FailWithMessage("执行失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(run, "执行完成", c)
+}
+
+// GetResetRunList 分页获取账号重置执行记录
+// @Tags     RiskBirdResetSchedule
+// @Summary  分页获取账号重置执行记录
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdResetRunSearch                     true  "计划ID, 执行结果"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/resetSchedule/getResetRunList [get]
+func (a *RiskBirdResetScheduleApi) GetResetRunList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdResetRunSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdResetScheduleService.GetResetRunList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
@@ -0,0 +1,77 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetRiskBirdScopes
+// @Tags      Authority
+// @Summary   设置角色可使用的RiskBird环境及各环境允许的操作，scopes为空表示不限制
+// @Security  ApiKeyAuth
+// @accept    application/json
+// @Produce   application/json
+// @Param     data  body      systemReq.SetRiskBirdScopes    true  "角色ID, 各环境允许的操作"
+// @Success   200   {object}  response.Response{msg=string}  "设置成功"
+// @Router    /authority/setRiskBirdScopes [post]
+func (a *AuthorityApi) SetRiskBirdScopes(c *gin.Context) {
+	var req systemReq.SetRiskBirdScopes
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = riskBirdScopeService.SetAuthorityScopes(utils.GetUserAuthorityId(c), req)
+	if err != nil {
+		global.GVA_LOG.Error("设置失败!", zap.Error(err))
+		response.FailWithMessage("设置失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("设置成功", c)
+}
+
+// GetRiskBirdScopes
+// @Tags      Authority
+// @Summary   获取角色已配置的RiskBird环境权限，为空表示不限制
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Param     data  query     request.GetAuthorityId                                                   true  "角色ID"
+// @Success   200   {object}  response.Response{data=[]system.RiskBirdAuthorityScope,msg=string}  "获取成功"
+// @Router    /authority/getRiskBirdScopes [get]
+func (a *AuthorityApi) GetRiskBirdScopes(c *gin.Context) {
+	var req request.GetAuthorityId
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	scopes, err := riskBirdScopeService.GetAuthorityScopes(req.AuthorityId)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(scopes, "获取成功", c)
+}
+
+// GetRiskBirdAllowedEnvs
+// @Tags      Authority
+// @Summary   获取当前用户可使用的RiskBird环境及各环境允许的操作
+// @Security  ApiKeyAuth
+// @Produce   application/json
+// @Success   200  {object}  response.Response{data=[]system.RiskBirdAuthorityScope,msg=string}  "获取成功"
+// @Router    /authority/getRiskBirdAllowedEnvs [get]
+func (a *AuthorityApi) GetRiskBirdAllowedEnvs(c *gin.Context) {
+	envs, err := riskBirdScopeService.GetAllowedEnvs(utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(envs, "获取成功", c)
+}
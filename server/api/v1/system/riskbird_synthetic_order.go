@@ -0,0 +1,69 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RiskBirdSyntheticOrderApi struct{}
+
+// GetSyntheticOrderList 分页获取合成订单
+// @Tags     RiskBirdSyntheticOrder
+// @Summary  分页获取余额、积分流程创建的合成订单
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.RiskBirdSyntheticOrderSearch                  true  "环境, 手机号, 订单类型, 状态"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /riskbird/syntheticOrder/getSyntheticOrderList [get]
+func (a *RiskBirdSyntheticOrderApi) GetSyntheticOrderList(c *gin.Context) {
+	var pageInfo systemReq.RiskBirdSyntheticOrderSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := riskBirdSyntheticOrderService.GetSyntheticOrderList(pageInfo, utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// CleanupOrders 清理合成订单
+// @Tags     RiskBirdSyntheticOrder
+// @Summary  在 RiskBird 数据库中隐藏、取消或删除合成订单
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.CleanupRiskBirdOrders                                        true  "环境, 手机号, 记录ID, 清理方式"
+// @Success  200   {object}  response.Response{data=systemRes.RiskBirdOrderCleanupResult,msg=string}  "清理成功"
+// @Router   /riskbird/syntheticOrder/cleanupOrders [post]
+func (a *RiskBirdSyntheticOrderApi) CleanupOrders(c *gin.Context) {
+	var req systemReq.CleanupRiskBirdOrders
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
+	var result systemRes.RiskBirdOrderCleanupResult
+	result, err = riskBirdSyntheticOrderService.CleanupOrders(req)
+	if err != nil {
+		global.GVA_LOG.Error("清理失败!", zap.Error(err))
+		response.FailWithDetailed(result, "清理失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(result, "清理成功", c)
+}
@@ -0,0 +1,123 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ApiKeyApi struct{}
+
+// CreateApiKey 创建API密钥
+// @Tags     SysApiKey
+// @Summary  为服务账号创建API密钥，明文密钥只返回一次
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      systemReq.CreateApiKey                                        true  "密钥名称, 服务账号ID, 角色ID, 过期时间"
+// @Success  200   {object}  response.Response{data=systemRes.SysApiKeyResponse,msg=string}  "创建成功"
+// @Router   /apiKey/createApiKey [post]
+func (a *ApiKeyApi) CreateApiKey(c *gin.Context) {
+	var req systemReq.CreateApiKey
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	var res systemRes.SysApiKeyResponse
+	res, err = apiKeyService.CreateApiKey(req, utils.GetUserID(c), utils.GetUserAuthorityId(c))
+	if err != nil {
+		global.GVA_LOG.Error("创建失败!", zap.Error(err))
+		response.FailWithMessage("创建失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(res, "创建成功，请妥善保存密钥，关闭后将无法再次查看", c)
+}
+
+// RevokeApiKey 吊销API密钥
+// @Tags     SysApiKey
+// @Summary  吊销API密钥
+// @Security ApiKeyAuth
+// @accept   application/json
+// @Produce  application/json
+// @Param    data  body      request.GetById                 true  "密钥ID"
+// @Success  200   {object}  response.Response{msg=string}  "吊销成功"
+// @Router   /apiKey/revokeApiKey [post]
+func (a *ApiKeyApi) RevokeApiKey(c *gin.Context) {
+	var reqId request.GetById
+	err := c.ShouldBindJSON(&reqId)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	err = apiKeyService.RevokeApiKey(reqId.Uint())
+	if err != nil {
+		global.GVA_LOG.Error("吊销失败!", zap.Error(err))
+		response.FailWithMessage("吊销失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("吊销成功", c)
+}
+
+// GetApiKeyList 分页获取API密钥
+// @Tags     SysApiKey
+// @Summary  分页获取API密钥
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.SysApiKeySearch                              true  "页码, 每页大小, 搜索条件"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /apiKey/getApiKeyList [get]
+func (a *ApiKeyApi) GetApiKeyList(c *gin.Context) {
+	var pageInfo systemReq.SysApiKeySearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := apiKeyService.GetApiKeyList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
+
+// GetApiKeyUsageList 分页获取API密钥调用记录
+// @Tags     SysApiKey
+// @Summary  分页获取API密钥调用记录
+// @Security ApiKeyAuth
+// @Produce  application/json
+// @Param    data  query     systemReq.SysApiKeyUsageSearch                         true  "密钥ID, 页码, 每页大小"
+// @Success  200   {object}  response.Response{data=response.PageResult,msg=string}  "获取成功"
+// @Router   /apiKey/getApiKeyUsageList [get]
+func (a *ApiKeyApi) GetApiKeyUsageList(c *gin.Context) {
+	var pageInfo systemReq.SysApiKeyUsageSearch
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	list, total, err := apiKeyService.GetApiKeyUsageList(pageInfo)
+	if err != nil {
+		global.GVA_LOG.Error("获取失败!", zap.Error(err))
+		response.FailWithMessage("获取失败:"+err.Error(), c)
+		return
+	}
+	response.OkWithDetailed(response.PageResult{
+		List:     list,
+		Total:    total,
+		Page:     pageInfo.Page,
+		PageSize: pageInfo.PageSize,
+	}, "获取成功", c)
+}
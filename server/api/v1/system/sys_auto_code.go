@@ -1,7 +1,11 @@
 package system
 
 import (
+	"slices"
+
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
@@ -17,6 +21,7 @@ type AutoCodeApi struct{}
 // @Security  ApiKeyAuth
 // @accept    application/json
 // @Produce   application/json
+// @Param     readOnly  query     bool  false  "是否附带只读的RiskBird业务库，导出模板使用"
 // @Success   200  {object}  response.Response{data=map[string]interface{},msg=string}  "获取当前所有数据库"
 // @Router    /autoCode/getDB [get]
 func (autoApi *AutoCodeApi) GetDB(c *gin.Context) {
@@ -31,6 +36,26 @@ func (autoApi *AutoCodeApi) GetDB(c *gin.Context) {
 		item["dbtype"] = db.Type
 		dbList = append(dbList, item)
 	}
+	// RiskBird 各环境业务库只读注册，仅导出模板通过 readOnly=true 选择，且只列出当前角色可以导出的环境
+	if c.Query("readOnly") == "true" {
+		allowed, scopeErr := riskBirdScopeService.GetAllowedEnvs(utils.GetUserAuthorityId(c))
+		if scopeErr != nil {
+			err = scopeErr
+		}
+		for _, scope := range allowed {
+			env, _ := global.GVA_CONFIG.RiskBird.Env(scope.Env)
+			if !slices.Contains(scope.Operations, system.RiskBirdOperationExport) || global.GetGlobalDBByDBName(env.DBAliasName()) == nil {
+				continue
+			}
+			dbList = append(dbList, map[string]interface{}{
+				"aliasName": env.DBAliasName(),
+				"dbName":    env.DB.Database,
+				"disable":   false,
+				"dbtype":    "mysql",
+				"readOnly":  true,
+			})
+		}
+	}
 	if err != nil {
 		global.GVA_LOG.Error("获取失败!", zap.Error(err))
 		response.FailWithMessage("获取失败", c)
@@ -58,6 +83,9 @@ func (autoApi *AutoCodeApi) GetTables(c *gin.Context) {
 					dbName = db.Dbname
 				}
 			}
+			if env, ok := global.GVA_CONFIG.RiskBird.EnvByDBAlias(businessDB); ok {
+				dbName = env.DB.Database
+			}
 		}
 	}
 
@@ -89,6 +117,9 @@ func (autoApi *AutoCodeApi) GetColumn(c *gin.Context) {
 					dbName = db.Dbname
 				}
 			}
+			if env, ok := global.GVA_CONFIG.RiskBird.EnvByDBAlias(businessDB); ok {
+				dbName = env.DB.Database
+			}
 		}
 	}
 	tableName := c.Query("tableName")
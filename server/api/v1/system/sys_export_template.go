@@ -254,6 +254,12 @@ func (sysExportTemplateApi *SysExportTemplateApi) ExportExcel(c *gin.Context) {
 		response.FailWithMessage("模板ID不能为空", c)
 		return
 	}
+	// 一次性链接不再鉴权，生成链接前校验 RiskBird 业务库的环境权限
+	if err := sysExportTemplateService.CheckRiskBirdExportScope(templateID, utils.GetUserAuthorityId(c)); err != nil {
+		global.GVA_LOG.Error("导出失败!", zap.Error(err))
+		response.FailWithMessage("导出失败:"+err.Error(), c)
+		return
+	}
 
 	queryParams := c.Request.URL.Query()
 
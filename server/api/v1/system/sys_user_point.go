@@ -5,6 +5,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
 	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -15,8 +16,8 @@ type UserPointApi struct{}
 // @Tags     UserPoint
 // @Summary  修改用户积分
 // @Produce   application/json
-// @Param    data  body      systemReq.ModifyUserPoint                      true  "手机号, 密码, 修改积分"
-// @Success  200   {object}  response.Response{msg=string}                  "修改用户积分成功"
+// @Param    data  body      systemReq.ModifyUserPoint                      true  "手机号, 密码或验证码登录, 修改积分, 修改方式, 是否预演"
+// @Success  200   {object}  response.Response{data=systemRes.RiskBirdFlowPlan,msg=string}  "修改用户积分成功，预演时返回将要执行的步骤"
 // @Router   /riskbird/user/modifyUserPoint [post]
 func (u *UserPointApi) ModifyUserPoint(c *gin.Context) {
 	var req systemReq.ModifyUserPoint
@@ -26,27 +27,46 @@ func (u *UserPointApi) ModifyUserPoint(c *gin.Context) {
 		return
 	}
 
-	// 验证必填字段
-	if req.Phone == "" {
-		response.FailWithMessage("用户手机号不能为空", c)
-		return
-	}
-	if req.Password == "" {
-		response.FailWithMessage("用户密码不能为空", c)
-		return
+	// 验证必填字段，代登录和验证码登录时不需要密码
+	if req.Impersonate {
+		if req.Phone == "" && req.TargetUserID == 0 {
+			response.FailWithMessage("代登录需要指定用户ID或手机号", c)
+			return
+		}
+	} else {
+		if req.Phone == "" {
+			response.FailWithMessage("用户手机号不能为空", c)
+			return
+		}
+		if req.Password == "" && !req.SmsLogin {
+			response.FailWithMessage("用户密码不能为空", c)
+			return
+		}
 	}
+	req.OperatorID = utils.GetUserID(c)
+	req.OperatorAuthorityID = utils.GetUserAuthorityId(c)
 
 	// 验证积分
 	if req.PointAmount < 0 {
 		response.FailWithMessage("积分不能为负数", c)
 		return
 	}
-	if req.PointAmount%5 != 0 {
+	if req.Strategy != systemReq.ModifyUserPointStrategyDirect && req.PointAmount%5 != 0 {
 		response.FailWithMessage("积分必须是5的倍数", c)
 		return
 	}
 
 	userPointService := service.ServiceGroupApp.SystemServiceGroup.UserPointService
+	if req.DryRun {
+		plan, err := userPointService.PlanModifyUserPoint(req)
+		if err != nil {
+			global.GVA_LOG.Error("预演修改用户积分失败", zap.Error(err))
+			response.FailWithMessage(err.Error(), c)
+			return
+		}
+		response.OkWithDetailed(plan, "预演完成，未修改任何数据", c)
+		return
+	}
 	err = userPointService.ModifyUserPoint(req)
 	if err != nil {
 		global.GVA_LOG.Error("修改用户积分失败", zap.Error(err))
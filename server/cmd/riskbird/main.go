@@ -0,0 +1,326 @@
+// riskbird 命令行工具，复用服务端的配置、RiskBird 客户端和业务逻辑，便于在脚本和 CI 中造数
+//
+// 用法:
+//
+//	riskbird [-c config.yaml] <command> [flags]
+//
+// 命令:
+//
+//	balance   修改用户余额
+//	points    修改用户积分
+//	inspect   查询用户余额和积分
+//	scenario  执行账号重置计划
+//
+// 指定 -json 时结果以 JSON 输出到标准输出，日志和配置提示输出到标准错误，失败时退出码为 1。
+// 用户密码通过环境变量 RISKBIRD_PASSWORD 或 -password-stdin 从标准输入读取，避免出现在进程列表和 shell 历史中
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/core"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/initialize"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"go.uber.org/zap"
+)
+
+var systemService = service.ServiceGroupApp.SystemServiceGroup
+
+// passwordEnv 读取用户密码的环境变量
+const passwordEnv = "RISKBIRD_PASSWORD"
+
+// command 子命令，run 返回的结果在 -json 模式下原样输出
+type command struct {
+	usage string
+	run   func(args []string) (result interface{}, jsonOutput bool, err error)
+}
+
+var commands = map[string]command{
+	"balance":  {usage: "修改用户余额", run: runBalance},
+	"points":   {usage: "修改用户积分", run: runPoints},
+	"inspect":  {usage: "查询用户余额和积分", run: runInspect},
+	"scenario": {usage: "执行账号重置计划", run: runScenario},
+}
+
+// cliResult -json 模式下的输出结构
+type cliResult struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+func main() {
+	// 配置提示和控制台日志写到标准错误，保证标准输出只有命令结果
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	initializeCli()
+	os.Stdout = stdout
+
+	args := flag.Args()
+	if len(args) == 0 {
+		printUsage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n", args[0])
+		printUsage()
+		os.Exit(2)
+	}
+	result, jsonOutput, err := cmd.run(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if jsonOutput {
+		output := cliResult{Success: err == nil, Data: result}
+		if err != nil {
+			output.Error = err.Error()
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(output)
+	} else if err == nil {
+		printText(result)
+	} else {
+		fmt.Fprintln(os.Stderr, "执行失败:", err)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// initializeCli 只初始化命令需要的组件，不启动定时任务和 HTTP 服务
+func initializeCli() {
+	global.GVA_VP = core.Viper()
+	initialize.OtherInit()
+	global.GVA_LOG = core.Zap()
+	zap.ReplaceGlobals(global.GVA_LOG)
+	// 未配置管理后台数据库时仍可按手机号操作，账号库和重置计划相关命令不可用
+	global.GVA_DB = initialize.Gorm()
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "用法: riskbird [-c config.yaml] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "命令:")
+	for _, name := range []string{"balance", "points", "inspect", "scenario"} {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "使用 riskbird <command> -h 查看命令参数")
+}
+
+func printText(result interface{}) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Println(result)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// userFlags 各命令共用的用户定位参数，-account 与 -phone 加密码二选一，没有密码的账号使用 -phone 和 -sms
+type userFlags struct {
+	env           string
+	phone         string
+	password      string
+	passwordStdin bool
+	sms           bool
+	accountID     uint
+	json          bool
+}
+
+func newFlagSet(name string, u *userFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.BoolVar(&u.json, "json", false, "以 JSON 输出结果")
+	return fs
+}
+
+func (u *userFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&u.env, "env", "", "RiskBird环境，为空时使用默认环境")
+	fs.StringVar(&u.phone, "phone", "", "用户手机号")
+	fs.StringVar(&u.password, "password", "", "用户密码，会出现在进程列表中，建议改用 "+passwordEnv+" 或 -password-stdin")
+	fs.BoolVar(&u.passwordStdin, "password-stdin", false, "从标准输入读取用户密码")
+	fs.BoolVar(&u.sms, "sms", false, "使用短信验证码登录，用于没有密码的账号")
+	fs.UintVar(&u.accountID, "account", 0, "账号库中的账号ID，指定后忽略 -env/-phone/-password")
+}
+
+func (u *userFlags) validate() error {
+	if u.accountID == 0 && !u.sms {
+		if err := u.readPassword(os.Stdin); err != nil {
+			return err
+		}
+	}
+	if u.accountID == 0 && (u.phone == "" || u.password == "" && !u.sms) {
+		return errors.New("请指定 -account 或 -phone 和密码（" + passwordEnv + "、-password-stdin 或 -password）/-sms")
+	}
+	if u.accountID != 0 && global.GVA_DB == nil {
+		return errors.New("未配置管理后台数据库，无法读取账号库")
+	}
+	return nil
+}
+
+// readPassword 按 -password-stdin、环境变量 RISKBIRD_PASSWORD、-password 的顺序确定用户密码
+func (u *userFlags) readPassword(stdin io.Reader) error {
+	if u.passwordStdin {
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("读取标准输入中的密码失败: %w", err)
+		}
+		u.password = strings.TrimRight(line, "\r\n")
+		return nil
+	}
+	if password := os.Getenv(passwordEnv); password != "" {
+		u.password = password
+	}
+	return nil
+}
+
+// inspect 查询操作后的余额和积分作为命令结果，密码为空时使用短信验证码登录
+func (u *userFlags) inspect() (interface{}, error) {
+	if u.accountID != 0 {
+		return systemService.RiskBirdAccountService.GetAccountOverview(u.accountID)
+	}
+	password := u.password
+	if u.sms {
+		password = ""
+	}
+	return systemService.RiskBirdAccountService.InspectUser(u.env, u.phone, password)
+}
+
+func runBalance(args []string) (interface{}, bool, error) {
+	var u userFlags
+	var recharge, gift string
+	fs := newFlagSet("balance", &u)
+	u.register(fs)
+	fs.StringVar(&recharge, "recharge", "0", "修改后的充值金额（最多小数点后2位）")
+	fs.StringVar(&gift, "gift", "0", "修改后的赠送金额（最多小数点后2位）")
+	if err := fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+	if err := u.validate(); err != nil {
+		return nil, u.json, err
+	}
+	rechargeAmount, err := common.ParseMoney(recharge)
+	if err != nil {
+		return nil, u.json, fmt.Errorf("充值金额格式错误: %w", err)
+	}
+	giftAmount, err := common.ParseMoney(gift)
+	if err != nil {
+		return nil, u.json, fmt.Errorf("赠送金额格式错误: %w", err)
+	}
+
+	if u.accountID != 0 {
+		err = systemService.UserBalanceService.ModifyAccountBalance(systemReq.ModifyRiskBirdAccountBalance{
+			AccountID:      u.accountID,
+			RechargeAmount: rechargeAmount,
+			GiftAmount:     giftAmount,
+
+			OperatorAuthorityID: system.RiskBirdInternalAuthorityID,
+		})
+	} else {
+		err = systemService.UserBalanceService.ModifyUserBalance(systemReq.ModifyUserBalance{
+			Env:            u.env,
+			Phone:          u.phone,
+			Password:       u.password,
+			SmsLogin:       u.sms,
+			RechargeAmount: rechargeAmount,
+			GiftAmount:     giftAmount,
+
+			OperatorAuthorityID: system.RiskBirdInternalAuthorityID,
+		})
+	}
+	if err != nil {
+		return nil, u.json, err
+	}
+	result, err := u.inspect()
+	return result, u.json, err
+}
+
+func runPoints(args []string) (interface{}, bool, error) {
+	var u userFlags
+	var points int64
+	var strategy string
+	fs := newFlagSet("points", &u)
+	u.register(fs)
+	fs.Int64Var(&points, "points", 0, "修改后的积分")
+	fs.StringVar(&strategy, "strategy", systemReq.ModifyUserPointStrategyOrder, "修改方式 order下单 direct直接修改积分记录")
+	if err := fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+	if err := u.validate(); err != nil {
+		return nil, u.json, err
+	}
+
+	var err error
+	if u.accountID != 0 {
+		err = systemService.UserPointService.ModifyAccountPoint(systemReq.ModifyRiskBirdAccountPoint{
+			AccountID:   u.accountID,
+			PointAmount: points,
+			Strategy:    strategy,
+
+			OperatorAuthorityID: system.RiskBirdInternalAuthorityID,
+		})
+	} else {
+		err = systemService.UserPointService.ModifyUserPoint(systemReq.ModifyUserPoint{
+			Env:         u.env,
+			Phone:       u.phone,
+			Password:    u.password,
+			SmsLogin:    u.sms,
+			PointAmount: points,
+			Strategy:    strategy,
+
+			OperatorAuthorityID: system.RiskBirdInternalAuthorityID,
+		})
+	}
+	if err != nil {
+		return nil, u.json, err
+	}
+	result, err := u.inspect()
+	return result, u.json, err
+}
+
+func runInspect(args []string) (interface{}, bool, error) {
+	var u userFlags
+	fs := newFlagSet("inspect", &u)
+	u.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+	if err := u.validate(); err != nil {
+		return nil, u.json, err
+	}
+	result, err := u.inspect()
+	return result, u.json, err
+}
+
+func runScenario(args []string) (interface{}, bool, error) {
+	var u userFlags
+	var id uint
+	fs := newFlagSet("scenario", &u)
+	fs.UintVar(&id, "id", 0, "账号重置计划ID")
+	if err := fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+	if id == 0 {
+		return nil, u.json, errors.New("请指定 -id")
+	}
+	if global.GVA_DB == nil {
+		return nil, u.json, errors.New("未配置管理后台数据库，无法读取重置计划")
+	}
+	run, err := systemService.RiskBirdResetScheduleService.RunResetSchedule(id, "cli", system.RiskBirdInternalAuthorityID)
+	// 部分账号重置失败时同样以失败退出，便于脚本判断
+	if err == nil && run.Status != system.RiskBirdResetStatusSuccess {
+		err = fmt.Errorf("重置计划执行结果: %s", run.Status)
+	}
+	return run, u.json, err
+}
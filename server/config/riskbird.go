@@ -1,8 +1,30 @@
 package config
 
+import "strings"
+
+// RiskBirdDefaultEnv 默认环境名称，对应 riskbird 下直接配置的 db 和 api
+const RiskBirdDefaultEnv = "default"
+
+// RiskBirdDBAliasPrefix 各环境数据库以只读方式注册到多数据库列表时的名称前缀
+const RiskBirdDBAliasPrefix = "riskbird-"
+
 type RiskBird struct {
-	DB  RiskBirdDB  `mapstructure:"db" json:"db" yaml:"db"`
-	API RiskBirdAPI `mapstructure:"api" json:"api" yaml:"api"`
+	DB   RiskBirdDB    `mapstructure:"db" json:"db" yaml:"db"`
+	API  RiskBirdAPI   `mapstructure:"api" json:"api" yaml:"api"`
+	SMS  RiskBirdSMS   `mapstructure:"sms" json:"sms" yaml:"sms"`    // 默认环境的短信验证码配置
+	Jobs []RiskBirdJob `mapstructure:"jobs" json:"jobs" yaml:"jobs"` // 默认环境的定时任务接口目录
+	Envs []RiskBirdEnv `mapstructure:"envs" json:"envs" yaml:"envs"` // 其他环境
+
+	Impersonation RiskBirdImpersonation `mapstructure:"impersonation" json:"impersonation" yaml:"impersonation"` // 默认环境的代登录配置
+
+	OrderTables  RiskBirdOrderTables  `mapstructure:"order-tables" json:"order-tables" yaml:"order-tables"`    // 订单相关表结构
+	BalanceTable RiskBirdBalanceTable `mapstructure:"balance-table" json:"balance-table" yaml:"balance-table"` // 余额账户表结构，用于账务核对
+
+	HealthCheckSpec string             `mapstructure:"health-check-spec" json:"health-check-spec" yaml:"health-check-spec"` // 环境健康检查的cron表达式，默认每10分钟一次，设为 off 时关闭
+	ReconcileSpec   string             `mapstructure:"reconcile-spec" json:"reconcile-spec" yaml:"reconcile-spec"`          // 账号库账务核对的cron表达式，默认每天2点，设为 off 时关闭
+	Resilience      RiskBirdResilience `mapstructure:"resilience" json:"resilience" yaml:"resilience"`                      // 接口重试和熔断策略，对全部环境生效
+	Notify          RiskBirdNotify     `mapstructure:"notify" json:"notify" yaml:"notify"`                                  // 任务完成通知
+	SecretKey       string             `mapstructure:"secret-key" json:"secret-key" yaml:"secret-key"`                      // 加密账号库和造数任务中保存的登录密码，未配置时只能保存无密码的验证码登录账号
 }
 
 type RiskBirdDB struct {
@@ -14,5 +36,151 @@ type RiskBirdDB struct {
 }
 
 type RiskBirdAPI struct {
-	BaseUrl string `mapstructure:"base-url" json:"base-url" yaml:"base-url"`
+	BaseUrl       string `mapstructure:"base-url" json:"base-url" yaml:"base-url"`
+	AdminBaseUrl  string `mapstructure:"admin-base-url" json:"admin-base-url" yaml:"admin-base-url"` // 后台管理系统接口地址
+	AdminUsername string `mapstructure:"admin-username" json:"admin-username" yaml:"admin-username"` // 后台管理员账号
+	AdminPassword string `mapstructure:"admin-password" json:"admin-password" yaml:"admin-password"` // 后台管理员密码
+}
+
+// RiskBirdOrderTables 清理合成订单时使用的 RiskBird 订单表结构，未配置的字段使用默认值
+type RiskBirdOrderTables struct {
+	OrderTable    string `mapstructure:"order-table" json:"order-table" yaml:"order-table"`             // 订单表，默认 p_order
+	PreOrderTable string `mapstructure:"pre-order-table" json:"pre-order-table" yaml:"pre-order-table"` // 预订单表，默认 p_pre_order
+	OrderNoColumn string `mapstructure:"order-no-column" json:"order-no-column" yaml:"order-no-column"` // 订单号字段，默认 order_no
+	HiddenColumn  string `mapstructure:"hidden-column" json:"hidden-column" yaml:"hidden-column"`       // 隐藏标记字段，默认 is_deleted
+	StatusColumn  string `mapstructure:"status-column" json:"status-column" yaml:"status-column"`       // 订单状态字段，默认 status
+	CancelStatus  string `mapstructure:"cancel-status" json:"cancel-status" yaml:"cancel-status"`       // 取消状态值，默认 cancel
+	UserColumn    string `mapstructure:"user-column" json:"user-column" yaml:"user-column"`             // 用户ID字段，默认 user_id，用于下单重复检测
+	TimeColumn    string `mapstructure:"time-column" json:"time-column" yaml:"time-column"`             // 创建时间字段，默认 create_time，用于下单重复检测
+}
+
+// RiskBirdBalanceTable 账务核对时读取的余额账户表结构，未配置的字段使用默认值
+type RiskBirdBalanceTable struct {
+	Table          string   `mapstructure:"table" json:"table" yaml:"table"`                               // 余额账户表，默认 recharge_account
+	UserColumn     string   `mapstructure:"user-column" json:"user-column" yaml:"user-column"`             // 用户ID字段，默认 user_id
+	BalanceColumns []string `mapstructure:"balance-columns" json:"balance-columns" yaml:"balance-columns"` // 计入总余额的字段，默认 balance 和 gift_balance
+}
+
+// RiskBirdNotify 流程、造数、重置和核对任务完成时的通知配置，用户在通知偏好中选择接收的事件和渠道
+type RiskBirdNotify struct {
+	SiteURL        string `mapstructure:"site-url" json:"site-url" yaml:"site-url"`                      // 管理后台访问地址，用于生成详情链接，如 https://admin.example.com
+	WebhookURL     string `mapstructure:"webhook-url" json:"webhook-url" yaml:"webhook-url"`             // 外发 webhook 地址，为空时不发送
+	WebhookSecret  string `mapstructure:"webhook-secret" json:"webhook-secret" yaml:"webhook-secret"`    // 配置后使用 HMAC-SHA256 对请求体签名，放在 X-RiskBird-Signature 请求头
+	WebhookTimeout int    `mapstructure:"webhook-timeout" json:"webhook-timeout" yaml:"webhook-timeout"` // webhook 超时时间（秒），默认 5
+}
+
+// RiskBirdResilience RiskBird 接口重试和熔断策略，未配置的字段使用默认值
+type RiskBirdResilience struct {
+	Retries map[string]RiskBirdRetryPolicy `mapstructure:"retries" json:"retries" yaml:"retries"` // 按接口配置重试策略，键为 login/balance/point-overview/admin-login；job 默认不重试，确认任务幂等后才配置
+	Breaker RiskBirdBreakerPolicy          `mapstructure:"breaker" json:"breaker" yaml:"breaker"` // 每个环境一个熔断器
+}
+
+// RiskBirdRetryPolicy 重试策略，只对幂等接口生效，每次重试的等待时间翻倍
+type RiskBirdRetryPolicy struct {
+	MaxAttempts int `mapstructure:"max-attempts" json:"max-attempts" yaml:"max-attempts"` // 最多调用次数，1 表示不重试
+	Backoff     int `mapstructure:"backoff" json:"backoff" yaml:"backoff"`                // 首次重试等待时间（毫秒）
+	MaxBackoff  int `mapstructure:"max-backoff" json:"max-backoff" yaml:"max-backoff"`    // 最长等待时间（毫秒）
+}
+
+// RiskBirdBreakerPolicy 熔断策略，连续失败达到阈值后熔断，熔断期过后放行一次试探调用
+type RiskBirdBreakerPolicy struct {
+	FailureThreshold int `mapstructure:"failure-threshold" json:"failure-threshold" yaml:"failure-threshold"` // 连续失败次数阈值，默认 5
+	OpenSeconds      int `mapstructure:"open-seconds" json:"open-seconds" yaml:"open-seconds"`                // 熔断时长（秒），默认 30
+}
+
+// RiskBirdEnv RiskBird 环境配置
+type RiskBirdEnv struct {
+	Name string        `mapstructure:"name" json:"name" yaml:"name"`
+	DB   RiskBirdDB    `mapstructure:"db" json:"db" yaml:"db"`
+	API  RiskBirdAPI   `mapstructure:"api" json:"api" yaml:"api"`
+	SMS  RiskBirdSMS   `mapstructure:"sms" json:"sms" yaml:"sms"`
+	Jobs []RiskBirdJob `mapstructure:"jobs" json:"jobs" yaml:"jobs"`
+
+	Impersonation RiskBirdImpersonation `mapstructure:"impersonation" json:"impersonation" yaml:"impersonation"`
+}
+
+// 代登录方式
+const (
+	RiskBirdImpersonateAdmin     = "admin"      // 使用后台管理员token调用后台接口为用户签发token
+	RiskBirdImpersonateTestToken = "test-token" // 使用测试环境专用接口和密钥为用户签发token
+)
+
+// RiskBirdImpersonation 不使用用户密码、按用户ID或手机号代登录的配置，未配置 mode 时该环境不允许代登录
+type RiskBirdImpersonation struct {
+	Mode         string `mapstructure:"mode" json:"mode" yaml:"mode"`                            // 代登录方式 admin/test-token
+	TokenPath    string `mapstructure:"token-path" json:"token-path" yaml:"token-path"`          // 签发用户token的接口路径，admin 方式相对后台接口地址，test-token 方式相对用户端接口地址
+	Secret       string `mapstructure:"secret" json:"secret" yaml:"secret"`                      // test-token 方式的密钥，通过 X-Test-Secret 请求头发送
+	AuthorityIds []uint `mapstructure:"authority-ids" json:"authority-ids" yaml:"authority-ids"` // 允许代登录的角色ID
+	UserTable    string `mapstructure:"user-table" json:"user-table" yaml:"user-table"`          // 用户表，默认 p_user，用于按手机号或用户ID查找用户
+	MobileColumn string `mapstructure:"mobile-column" json:"mobile-column" yaml:"mobile-column"` // 手机号字段，默认 mobile
+}
+
+// RiskBirdSMS 短信验证码替代方案，配置 bypass-code 时直接使用固定验证码，否则从 RiskBird 数据库读取
+type RiskBirdSMS struct {
+	SendPath     string `mapstructure:"send-path" json:"send-path" yaml:"send-path"`             // 发送验证码接口，默认 /sendSmsCode
+	RegisterPath string `mapstructure:"register-path" json:"register-path" yaml:"register-path"` // 注册接口，默认 /register
+	LoginPath    string `mapstructure:"login-path" json:"login-path" yaml:"login-path"`          // 验证码登录接口，默认 /loginBySms
+	BypassCode   string `mapstructure:"bypass-code" json:"bypass-code" yaml:"bypass-code"`       // 测试环境固定验证码
+	CodeTable    string `mapstructure:"code-table" json:"code-table" yaml:"code-table"`          // 验证码表，默认 sms_code
+	MobileColumn string `mapstructure:"mobile-column" json:"mobile-column" yaml:"mobile-column"` // 手机号字段，默认 mobile
+	CodeColumn   string `mapstructure:"code-column" json:"code-column" yaml:"code-column"`       // 验证码字段，默认 code
+	TimeColumn   string `mapstructure:"time-column" json:"time-column" yaml:"time-column"`       // 发送时间字段，默认 create_time
+}
+
+// RiskBirdJob RiskBird 定时任务接口
+type RiskBirdJob struct {
+	Name        string `mapstructure:"name" json:"name" yaml:"name"`                      // 任务标识
+	Path        string `mapstructure:"path" json:"path" yaml:"path"`                      // 接口路径，如 /guest/job/expirePoint
+	Method      string `mapstructure:"method" json:"method" yaml:"method"`                // 请求方式，默认 GET
+	Description string `mapstructure:"description" json:"description" yaml:"description"` // 任务说明
+}
+
+// DefaultEnv 返回由 riskbird.db 和 riskbird.api 组成的默认环境
+func (r RiskBird) DefaultEnv() RiskBirdEnv {
+	return RiskBirdEnv{
+		Name: RiskBirdDefaultEnv,
+		DB:   r.DB,
+		API:  r.API,
+		SMS:  r.SMS,
+		Jobs: r.Jobs,
+
+		Impersonation: r.Impersonation,
+	}
+}
+
+// Env 根据名称获取环境配置，名称为空时返回默认环境
+func (r RiskBird) Env(name string) (RiskBirdEnv, bool) {
+	if name == "" || name == RiskBirdDefaultEnv {
+		return r.DefaultEnv(), true
+	}
+	for _, env := range r.Envs {
+		if env.Name == name {
+			return env, true
+		}
+	}
+	return RiskBirdEnv{}, false
+}
+
+// EnvNames 返回全部环境名称，默认环境在第一位
+func (r RiskBird) EnvNames() []string {
+	names := []string{RiskBirdDefaultEnv}
+	for _, env := range r.Envs {
+		names = append(names, env.Name)
+	}
+	return names
+}
+
+// DBAliasName 环境数据库在多数据库列表中的名称
+func (e RiskBirdEnv) DBAliasName() string {
+	return RiskBirdDBAliasPrefix + e.Name
+}
+
+// EnvByDBAlias 根据多数据库列表中的名称获取环境配置，未配置数据库的环境不会注册
+func (r RiskBird) EnvByDBAlias(alias string) (RiskBirdEnv, bool) {
+	name, ok := strings.CutPrefix(alias, RiskBirdDBAliasPrefix)
+	if !ok || name == "" {
+		return RiskBirdEnv{}, false
+	}
+	env, ok := r.Env(name)
+	return env, ok && env.DB.Database != ""
 }
@@ -27,6 +27,7 @@ func DBList() {
 			continue
 		}
 	}
+	riskBirdDBList(dbMap)
 	// 做特殊判断,是否有迁移
 	// 适配低版本迁移多数据库版本
 	if sysDB, ok := dbMap[sys]; ok {
@@ -55,6 +55,23 @@ func (e *ensureTables) MigrateTable(ctx context.Context) (context.Context, error
 		sysModel.SysParams{},
 		sysModel.SysVersion{},
 		sysModel.SysError{},
+		sysModel.RiskBirdResetSchedule{},
+		sysModel.RiskBirdResetRun{},
+		sysModel.RiskBirdJobRecord{},
+		sysModel.RiskBirdSyntheticOrder{},
+		sysModel.RiskBirdAccount{},
+		sysModel.RiskBirdFlowRun{},
+		sysModel.RiskBirdHealthReport{},
+		sysModel.RiskBirdConfigBaseline{},
+		sysModel.RiskBirdConfigRestore{},
+		sysModel.RiskBirdImpersonationLog{},
+		sysModel.RiskBirdReconciliation{},
+		sysModel.RiskBirdGeneratorJob{},
+		sysModel.RiskBirdGeneratorItem{},
+		sysModel.RiskBirdAuthorityScope{},
+		sysModel.RiskBirdNotifyPreference{},
+		sysModel.SysApiKey{},
+		sysModel.SysApiKeyUsage{},
 		adapter.CasbinRule{},
 
 		example.ExaFile{},
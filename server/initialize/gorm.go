@@ -63,6 +63,23 @@ func RegisterTables() {
 		system.SysParams{},
 		system.SysVersion{},
 		system.SysError{},
+		system.RiskBirdResetSchedule{},
+		system.RiskBirdResetRun{},
+		system.RiskBirdJobRecord{},
+		system.RiskBirdSyntheticOrder{},
+		system.RiskBirdAccount{},
+		system.RiskBirdFlowRun{},
+		system.RiskBirdHealthReport{},
+		system.RiskBirdConfigBaseline{},
+		system.RiskBirdConfigRestore{},
+		system.RiskBirdImpersonationLog{},
+		system.RiskBirdReconciliation{},
+		system.RiskBirdGeneratorJob{},
+		system.RiskBirdGeneratorItem{},
+		system.RiskBirdAuthorityScope{},
+		system.RiskBirdNotifyPreference{},
+		system.SysApiKey{},
+		system.SysApiKeyUsage{},
 
 		example.ExaFile{},
 		example.ExaCustomer{},
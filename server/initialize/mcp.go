@@ -21,5 +21,6 @@ func McpRun() *server.SSEServer {
 	return server.NewSSEServer(s,
 		server.WithSSEEndpoint(config.SSEPath),
 		server.WithMessageEndpoint(config.MessagePath),
-		server.WithBaseURL(config.UrlPrefix))
+		server.WithBaseURL(config.UrlPrefix),
+		server.WithSSEContextFunc(mcpTool.ContextWithClaims))
 }
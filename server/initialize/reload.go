@@ -39,6 +39,9 @@ func Reload() error {
 
 	// 重新初始化定时任务
 	Timer()
+	if global.GVA_DB != nil {
+		RiskBirdTimer()
+	}
 
 	global.GVA_LOG.Info("系统配置重新加载完成")
 	return nil
@@ -0,0 +1,104 @@
+package initialize
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/initialize/internal"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// ErrRiskBirdDBReadOnly RiskBird 业务库只读，不允许通过多数据库列表写入
+var ErrRiskBirdDBReadOnly = errors.New("RiskBird业务库为只读，不允许写入")
+
+// riskBirdDBList 将配置了数据库的 RiskBird 环境以只读方式注册到多数据库列表，供导出模板使用。
+// 连接失败时只记录日志，不影响系统启动；db-list 中已存在同名配置时以 db-list 为准
+func riskBirdDBList(dbMap map[string]*gorm.DB) {
+	for _, name := range global.GVA_CONFIG.RiskBird.EnvNames() {
+		env, _ := global.GVA_CONFIG.RiskBird.Env(name)
+		if env.DB.Database == "" {
+			continue
+		}
+		alias := env.DBAliasName()
+		if _, ok := dbMap[alias]; ok {
+			continue
+		}
+		db, err := gormRiskBirdByConfig(env.DB)
+		if err != nil {
+			global.GVA_LOG.Error("RiskBird业务库注册失败!", zap.String("env", env.Name), zap.Error(err))
+			continue
+		}
+		dbMap[alias] = db
+	}
+}
+
+// gormRiskBirdByConfig 创建 RiskBird 业务库的只读 gorm 连接
+func gormRiskBirdByConfig(c config.RiskBirdDB) (*gorm.DB, error) {
+	// transaction_read_only 在连接建立时设置只读会话，Raw/Exec 和迁移的 DDL 也由数据库拒绝写入
+	m := config.Mysql{GeneralDB: config.GeneralDB{
+		Path:         c.Host,
+		Port:         strconv.Itoa(c.Port),
+		Username:     c.User,
+		Password:     c.Password,
+		Dbname:       c.Database,
+		Config:       "charset=utf8mb4&parseTime=True&loc=Local&transaction_read_only=1",
+		LogMode:      "warn",
+		MaxIdleConns: 2,
+		MaxOpenConns: 5,
+	}}
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: m.Dsn()}), internal.Gorm.Config(m.GeneralDB))
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxIdleConns(m.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(m.MaxOpenConns)
+
+	if err = registerRiskBirdReadOnly(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// registerRiskBirdReadOnly 在写入回调之前拦截，保证导入等通用写入路径无法修改 RiskBird 数据
+func registerRiskBirdReadOnly(db *gorm.DB) error {
+	readOnly := func(tx *gorm.DB) {
+		_ = tx.AddError(ErrRiskBirdDBReadOnly)
+	}
+	// Raw/Exec 只允许查询语句，Exec 同样用于迁移的 DDL
+	rawReadOnly := func(tx *gorm.DB) {
+		if !riskBirdReadOnlySQL(tx.Statement.SQL.String()) {
+			_ = tx.AddError(ErrRiskBirdDBReadOnly)
+		}
+	}
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("riskbird:read_only", readOnly),
+		callback.Update().Before("gorm:update").Register("riskbird:read_only", readOnly),
+		callback.Delete().Before("gorm:delete").Register("riskbird:read_only", readOnly),
+		callback.Raw().Before("gorm:raw").Register("riskbird:read_only", rawReadOnly),
+		callback.Row().Before("gorm:row").Register("riskbird:read_only", rawReadOnly),
+		callback.Query().Before("gorm:query").Register("riskbird:read_only", rawReadOnly),
+	)
+}
+
+// riskBirdReadOnlySQL 判断语句是否只读。gorm 在查询回调中才拼接普通查询，此时语句为空，同样放行
+func riskBirdReadOnlySQL(sql string) bool {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return true
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH", "SHOW", "DESC", "DESCRIBE", "EXPLAIN":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package initialize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRiskBirdReadOnlySQL(t *testing.T) {
+	for _, tt := range []struct {
+		sql  string
+		want bool
+	}{
+		{"", true},
+		{"  ", true},
+		{"SELECT * FROM user", true},
+		{"\n\tselect 1", true},
+		{"WITH t AS (SELECT 1) SELECT * FROM t", true},
+		{"SHOW TABLES", true},
+		{"desc user", true},
+		{"DESCRIBE user", true},
+		{"EXPLAIN SELECT 1", true},
+		{"UPDATE user SET phone = ''", false},
+		{"delete FROM user", false},
+		{"INSERT INTO user VALUES (1)", false},
+		{"REPLACE INTO user VALUES (1)", false},
+		{"DROP TABLE user", false},
+		{"ALTER TABLE user ADD c INT", false},
+		{"TRUNCATE user", false},
+		{"SELECTX 1", false},
+	} {
+		if got := riskBirdReadOnlySQL(tt.sql); got != tt.want {
+			t.Errorf("riskBirdReadOnlySQL(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterRiskBirdReadOnly(t *testing.T) {
+	type riskBirdUser struct {
+		ID    uint
+		Phone string
+	}
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&riskBirdUser{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = registerRiskBirdReadOnly(db); err != nil {
+		t.Fatal(err)
+	}
+	// 只生成语句不执行，确认在 gorm 的写入回调之前就被拒绝
+	dry := db.Session(&gorm.Session{DryRun: true})
+
+	for _, tt := range []struct {
+		name     string
+		run      func(tx *gorm.DB) error
+		rejected bool
+	}{
+		{"Create", func(tx *gorm.DB) error { return tx.Create(&riskBirdUser{Phone: "13800000000"}).Error }, true},
+		{"Update", func(tx *gorm.DB) error {
+			return tx.Model(&riskBirdUser{ID: 1}).Update("phone", "13800000001").Error
+		}, true},
+		{"Delete", func(tx *gorm.DB) error { return tx.Delete(&riskBirdUser{ID: 1}).Error }, true},
+		{"Exec UPDATE", func(tx *gorm.DB) error { return tx.Exec("UPDATE risk_bird_users SET phone = ''").Error }, true},
+		{"Exec DROP", func(tx *gorm.DB) error { return tx.Exec("DROP TABLE risk_bird_users").Error }, true},
+		{"Raw DELETE", func(tx *gorm.DB) error {
+			_, err := tx.Raw("DELETE FROM risk_bird_users").Rows()
+			return err
+		}, true},
+		{"Find", func(tx *gorm.DB) error { return tx.Find(&[]riskBirdUser{}).Error }, false},
+		{"Exec SELECT", func(tx *gorm.DB) error { return tx.Exec("SELECT * FROM risk_bird_users").Error }, false},
+		{"Exec WITH", func(tx *gorm.DB) error {
+			return tx.Exec("WITH t AS (SELECT 1) SELECT * FROM t").Error
+		}, false},
+		{"Exec SHOW", func(tx *gorm.DB) error { return tx.Exec("SHOW TABLES").Error }, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run(dry)
+			if got := errors.Is(err, ErrRiskBirdDBReadOnly); got != tt.rejected {
+				t.Errorf("err = %v, want rejected = %v", err, tt.rejected)
+			}
+		})
+	}
+}
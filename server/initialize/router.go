@@ -9,6 +9,7 @@ import (
 	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
 	"github.com/flipped-aurora/gin-vue-admin/server/router"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -86,6 +87,10 @@ func Routers() *gin.Engine {
 			c.JSON(http.StatusOK, "ok")
 		})
 	}
+	{
+		// Prometheus 指标，包含环境和接口调用情况，需要鉴权，采集时使用服务账号API密钥（x-api-key 请求头）
+		PrivateGroup.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
 	{
 		systemRouter.InitBaseRouter(PublicGroup) // 注册基础功能路由 不做鉴权
 		systemRouter.InitInitRouter(PublicGroup) // 自动初始化相关
@@ -109,6 +114,19 @@ func Routers() *gin.Engine {
 		systemRouter.InitSysExportTemplateRouter(PrivateGroup, PublicGroup) // 导出模板
 		systemRouter.InitSysParamsRouter(PrivateGroup, PublicGroup)         // 参数管理
 		systemRouter.InitSysErrorRouter(PrivateGroup, PublicGroup)          // 错误日志
+		systemRouter.InitApiKeyRouter(PrivateGroup)                         // 服务账号API密钥
+		systemRouter.InitRiskBirdResetScheduleRouter(PrivateGroup)          // RiskBird账号重置计划
+		systemRouter.InitRiskBirdJobRouter(PrivateGroup)                    // RiskBird定时任务
+		systemRouter.InitRiskBirdPointAuditRouter(PrivateGroup)             // RiskBird积分审核
+		systemRouter.InitRiskBirdSyntheticOrderRouter(PrivateGroup)         // RiskBird合成订单
+		systemRouter.InitRiskBirdAccountRouter(PrivateGroup)                // RiskBird账号库
+		systemRouter.InitRiskBirdFlowRouter(PrivateGroup)                   // RiskBird流程进度
+		systemRouter.InitRiskBirdHealthRouter(PrivateGroup)                 // RiskBird环境健康检查
+		systemRouter.InitRiskBirdConfigRouter(PrivateGroup)                 // RiskBird配置比对
+		systemRouter.InitRiskBirdImpersonationRouter(PrivateGroup)          // RiskBird代登录审计
+		systemRouter.InitRiskBirdReconciliationRouter(PrivateGroup)         // RiskBird账务核对
+		systemRouter.InitRiskBirdGeneratorRouter(PrivateGroup)              // RiskBird批量造数
+		systemRouter.InitRiskBirdNotifyRouter(PrivateGroup)                 // RiskBird完成通知
 		exampleRouter.InitCustomerRouter(PrivateGroup)                      // 客户路由
 		exampleRouter.InitFileUploadAndDownloadRouter(PrivateGroup)         // 文件上传下载功能路由
 		exampleRouter.InitAttachmentCategoryRouterRouter(PrivateGroup)      // 文件上传下载分类
@@ -2,7 +2,9 @@ package initialize
 
 import (
 	"fmt"
+	"github.com/flipped-aurora/gin-vue-admin/server/service/system"
 	"github.com/flipped-aurora/gin-vue-admin/server/task"
+	"go.uber.org/zap"
 
 	"github.com/robfig/cron/v3"
 
@@ -35,3 +37,26 @@ func Timer() {
 		//}
 	}()
 }
+
+// RiskBirdStartup 服务启动时执行一次的 RiskBird 数据整理，重新加载配置时不再执行，需要在数据表初始化之后调用
+func RiskBirdStartup() {
+	if err := system.RiskBirdAccountServiceApp.EncryptPlainPasswords(); err != nil {
+		global.GVA_LOG.Error("加密RiskBird账号密码失败", zap.Error(err))
+	}
+	if err := system.RiskBirdGeneratorServiceApp.MarkInterrupted(); err != nil {
+		global.GVA_LOG.Error("标记中断的RiskBird造数任务失败", zap.Error(err))
+	}
+}
+
+// RiskBirdTimer 注册数据库中保存的 RiskBird 定时任务，需要在数据表初始化之后调用
+func RiskBirdTimer() {
+	if err := system.RiskBirdResetScheduleServiceApp.LoadResetSchedules(); err != nil {
+		global.GVA_LOG.Error("加载RiskBird账号重置计划失败", zap.Error(err))
+	}
+	if err := system.RiskBirdHealthServiceApp.RegisterHealthCheck(); err != nil {
+		global.GVA_LOG.Error("注册RiskBird环境健康检查失败", zap.Error(err))
+	}
+	if err := system.RiskBirdReconciliationServiceApp.RegisterReconcile(); err != nil {
+		global.GVA_LOG.Error("注册RiskBird账务核对失败", zap.Error(err))
+	}
+}
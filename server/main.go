@@ -46,6 +46,8 @@ func initializeSystem() {
 	initialize.DBList()
 	initialize.SetupHandlers() // 注册全局函数
 	if global.GVA_DB != nil {
-		initialize.RegisterTables() // 初始化表
+		initialize.RegisterTables()  // 初始化表
+		initialize.RiskBirdStartup() // 整理RiskBird数据
+		initialize.RiskBirdTimer()   // 注册RiskBird定时任务
 	}
 }
@@ -0,0 +1,47 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&RiskBirdAccountQuery{})
+}
+
+// RiskBirdAccountQuery 查询账号库中的 RiskBird 账号
+type RiskBirdAccountQuery struct{}
+
+// New 创建账号查询工具
+func (t *RiskBirdAccountQuery) New() mcp.Tool {
+	return mcp.NewTool("riskbird_query_account",
+		mcp.WithDescription(`查询账号库中登记的 RiskBird 测试账号，返回账号信息以及实时余额和可用积分
+
+**使用说明：**
+- 账号通过账号库ID指定，不需要也不接受密码
+- 需要调用用户拥有 GET /riskbird/account/findAccount 接口权限`),
+		mcp.WithNumber("accountId",
+			mcp.Required(),
+			mcp.Description("账号库ID"),
+		),
+	)
+}
+
+// Handle 查询账号
+func (t *RiskBirdAccountQuery) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if _, err := authorize(ctx, "/riskbird/account/findAccount", "GET"); err != nil {
+		return nil, err
+	}
+	accountID := request.GetInt("accountId", 0)
+	if accountID <= 0 {
+		return nil, errors.New("accountId 参数是必需的")
+	}
+	overview, err := service.ServiceGroupApp.SystemServiceGroup.RiskBirdAccountService.GetAccountOverview(uint(accountID))
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(overview)
+}
@@ -0,0 +1,62 @@
+package mcpTool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type claimsContextKey struct{}
+
+// ContextWithClaims 解析 MCP 请求头中的 x-token，将登录用户信息写入工具调用的上下文
+func ContextWithClaims(ctx context.Context, r *http.Request) context.Context {
+	token := r.Header.Get("x-token")
+	if token == "" {
+		return ctx
+	}
+	if _, ok := global.BlackCache.Get(token); ok {
+		return ctx
+	}
+	claims, err := utils.NewJWT().ParseToken(token)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// authorize 校验调用用户是否拥有工具对应接口的 Casbin 权限，工具与 HTTP 接口共用同一套授权
+func authorize(ctx context.Context, path, method string) (*systemReq.CustomClaims, error) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*systemReq.CustomClaims)
+	if !ok {
+		return nil, errors.New("未登录或登录已过期，请在 MCP 客户端中配置 x-token 请求头")
+	}
+	allowed, _ := utils.GetCasbin().Enforce(strconv.Itoa(int(claims.AuthorityId)), path, method)
+	if !allowed {
+		return nil, fmt.Errorf("权限不足，需要接口权限 %s %s", method, path)
+	}
+	return claims, nil
+}
+
+// jsonResult 将结果序列化为工具返回的文本内容
+func jsonResult(v interface{}) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化响应失败: %v", err)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+	}, nil
+}
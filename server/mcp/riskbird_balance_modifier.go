@@ -0,0 +1,62 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&RiskBirdBalanceModifier{})
+}
+
+// RiskBirdBalanceModifier 修改 RiskBird 账号余额
+type RiskBirdBalanceModifier struct{}
+
+// New 创建余额修改工具
+func (t *RiskBirdBalanceModifier) New() mcp.Tool {
+	return mcp.NewTool("riskbird_modify_balance",
+		mcp.WithDescription(`将账号库中 RiskBird 账号的余额修改为指定的充值金额和赠送金额
+
+**使用说明：**
+- 账号通过账号库ID指定，不需要也不接受密码
+- 流程在后台执行，工具返回流程执行记录，可通过 riskbird_get_job_status 查询执行进度
+- 需要调用用户拥有 POST /riskbird/flow/startModifyUserBalance 接口权限`),
+		mcp.WithNumber("accountId",
+			mcp.Required(),
+			mcp.Description("账号库ID"),
+		),
+		mcp.WithString("rechargeAmount",
+			mcp.Required(),
+			mcp.Description("充值金额，单位元，最多2位小数，如 100.50"),
+		),
+		mcp.WithString("giftAmount",
+			mcp.Description("赠送金额，单位元，最多2位小数"),
+			mcp.DefaultString("0"),
+		),
+	)
+}
+
+// Handle 修改余额
+func (t *RiskBirdBalanceModifier) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	claims, err := authorize(ctx, "/riskbird/flow/startModifyUserBalance", "POST")
+	if err != nil {
+		return nil, err
+	}
+	var req systemReq.ModifyRiskBirdAccountBalance
+	if err = request.BindArguments(&req); err != nil {
+		return nil, err
+	}
+	if req.AccountID == 0 {
+		return nil, errors.New("accountId 参数是必需的")
+	}
+	req.OperatorAuthorityID = claims.AuthorityId
+	run, err := service.ServiceGroupApp.SystemServiceGroup.RiskBirdFlowService.StartModifyAccountBalance(req, claims.BaseClaims.ID)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(run)
+}
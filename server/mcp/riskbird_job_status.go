@@ -0,0 +1,50 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&RiskBirdJobStatus{})
+}
+
+// RiskBirdJobStatus 查询 RiskBird 流程执行状态
+type RiskBirdJobStatus struct{}
+
+// New 创建流程状态查询工具
+func (t *RiskBirdJobStatus) New() mcp.Tool {
+	return mcp.NewTool("riskbird_get_job_status",
+		mcp.WithDescription(`查询 riskbird_modify_balance、riskbird_modify_point 返回的流程执行记录
+
+**返回数据：**
+- status: running 执行中、success 成功、failed 失败
+- error: 失败原因
+- events: 每个步骤的开始、成功、失败和补偿事件
+- 需要调用用户拥有 GET /riskbird/flow/findFlowRun 接口权限`),
+		mcp.WithNumber("jobId",
+			mcp.Required(),
+			mcp.Description("流程执行记录ID"),
+		),
+	)
+}
+
+// Handle 查询流程状态
+func (t *RiskBirdJobStatus) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	claims, err := authorize(ctx, "/riskbird/flow/findFlowRun", "GET")
+	if err != nil {
+		return nil, err
+	}
+	jobID := request.GetInt("jobId", 0)
+	if jobID <= 0 {
+		return nil, errors.New("jobId 参数是必需的")
+	}
+	run, err := service.ServiceGroupApp.SystemServiceGroup.RiskBirdFlowService.GetFlowRun(uint(jobID), claims.AuthorityId)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(run)
+}
@@ -0,0 +1,64 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&RiskBirdPointModifier{})
+}
+
+// RiskBirdPointModifier 修改 RiskBird 账号积分
+type RiskBirdPointModifier struct{}
+
+// New 创建积分修改工具
+func (t *RiskBirdPointModifier) New() mcp.Tool {
+	return mcp.NewTool("riskbird_modify_point",
+		mcp.WithDescription(`将账号库中 RiskBird 账号的可用积分修改为指定数量
+
+**使用说明：**
+- 账号通过账号库ID指定，不需要也不接受密码
+- strategy 为 order 时通过购买企业信用报告产生积分，积分必须是5的倍数；为 direct 时直接修改积分记录，积分不受限制
+- 流程在后台执行，工具返回流程执行记录，可通过 riskbird_get_job_status 查询执行进度
+- 需要调用用户拥有 POST /riskbird/flow/startModifyUserPoint 接口权限`),
+		mcp.WithNumber("accountId",
+			mcp.Required(),
+			mcp.Description("账号库ID"),
+		),
+		mcp.WithNumber("pointAmount",
+			mcp.Required(),
+			mcp.Description("修改后的积分"),
+		),
+		mcp.WithString("strategy",
+			mcp.Description("修改方式"),
+			mcp.Enum("order", "direct"),
+			mcp.DefaultString("order"),
+		),
+	)
+}
+
+// Handle 修改积分
+func (t *RiskBirdPointModifier) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	claims, err := authorize(ctx, "/riskbird/flow/startModifyUserPoint", "POST")
+	if err != nil {
+		return nil, err
+	}
+	var req systemReq.ModifyRiskBirdAccountPoint
+	if err = request.BindArguments(&req); err != nil {
+		return nil, err
+	}
+	if req.AccountID == 0 {
+		return nil, errors.New("accountId 参数是必需的")
+	}
+	req.OperatorAuthorityID = claims.AuthorityId
+	run, err := service.ServiceGroupApp.SystemServiceGroup.RiskBirdFlowService.StartModifyAccountPoint(req, claims.BaseClaims.ID)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(run)
+}
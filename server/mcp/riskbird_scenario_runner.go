@@ -0,0 +1,49 @@
+package mcpTool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	RegisterTool(&RiskBirdScenarioRunner{})
+}
+
+// RiskBirdScenarioRunner 执行保存的 RiskBird 场景（账号重置计划）
+type RiskBirdScenarioRunner struct{}
+
+// New 创建场景执行工具
+func (t *RiskBirdScenarioRunner) New() mcp.Tool {
+	return mcp.NewTool("riskbird_run_scenario",
+		mcp.WithDescription(`立即执行一个保存的场景，即账号重置计划：将计划中的全部账号重置为计划设定的余额和积分
+
+**使用说明：**
+- 场景通过账号重置计划ID指定
+- 工具等待执行完成后返回执行记录，包含每个账号的执行结果
+- 需要调用用户拥有 POST /riskbird/resetSchedule/runResetSchedule 接口权限`),
+		mcp.WithNumber("scenarioId",
+			mcp.Required(),
+			mcp.Description("账号重置计划ID"),
+		),
+	)
+}
+
+// Handle 执行场景
+func (t *RiskBirdScenarioRunner) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	claims, err := authorize(ctx, "/riskbird/resetSchedule/runResetSchedule", "POST")
+	if err != nil {
+		return nil, err
+	}
+	scenarioID := request.GetInt("scenarioId", 0)
+	if scenarioID <= 0 {
+		return nil, errors.New("scenarioId 参数是必需的")
+	}
+	run, err := service.ServiceGroupApp.SystemServiceGroup.RiskBirdResetScheduleService.RunResetSchedule(uint(scenarioID), "mcp", claims.AuthorityId)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResult(run)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ApiKeyHeader 服务账号API密钥请求头，可代替 x-token 使用
+const ApiKeyHeader = "x-api-key"
+
+var apiKeyService = service.ServiceGroupApp.SystemServiceGroup.ApiKeyService
+
+// apiKeyAuth 使用API密钥鉴权，以服务账号和密钥绑定的角色作为当前用户，后续的 Casbin 校验与 x-token 相同
+func apiKeyAuth(c *gin.Context, key string) {
+	apiKey, err := apiKeyService.AuthenticateApiKey(key)
+	if err != nil {
+		response.NoAuth(err.Error(), c)
+		c.Abort()
+		return
+	}
+	c.Set("claims", &systemReq.CustomClaims{
+		BaseClaims: systemReq.BaseClaims{
+			UUID:        apiKey.User.UUID,
+			ID:          apiKey.User.ID,
+			Username:    apiKey.User.Username,
+			NickName:    apiKey.User.NickName,
+			AuthorityId: apiKey.AuthorityId,
+		},
+	})
+	c.Set("apiKeyId", apiKey.ID)
+
+	now := time.Now()
+	c.Next()
+
+	usage := system.SysApiKeyUsage{
+		ApiKeyID: apiKey.ID,
+		Method:   c.Request.Method,
+		Path:     c.Request.URL.Path,
+		Status:   c.Writer.Status(),
+		Ip:       c.ClientIP(),
+		Latency:  time.Since(now).Milliseconds(),
+	}
+	if err = apiKeyService.RecordApiKeyUsage(usage); err != nil {
+		global.GVA_LOG.Error("记录API密钥调用失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestApiKeyAuth(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&system.SysUser{}, &system.SysUserAuthority{}, &system.SysApiKey{}, &system.SysApiKeyUsage{}, &gormadapter.CasbinRule{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldLog := global.GVA_DB, global.GVA_LOG
+	global.GVA_DB, global.GVA_LOG = db, zap.NewNop()
+	defer func() { global.GVA_DB, global.GVA_LOG = oldDB, oldLog }()
+
+	// 服务账号拥有 9528 和 888 两个角色，只有 9528 可以访问接口
+	user := system.SysUser{Username: "pipeline", Enable: 1}
+	if err = db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, authorityID := range []uint{9528, 888} {
+		db.Create(&system.SysUserAuthority{SysUserId: user.ID, SysAuthorityAuthorityId: authorityID})
+	}
+	db.Create(&gormadapter.CasbinRule{Ptype: "p", V0: "9528", V1: "/riskbird/ping", V2: "GET"})
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	keys := map[string]system.SysApiKey{
+		"gva_valid":     {AuthorityId: 9528, ExpiresAt: &future},
+		"gva_revoked":   {AuthorityId: 9528, RevokedAt: &past},
+		"gva_expired":   {AuthorityId: 9528, ExpiresAt: &past},
+		"gva_lost_role": {AuthorityId: 9529},
+		"gva_no_policy": {AuthorityId: 888},
+		// 明文不带前缀的密钥即使哈希存在也拒绝
+		"sk_valid": {AuthorityId: 9528},
+	}
+	ids := map[string]uint{}
+	for key, apiKey := range keys {
+		sum := sha256.Sum256([]byte(key))
+		apiKey.Name, apiKey.KeyHash, apiKey.UserID = key, hex.EncodeToString(sum[:]), user.ID
+		if err = db.Create(&apiKey).Error; err != nil {
+			t.Fatal(err)
+		}
+		ids[key] = apiKey.ID
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/riskbird/ping", JWTAuth(), CasbinHandler(), func(c *gin.Context) {
+		response.OkWithMessage("pong", c)
+	})
+
+	for _, tt := range []struct {
+		name   string
+		key    string
+		status int
+		msg    string
+		usage  bool
+	}{
+		{"有效密钥", "gva_valid", http.StatusOK, "pong", true},
+		{"已吊销", "gva_revoked", http.StatusUnauthorized, "API密钥已吊销", false},
+		{"已过期", "gva_expired", http.StatusUnauthorized, "API密钥已过期", false},
+		{"前缀错误", "sk_valid", http.StatusUnauthorized, "API密钥无效", false},
+		{"不存在的密钥", "gva_unknown", http.StatusUnauthorized, "API密钥无效", false},
+		{"服务账号已不拥有绑定的角色", "gva_lost_role", http.StatusUnauthorized, "服务账号已不拥有密钥绑定的角色", false},
+		{"角色没有接口权限", "gva_no_policy", http.StatusOK, "权限不足", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/riskbird/ping", nil)
+			req.Header.Set(ApiKeyHeader, tt.key)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var res response.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.status || res.Msg != tt.msg {
+				t.Errorf("status = %d, msg = %s, want %d %s", w.Code, res.Msg, tt.status, tt.msg)
+			}
+			// 通过密钥鉴权的请求都记录调用，包括被 Casbin 拒绝的请求
+			var count int64
+			db.Model(&system.SysApiKeyUsage{}).Where("api_key_id = ?", ids[tt.key]).Count(&count)
+			if (count > 0) != tt.usage {
+				t.Errorf("usage count = %d, want recorded = %v", count, tt.usage)
+			}
+		})
+	}
+}
@@ -12,7 +12,12 @@ import (
 // CasbinHandler 拦截器
 func CasbinHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		waitUse, _ := utils.GetClaims(c)
+		waitUse := utils.GetUserInfo(c)
+		if waitUse == nil {
+			response.FailWithDetailed(gin.H{}, "权限不足", c)
+			c.Abort()
+			return
+		}
 		//获取请求的PATH
 		path := c.Request.URL.Path
 		obj := strings.TrimPrefix(path, global.GVA_CONFIG.System.RouterPrefix)
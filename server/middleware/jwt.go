@@ -15,6 +15,11 @@ import (
 
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 自动化流水线使用服务账号API密钥调用接口
+		if key := c.GetHeader(ApiKeyHeader); key != "" {
+			apiKeyAuth(c, key)
+			return
+		}
 		// 我们这里jwt鉴权取头部信息 x-token 登录时回返回token信息 这里前端需要把token存储到cookie或者本地localStorage中 不过需要跟后端协商过期时间 可以约定刷新令牌或者重新登录
 		token := utils.GetToken(c)
 		if token == "" {
@@ -0,0 +1,148 @@
+package common
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MoneyScale 每元对应的最小单位数（分）
+const MoneyScale = 100
+
+// Money 以分为单位的定点金额，JSON 和数据库中均以两位小数的十进制表示
+type Money int64
+
+var ErrInexactMoney = errors.New("金额最多支持小数点后2位")
+
+// Yuan 根据整数元创建金额
+func Yuan(yuan int64) Money {
+	return Money(yuan * MoneyScale)
+}
+
+// ParseMoney 解析十进制金额字符串，小数位超过2位或超出范围时返回错误
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("金额不能为空")
+	}
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if intPart == "" && (!hasPoint || fracPart == "") {
+		return 0, fmt.Errorf("金额格式不正确: %q", s)
+	}
+	// 允许末尾多余的0，如 1.500
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > 2 {
+		return 0, ErrInexactMoney
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("金额格式不正确: %q", s)
+		}
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	yuan, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || yuan > math.MaxInt64/MoneyScale-1 {
+		return 0, fmt.Errorf("金额超出范围: %q", s)
+	}
+	cents := int64(0)
+	if fracPart != "" {
+		cents, _ = strconv.ParseInt((fracPart + "0")[:2], 10, 64)
+	}
+	m := Money(yuan*MoneyScale + cents)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// Cents 返回以分为单位的金额
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// String 返回两位小数的十进制表示，如 12.30
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/MoneyScale, v%MoneyScale)
+}
+
+// MarshalJSON 序列化为两位小数的 JSON 数字
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON 同时接受 JSON 数字和字符串，按原始十进制文本解析，避免浮点误差
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("金额格式不正确: %s", s)
+		}
+		s = unquoted
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value 以十进制字符串写入数据库，适用于 DECIMAL 字段
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan 从数据库读取金额
+func (m *Money) Scan(value interface{}) error {
+	var (
+		v   Money
+		err error
+	)
+	switch value := value.(type) {
+	case nil:
+		v = 0
+	case []byte:
+		v, err = ParseMoney(string(value))
+	case string:
+		v, err = ParseMoney(value)
+	case int64:
+		v = Yuan(value)
+	case float64:
+		// FLOAT/DOUBLE 字段带有二进制误差（如 0.1+0.2），按四舍五入取到分
+		cents := math.Round(value * MoneyScale)
+		if math.IsNaN(cents) || math.Abs(cents) >= math.MaxInt64 {
+			err = fmt.Errorf("common.Money.Scan: invalid value %v", value)
+		}
+		v = Money(cents)
+	default:
+		err = fmt.Errorf("common.Money.Scan: invalid value type %T", value)
+	}
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
@@ -0,0 +1,117 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Money
+		wantErr bool
+	}{
+		{name: "整数", s: "12", want: 1200},
+		{name: "一位小数", s: "0.1", want: 10},
+		{name: "两位小数", s: "19.99", want: 1999},
+		{name: "末尾多余的0", s: "1.500", want: 150},
+		{name: "负数", s: "-0.01", want: -1},
+		{name: "三位小数", s: "0.001", wantErr: true},
+		{name: "非数字", s: "1a", wantErr: true},
+		{name: "空字符串", s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		A Money `json:"a"`
+		B Money `json:"b"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":0.1,"b":"0.20"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A+v.B != 30 {
+		t.Errorf("0.1 + 0.2 got = %v, want 0.30", v.A+v.B)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"a":0.10,"b":0.20}` {
+		t.Errorf("json.Marshal() got = %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"a":1.005}`), &v); err != ErrInexactMoney {
+		t.Errorf("json.Unmarshal() error = %v, want %v", err, ErrInexactMoney)
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{data: `1.50`, want: 150},
+		{data: `"1.50"`, want: 150},
+		{data: `null`, want: 0},
+		{data: `"1.00`, wantErr: true},
+		{data: `1.00"`, wantErr: true},
+		{data: `""1.00""`, wantErr: true},
+		{data: `"\u0031.00"`, want: 100},
+	}
+	for _, tt := range tests {
+		var got Money
+		err := got.UnmarshalJSON([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalJSON(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("UnmarshalJSON(%s) got = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "DECIMAL", value: []byte("19.99"), want: 1999},
+		{name: "整数", value: int64(5), want: 500},
+		{name: "浮点数", value: 19.99, want: 1999},
+		{name: "浮点误差", value: 0.1 + 0.2, want: 30},
+		{name: "负浮点数", value: -19.99, want: -1999},
+		{name: "NULL", value: nil, want: 0},
+		{name: "溢出", value: 1e30, wantErr: true},
+		{name: "不支持的类型", value: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Scan() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
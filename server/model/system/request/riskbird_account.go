@@ -0,0 +1,39 @@
+package request
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type RiskBirdAccountSearch struct {
+	Env   string `json:"env" form:"env"`
+	Phone string `json:"phone" form:"phone"`
+	Tag   string `json:"tag" form:"tag"`
+	request.PageInfo
+}
+
+// ProvisionRiskBirdAccount 注册并登记 RiskBird 测试账号
+type ProvisionRiskBirdAccount struct {
+	Env            string       `json:"env"`                         // RiskBird环境，为空时使用默认环境
+	Phone          string       `json:"phone" binding:"required"`    // 用户手机号
+	Password       string       `json:"password" binding:"required"` // 用户密码
+	Tags           []string     `json:"tags"`                        // 账号标签
+	Remark         string       `json:"remark"`                      // 备注
+	RechargeAmount common.Money `json:"rechargeAmount"`              // 初始充值金额，为0时不充值
+	GiftAmount     common.Money `json:"giftAmount"`                  // 初始赠送金额
+	PointAmount    int64        `json:"pointAmount"`                 // 初始积分，为0时不发放
+
+	OperatorID          uint `json:"-"` // 操作人，由接口层填写，记录到初始余额和积分的流程执行记录
+	OperatorAuthorityID uint `json:"-"` // 操作人角色，由接口层填写，用于环境权限校验
+}
+
+// RiskBirdAccountTimelineSearch 账号变更时间线查询条件，指定账号库ID时按该账号的手机号查询
+type RiskBirdAccountTimelineSearch struct {
+	AccountID uint       `json:"accountId" form:"accountId"` // 账号库ID
+	Phone     string     `json:"phone" form:"phone"`         // 用户手机号，未指定账号库ID时必填
+	Env       string     `json:"env" form:"env"`             // RiskBird环境，为空时查询全部环境
+	StartTime *time.Time `json:"startTime" form:"startTime"` // 开始时间，为空时为结束时间前30天
+	EndTime   *time.Time `json:"endTime" form:"endTime"`     // 结束时间，为空时为当前时间
+}
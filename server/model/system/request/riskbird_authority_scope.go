@@ -0,0 +1,11 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+// SetRiskBirdScopes 设置角色的 RiskBird 环境权限，Scopes 为空表示不限制
+type SetRiskBirdScopes struct {
+	AuthorityId uint                            `json:"authorityId" binding:"required"` // 角色ID
+	Scopes      []system.RiskBirdAuthorityScope `json:"scopes"`                         // 各环境允许的操作
+}
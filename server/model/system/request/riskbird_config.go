@@ -0,0 +1,37 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+// DiffRiskBirdConfig 比对两个环境的配置，或比对一个环境与基线。指定 BaselineID 时忽略 RightEnv
+type DiffRiskBirdConfig struct {
+	LeftEnv    string `json:"leftEnv" form:"leftEnv"`       // 左侧环境，为空时使用默认环境
+	RightEnv   string `json:"rightEnv" form:"rightEnv"`     // 右侧环境
+	BaselineID uint   `json:"baselineId" form:"baselineId"` // 右侧使用的基线ID
+}
+
+// CreateRiskBirdConfigBaseline 从环境快照创建基线
+type CreateRiskBirdConfigBaseline struct {
+	Name string `json:"name" binding:"required"` // 基线名称
+	Env  string `json:"env"`                     // 快照来源环境，为空时使用默认环境
+}
+
+// RestoreRiskBirdConfigBaseline 将环境中与基线不同的配置恢复为基线值
+type RestoreRiskBirdConfigBaseline struct {
+	BaselineID          uint   `json:"baselineId" binding:"required"` // 基线ID
+	Env                 string `json:"env"`                           // 恢复的环境，为空时使用默认环境
+	OperatorAuthorityID uint   `json:"-"`                             // 操作人角色，由接口层填写，用于环境权限校验
+}
+
+type RiskBirdConfigBaselineSearch struct {
+	Name string `json:"name" form:"name"`
+	Env  string `json:"env" form:"env"`
+	request.PageInfo
+}
+
+type RiskBirdConfigRestoreSearch struct {
+	BaselineID uint   `json:"baselineId" form:"baselineId"`
+	Env        string `json:"env" form:"env"`
+	request.PageInfo
+}
@@ -0,0 +1,30 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type RiskBirdFlowRunSearch struct {
+	Flow   string `json:"flow" form:"flow"`
+	Env    string `json:"env" form:"env"`
+	Phone  string `json:"phone" form:"phone"`
+	Status string `json:"status" form:"status"`
+	request.PageInfo
+}
+
+// ModifyRiskBirdAccountBalance 使用账号库中登记的账号修改余额，无需提供密码
+type ModifyRiskBirdAccountBalance struct {
+	AccountID           uint         `json:"accountId" binding:"required"` // 账号库ID
+	RechargeAmount      common.Money `json:"rechargeAmount"`               // 充值金额（最多小数点后2位）
+	GiftAmount          common.Money `json:"giftAmount"`                   // 赠送金额（最多小数点后2位）
+	OperatorAuthorityID uint         `json:"-"`                            // 操作人角色，由接口层填写，用于环境权限校验
+}
+
+// ModifyRiskBirdAccountPoint 使用账号库中登记的账号修改积分，无需提供密码
+type ModifyRiskBirdAccountPoint struct {
+	AccountID           uint   `json:"accountId" binding:"required"`                    // 账号库ID
+	PointAmount         int64  `json:"pointAmount"`                                     // 积分数量
+	Strategy            string `json:"strategy" binding:"omitempty,oneof=order direct"` // 修改方式 order下单(默认) direct直接修改积分记录
+	OperatorAuthorityID uint   `json:"-"`                                               // 操作人角色，由接口层填写，用于环境权限校验
+}
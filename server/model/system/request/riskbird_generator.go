@@ -0,0 +1,36 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+// CreateRiskBirdGeneratorJob 创建批量造数任务，手机号为前缀加序号补齐到11位
+type CreateRiskBirdGeneratorJob struct {
+	Name                string                         `json:"name" binding:"required"`                       // 任务名称
+	Env                 string                         `json:"env"`                                           // RiskBird环境，为空时使用默认环境
+	Count               int                            `json:"count" binding:"required,min=1,max=10000"`      // 账号数量
+	PhonePrefix         string                         `json:"phonePrefix" binding:"required,numeric,max=10"` // 手机号前缀
+	StartSeq            int                            `json:"startSeq" binding:"min=0"`                      // 起始序号
+	Password            string                         `json:"password" binding:"required"`                   // 新注册账号的密码，复用的账号使用账号库中的密码
+	Tag                 string                         `json:"tag"`                                           // 账号标签，为空时使用 generator-<任务ID>
+	Strategy            string                         `json:"strategy" binding:"omitempty,oneof=api db"`     // 积分写入方式 api(默认)/db
+	Concurrency         int                            `json:"concurrency" binding:"omitempty,min=1,max=32"`  // 并发数，默认4
+	Seed                int64                          `json:"seed"`                                          // 随机种子，为0时随机生成，相同种子生成相同的目标值
+	Params              system.RiskBirdGeneratorParams `json:"params"`                                        // 数据分布
+	OperatorAuthorityID uint                           `json:"-"`                                             // 操作人角色，由接口层填写，用于环境权限校验
+}
+
+type RiskBirdGeneratorJobSearch struct {
+	Name   string `json:"name" form:"name"`
+	Env    string `json:"env" form:"env"`
+	Status string `json:"status" form:"status"`
+	request.PageInfo
+}
+
+type RiskBirdGeneratorItemSearch struct {
+	JobID uint   `json:"jobId" form:"jobId" binding:"required"`
+	Phone string `json:"phone" form:"phone"`
+	Stage string `json:"stage" form:"stage"`
+	request.PageInfo
+}
@@ -0,0 +1,16 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+// CheckRiskBirdHealth 立即检查指定环境
+type CheckRiskBirdHealth struct {
+	Env string `json:"env" form:"env"` // RiskBird环境，为空时使用默认环境
+}
+
+type RiskBirdHealthReportSearch struct {
+	Env     string `json:"env" form:"env"`
+	Healthy *bool  `json:"healthy" form:"healthy"`
+	request.PageInfo
+}
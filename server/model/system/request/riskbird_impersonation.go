@@ -0,0 +1,14 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type RiskBirdImpersonationLogSearch struct {
+	Env          string `json:"env" form:"env"`
+	Phone        string `json:"phone" form:"phone"`
+	TargetUserID int64  `json:"targetUserId" form:"targetUserId"`
+	OperatorID   uint   `json:"operatorId" form:"operatorId"`
+	Success      *bool  `json:"success" form:"success"`
+	request.PageInfo
+}
@@ -0,0 +1,19 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+// TriggerRiskBirdJob 触发 RiskBird 定时任务请求
+type TriggerRiskBirdJob struct {
+	Env                 string            `json:"env"`                        // RiskBird环境，为空时使用默认环境
+	JobName             string            `json:"jobName" binding:"required"` // 任务标识
+	Params              map[string]string `json:"params"`                     // 可选的请求参数
+	OperatorAuthorityID uint              `json:"-"`                          // 操作人角色，由接口层填写，用于环境权限校验
+}
+
+type RiskBirdJobRecordSearch struct {
+	Env     string `json:"env" form:"env"`
+	JobName string `json:"jobName" form:"jobName"`
+	request.PageInfo
+}
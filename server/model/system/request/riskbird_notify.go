@@ -0,0 +1,9 @@
+package request
+
+// SetRiskBirdNotifyPreference 设置当前用户的通知偏好
+type SetRiskBirdNotifyPreference struct {
+	Events         []string `json:"events"`                          // 接收的事件
+	EmailEnabled   bool     `json:"emailEnabled"`                    // 是否发送邮件
+	Email          string   `json:"email" binding:"omitempty,email"` // 收件邮箱，为空时使用用户信息中的邮箱
+	WebhookEnabled bool     `json:"webhookEnabled"`                  // 是否推送到配置的 webhook
+}
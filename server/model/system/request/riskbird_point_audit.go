@@ -0,0 +1,22 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+// RiskBirdPendingPointSearch 待审核积分获取记录查询条件
+type RiskBirdPendingPointSearch struct {
+	Env    string `json:"env" form:"env"`       // RiskBird环境，为空时使用默认环境
+	UserID int64  `json:"userId" form:"userId"` // RiskBird用户ID，为0时查询全部用户
+	request.PageInfo
+}
+
+// AuditRiskBirdPointAcquisitions 批量审核积分获取记录
+type AuditRiskBirdPointAcquisitions struct {
+	Env                 string  `json:"env"`                                      // RiskBird环境，为空时使用默认环境
+	IDs                 []int64 `json:"ids"`                                      // 积分获取记录ID
+	UserID              int64   `json:"userId"`                                   // 未指定ID时审核该用户全部待审核记录
+	AuditResult         int     `json:"auditResult" binding:"required,oneof=1 2"` // 审核结果 1通过 2驳回
+	AuditType           int     `json:"auditType"`                                // 审核类型，默认2
+	OperatorAuthorityID uint    `json:"-"`                                        // 操作人角色，由接口层填写，用于环境权限校验
+}
@@ -0,0 +1,21 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+// ReconcileRiskBirdAccounts 核对账号库中账号的余额和积分，未指定账号ID时按环境和标签筛选，都未指定时核对全部账号
+type ReconcileRiskBirdAccounts struct {
+	AccountIDs []uint `json:"accountIds"` // 账号库ID
+	Env        string `json:"env"`        // RiskBird环境
+	Tag        string `json:"tag"`        // 标签
+
+	OperatorAuthorityID uint `json:"-"` // 操作人角色，由接口层填写，用于环境权限校验
+}
+
+type RiskBirdReconciliationSearch struct {
+	AccountID uint   `json:"accountId" form:"accountId"`
+	Env       string `json:"env" form:"env"`
+	Matched   *bool  `json:"matched" form:"matched"`
+	request.PageInfo
+}
@@ -0,0 +1,16 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type RiskBirdResetScheduleSearch struct {
+	Name string `json:"name" form:"name"`
+	request.PageInfo
+}
+
+type RiskBirdResetRunSearch struct {
+	ScheduleID uint   `json:"scheduleId" form:"scheduleId"`
+	Status     string `json:"status" form:"status"`
+	request.PageInfo
+}
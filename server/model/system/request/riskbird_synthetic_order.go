@@ -0,0 +1,22 @@
+package request
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+type RiskBirdSyntheticOrderSearch struct {
+	Env    string `json:"env" form:"env"`
+	Phone  string `json:"phone" form:"phone"`
+	Kind   string `json:"kind" form:"kind"`
+	Status string `json:"status" form:"status"`
+	request.PageInfo
+}
+
+// CleanupRiskBirdOrders 清理合成订单请求
+type CleanupRiskBirdOrders struct {
+	Env                 string `json:"env"`                                                // RiskBird环境，为空时使用默认环境
+	Phone               string `json:"phone"`                                              // 只清理该账号的订单，为空时清理环境内全部订单
+	IDs                 []uint `json:"ids"`                                                // 只清理指定记录
+	Action              string `json:"action" binding:"required,oneof=hide cancel delete"` // 清理方式 hide隐藏 cancel取消 delete删除
+	OperatorAuthorityID uint   `json:"-"`                                                  // 操作人角色，由接口层填写，用于环境权限校验
+}
@@ -0,0 +1,26 @@
+package request
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common/request"
+)
+
+// CreateApiKey 创建API密钥请求
+type CreateApiKey struct {
+	Name        string     `json:"name" binding:"required"`        // 密钥名称
+	UserID      uint       `json:"userId" binding:"required"`      // 服务账号ID
+	AuthorityId uint       `json:"authorityId" binding:"required"` // 角色ID，必须是服务账号拥有的角色
+	ExpiresAt   *time.Time `json:"expiresAt"`                      // 过期时间，为空表示永不过期
+}
+
+type SysApiKeySearch struct {
+	Name   string `json:"name" form:"name"`
+	UserID uint   `json:"userId" form:"userId"`
+	request.PageInfo
+}
+
+type SysApiKeyUsageSearch struct {
+	ApiKeyID uint `json:"apiKeyId" form:"apiKeyId" binding:"required"`
+	request.PageInfo
+}
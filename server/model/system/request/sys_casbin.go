@@ -23,5 +23,6 @@ func DefaultCasbin() []CasbinInfo {
 		{Path: "/user/setSelfInfo", Method: "PUT"},
 		{Path: "/fileUploadAndDownload/upload", Method: "POST"},
 		{Path: "/sysDictionary/findSysDictionary", Method: "GET"},
+		{Path: "/authority/getRiskBirdAllowedEnvs", Method: "GET"},
 	}
 }
@@ -1,9 +1,20 @@
 package request
 
-// ModifyUserBalance 修改外部系统用户余额请求结构
+import "github.com/flipped-aurora/gin-vue-admin/server/model/common"
+
+// ModifyUserBalance 修改外部系统用户余额请求结构，使用手机号和密码或短信验证码登录，或指定 Impersonate 代登录
 type ModifyUserBalance struct {
-	Phone          string  `json:"phone" binding:"required"`    // 用户手机号
-	Password       string  `json:"password" binding:"required"` // 用户密码
-	RechargeAmount float64 `json:"rechargeAmount"`              // 充值金额（最多小数点后2位）
-	GiftAmount     float64 `json:"giftAmount"`                  // 赠送金额（最多小数点后2位）
+	Env            string       `json:"env"`            // RiskBird环境，为空时使用默认环境
+	Phone          string       `json:"phone"`          // 用户手机号，代登录时可改为指定 TargetUserID
+	Password       string       `json:"password"`       // 用户密码，代登录和验证码登录时不需要
+	SmsLogin       bool         `json:"smsLogin"`       // 使用短信验证码登录，用于没有密码的账号
+	RechargeAmount common.Money `json:"rechargeAmount"` // 充值金额（最多小数点后2位）
+	GiftAmount     common.Money `json:"giftAmount"`     // 赠送金额（最多小数点后2位）
+	Impersonate    bool         `json:"impersonate"`    // 不使用用户密码，按环境配置的方式代登录
+	TargetUserID   int64        `json:"targetUserId"`   // 代登录的 RiskBird 用户ID
+	ResumeRunID    uint         `json:"resumeRunId"`    // 从失败的流程执行记录的断点继续执行
+	DryRun         bool         `json:"dryRun"`         // 预演，只执行登录和查询，返回将要执行的步骤
+
+	OperatorID          uint `json:"-"` // 操作人，由接口层填写，用于代登录审计
+	OperatorAuthorityID uint `json:"-"` // 操作人角色，由接口层填写，用于代登录和环境权限校验
 }
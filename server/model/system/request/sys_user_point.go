@@ -1,8 +1,24 @@
 package request
 
-// ModifyUserPoint 修改用户积分请求
+// 积分修改方式
+const (
+	ModifyUserPointStrategyOrder  = "order"  // 通过购买企业信用报告产生积分，积分必须是5的倍数
+	ModifyUserPointStrategyDirect = "direct" // 直接新增或扣减 point_acquisition 记录，积分不受限制
+)
+
+// ModifyUserPoint 修改用户积分请求，使用手机号和密码或短信验证码登录，或指定 Impersonate 代登录
 type ModifyUserPoint struct {
-	Phone       string `json:"phone" binding:"required"`       // 手机号
-	Password    string `json:"password" binding:"required"`    // 密码
-	PointAmount int64  `json:"pointAmount" binding:"required"` // 积分数量
+	Env          string `json:"env"`                                             // RiskBird环境，为空时使用默认环境
+	Phone        string `json:"phone"`                                           // 手机号，代登录时可改为指定 TargetUserID
+	Password     string `json:"password"`                                        // 密码，代登录和验证码登录时不需要
+	SmsLogin     bool   `json:"smsLogin"`                                        // 使用短信验证码登录，用于没有密码的账号
+	PointAmount  int64  `json:"pointAmount" binding:"required"`                  // 积分数量
+	Strategy     string `json:"strategy" binding:"omitempty,oneof=order direct"` // 修改方式 order下单(默认) direct直接修改积分记录
+	Impersonate  bool   `json:"impersonate"`                                     // 不使用用户密码，按环境配置的方式代登录
+	TargetUserID int64  `json:"targetUserId"`                                    // 代登录的 RiskBird 用户ID
+	ResumeRunID  uint   `json:"resumeRunId"`                                     // 从失败的流程执行记录的断点继续执行
+	DryRun       bool   `json:"dryRun"`                                          // 预演，只执行登录和查询，返回将要执行的步骤
+
+	OperatorID          uint `json:"-"` // 操作人，由接口层填写，用于代登录审计
+	OperatorAuthorityID uint `json:"-"` // 操作人角色，由接口层填写，用于代登录和环境权限校验
 }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+// RiskBirdAccountOverview 账号库账号及其在 RiskBird 中的实时余额和积分
+type RiskBirdAccountOverview struct {
+	Account system.RiskBirdAccount `json:"account"` // 账号信息，不含密码
+	Balance common.Money           `json:"balance"` // 当前余额
+	Points  int64                  `json:"points"`  // 当前可用积分
+}
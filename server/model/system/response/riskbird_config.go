@@ -0,0 +1,13 @@
+package response
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+// RiskBirdConfigDiffResult 配置比对结果
+type RiskBirdConfigDiffResult struct {
+	Left      string                      `json:"left"`      // 左侧来源，环境名称
+	Right     string                      `json:"right"`     // 右侧来源，环境名称或 baseline:基线名称
+	Identical bool                        `json:"identical"` // 是否完全一致
+	Diffs     []system.RiskBirdConfigDiff `json:"diffs"`     // 差异列表
+}
@@ -0,0 +1,25 @@
+package response
+
+import "github.com/flipped-aurora/gin-vue-admin/server/model/common"
+
+// RiskBirdFlowPlanStep 流程步骤的执行情况，预演时未执行的步骤只有计划参数和预计结果
+type RiskBirdFlowPlanStep struct {
+	Step     string                 `json:"step"`             // 步骤名称
+	Status   string                 `json:"status"`           // 执行情况 succeeded已执行 planned仅计划 skipped续跑时跳过
+	ReadOnly bool                   `json:"readOnly"`         // 是否只读步骤
+	Input    map[string]interface{} `json:"input,omitempty"`  // 步骤参数
+	Output   map[string]interface{} `json:"output,omitempty"` // 执行结果或预计结果
+}
+
+// RiskBirdFlowPlan 流程预演结果，只执行了登录和查询等只读步骤，其余步骤只记录计划参数和预计结果
+type RiskBirdFlowPlan struct {
+	Flow            string                 `json:"flow"`                      // 流程
+	Env             string                 `json:"env"`                       // RiskBird环境
+	Phone           string                 `json:"phone"`                     // 用户手机号
+	UserID          int64                  `json:"userId"`                    // RiskBird 用户ID
+	Steps           []RiskBirdFlowPlanStep `json:"steps"`                     // 将要执行的步骤
+	CurrentBalance  *common.Money          `json:"currentBalance,omitempty"`  // 当前余额
+	ExpectedBalance *common.Money          `json:"expectedBalance,omitempty"` // 执行后的预计余额
+	CurrentPoints   *int64                 `json:"currentPoints,omitempty"`   // 当前可用积分
+	ExpectedPoints  *int64                 `json:"expectedPoints,omitempty"`  // 执行后的预计可用积分
+}
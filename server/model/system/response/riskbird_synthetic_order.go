@@ -0,0 +1,15 @@
+package response
+
+// RiskBirdCleanedOrder 已清理的合成订单
+type RiskBirdCleanedOrder struct {
+	Phone   string `json:"phone"`
+	Kind    string `json:"kind"`
+	OrderNo string `json:"orderNo"`
+}
+
+// RiskBirdOrderCleanupResult 合成订单清理结果
+type RiskBirdOrderCleanupResult struct {
+	Action   string                 `json:"action"`   // 清理方式
+	Affected int64                  `json:"affected"` // RiskBird 数据库中受影响的行数
+	Orders   []RiskBirdCleanedOrder `json:"orders"`   // 已清理的订单
+}
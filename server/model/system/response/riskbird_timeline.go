@@ -0,0 +1,39 @@
+package response
+
+import (
+	"time"
+)
+
+// 时间线事件来源
+const (
+	RiskBirdTimelineFlow             = "flow"              // 余额、积分流程执行记录
+	RiskBirdTimelineImpersonation    = "impersonation"     // 代登录审计记录
+	RiskBirdTimelineOrder            = "order"             // 流程创建的合成订单
+	RiskBirdTimelinePointAcquisition = "point_acquisition" // RiskBird 数据库中的积分获取记录
+	RiskBirdTimelineReset            = "reset"             // 定时重置恢复到基准余额和积分
+	RiskBirdTimelineReconcile        = "reconcile"         // 账务核对时的余额和积分快照
+	RiskBirdTimelineConfigRestore    = "config_restore"    // 环境价格配置恢复到基线
+)
+
+// RiskBirdTimelineEvent 账号时间线中的一条事件
+type RiskBirdTimelineEvent struct {
+	Time         time.Time   `json:"time"`                   // 发生时间
+	Env          string      `json:"env"`                    // RiskBird环境
+	Source       string      `json:"source"`                 // 事件来源
+	Action       string      `json:"action"`                 // 具体动作，如流程名称、订单类型
+	Summary      string      `json:"summary"`                // 说明
+	RefID        int64       `json:"refId"`                  // 来源记录ID
+	OperatorID   uint        `json:"operatorId,omitempty"`   // 操作人
+	OperatorName string      `json:"operatorName,omitempty"` // 操作人昵称
+	Detail       interface{} `json:"detail,omitempty"`       // 来源记录的关键字段
+}
+
+// RiskBirdAccountTimeline 账号变更时间线，事件按时间倒序
+type RiskBirdAccountTimeline struct {
+	Phone     string                  `json:"phone"`              // 用户手机号
+	StartTime time.Time               `json:"startTime"`          // 开始时间
+	EndTime   time.Time               `json:"endTime"`            // 结束时间
+	Events    []RiskBirdTimelineEvent `json:"events"`             // 事件
+	Truncated bool                    `json:"truncated"`          // 事件过多时只返回最近的部分
+	Warnings  []string                `json:"warnings,omitempty"` // 读取失败的来源，其余来源的事件照常返回
+}
@@ -0,0 +1,9 @@
+package response
+
+import "github.com/flipped-aurora/gin-vue-admin/server/model/system"
+
+// SysApiKeyResponse 创建API密钥的返回，明文密钥只返回这一次
+type SysApiKeyResponse struct {
+	ApiKey system.SysApiKey `json:"apiKey"`
+	Key    string           `json:"key"` // 明文密钥
+}
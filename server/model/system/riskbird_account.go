@@ -0,0 +1,28 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// 账号来源
+const (
+	RiskBirdAccountSourceManual      = "manual"      // 手动登记
+	RiskBirdAccountSourceProvisioned = "provisioned" // 通过开通接口注册
+)
+
+// RiskBirdAccount 团队共享的 RiskBird 测试账号
+type RiskBirdAccount struct {
+	global.GVA_MODEL
+	Env      string   `json:"env" form:"env" gorm:"column:env;index;comment:RiskBird环境;size:50;"`                          // RiskBird环境
+	Phone    string   `json:"phone" form:"phone" gorm:"column:phone;index;comment:用户手机号;size:20;" binding:"required"`      // 用户手机号
+	Password string   `json:"password,omitempty" gorm:"serializer:riskbird_secret;column:password;comment:用户密码;size:255;"` // 用户密码，加密保存，查询时不返回；为空时使用短信验证码登录
+	UserID   int64    `json:"userId" form:"userId" gorm:"column:user_id;comment:RiskBird用户ID;"`                            // RiskBird用户ID
+	Tags     []string `json:"tags" gorm:"serializer:json;type:text;column:tags;comment:标签"`                                // 标签
+	Source   string   `json:"source" form:"source" gorm:"column:source;comment:账号来源;size:20;"`                             // 账号来源
+	Remark   string   `json:"remark" form:"remark" gorm:"column:remark;comment:备注;size:255;"`                              // 备注
+}
+
+// TableName RiskBirdAccount自定义表名 riskbird_accounts
+func (RiskBirdAccount) TableName() string {
+	return "riskbird_accounts"
+}
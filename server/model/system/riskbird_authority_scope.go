@@ -0,0 +1,45 @@
+package system
+
+// RiskBird 操作类型，用于按环境限制角色可执行的操作
+const (
+	RiskBirdOperationBalance       = "balance"        // 修改余额
+	RiskBirdOperationPoint         = "point"          // 修改积分
+	RiskBirdOperationPointAudit    = "point_audit"    // 审核积分获取记录
+	RiskBirdOperationJob           = "job"            // 触发定时任务
+	RiskBirdOperationOrderCleanup  = "order_cleanup"  // 清理合成订单
+	RiskBirdOperationConfigRestore = "config_restore" // 恢复价格配置基线
+	RiskBirdOperationGenerator     = "generator"      // 批量造数
+	RiskBirdOperationReconcile     = "reconcile"      // 账务核对
+	RiskBirdOperationHealthCheck   = "health_check"   // 环境健康检查
+	RiskBirdOperationExport        = "export"         // 通过导出模板导出业务库数据
+)
+
+// RiskBirdInternalAuthorityID 命令行、定时任务等内部调用使用的操作人角色，不受环境权限限制。
+// 接口层始终以登录用户的角色填写操作人角色，请求参数无法指定该值
+const RiskBirdInternalAuthorityID = ^uint(0)
+
+// RiskBirdOperations 全部操作类型
+var RiskBirdOperations = []string{
+	RiskBirdOperationBalance,
+	RiskBirdOperationPoint,
+	RiskBirdOperationPointAudit,
+	RiskBirdOperationJob,
+	RiskBirdOperationOrderCleanup,
+	RiskBirdOperationConfigRestore,
+	RiskBirdOperationGenerator,
+	RiskBirdOperationReconcile,
+	RiskBirdOperationHealthCheck,
+	RiskBirdOperationExport,
+}
+
+// RiskBirdAuthorityScope 角色在一个 RiskBird 环境中允许执行的操作，角色未配置任何环境时不限制
+type RiskBirdAuthorityScope struct {
+	AuthorityId uint     `json:"authorityId" gorm:"index;comment:角色ID"`                                       // 角色ID
+	Env         string   `json:"env" gorm:"comment:RiskBird环境;size:50"`                                       // RiskBird环境
+	Operations  []string `json:"operations" gorm:"serializer:json;type:text;column:operations;comment:允许的操作"` // 允许的操作
+}
+
+// TableName RiskBirdAuthorityScope自定义表名 riskbird_authority_scopes
+func (RiskBirdAuthorityScope) TableName() string {
+	return "riskbird_authority_scopes"
+}
@@ -0,0 +1,73 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+)
+
+// RiskBirdProductCfg RiskBird p_product_cfg 中的一行配置
+type RiskBirdProductCfg struct {
+	ID       int64  `json:"id"`       // 配置ID
+	CfgValue string `json:"cfgValue"` // 配置值
+}
+
+// RiskBirdRechargeProduct RiskBird p_recharge_product 中的一个充值套餐
+type RiskBirdRechargeProduct struct {
+	ID         int64        `json:"id"`         // 套餐ID
+	Amount     common.Money `json:"amount"`     // 充值金额
+	GiftAmount common.Money `json:"giftAmount"` // 赠送金额
+}
+
+// RiskBirdConfigBaseline RiskBird 价格和充值套餐配置基线，从某个环境快照而来，用于比对和恢复
+type RiskBirdConfigBaseline struct {
+	global.GVA_MODEL
+	Name             string                    `json:"name" form:"name" gorm:"column:name;comment:基线名称;size:100;"`                                              // 基线名称
+	Env              string                    `json:"env" form:"env" gorm:"column:env;comment:快照来源环境;size:50;"`                                                // 快照来源环境
+	ProductCfgs      []RiskBirdProductCfg      `json:"productCfgs" gorm:"serializer:json;type:text;column:product_cfgs;comment:p_product_cfg快照"`                // p_product_cfg 快照
+	RechargeProducts []RiskBirdRechargeProduct `json:"rechargeProducts" gorm:"serializer:json;type:text;column:recharge_products;comment:p_recharge_product快照"` // p_recharge_product 快照
+	CreatedBy        uint                      `json:"createdBy" gorm:"column:created_by;comment:创建者;"`                                                         // 创建者
+}
+
+// TableName RiskBirdConfigBaseline自定义表名 riskbird_config_baselines
+func (RiskBirdConfigBaseline) TableName() string {
+	return "riskbird_config_baselines"
+}
+
+// 配置差异类型
+const (
+	RiskBirdConfigChanged   = "changed"    // 两边都存在但值不同
+	RiskBirdConfigOnlyLeft  = "only_left"  // 只在左侧存在
+	RiskBirdConfigOnlyRight = "only_right" // 只在右侧存在
+)
+
+// RiskBirdConfigDiff 一处配置差异
+type RiskBirdConfigDiff struct {
+	Table string `json:"table"` // 表名
+	ID    int64  `json:"id"`    // 记录ID
+	Field string `json:"field"` // 字段名，整行缺失时为空
+	Kind  string `json:"kind"`  // 差异类型
+	Left  string `json:"left"`  // 左侧的值
+	Right string `json:"right"` // 右侧的值
+}
+
+// 配置恢复结果
+const (
+	RiskBirdConfigRestoreSuccess = "success"
+	RiskBirdConfigRestoreFailed  = "failed"
+)
+
+// RiskBirdConfigRestore 按基线恢复配置的审计记录
+type RiskBirdConfigRestore struct {
+	global.GVA_MODEL
+	BaselineID uint                 `json:"baselineId" form:"baselineId" gorm:"column:baseline_id;index;comment:基线ID;"` // 基线ID
+	Env        string               `json:"env" form:"env" gorm:"column:env;comment:恢复的环境;size:50;"`                    // 恢复的环境
+	Changes    []RiskBirdConfigDiff `json:"changes" gorm:"serializer:json;type:text;column:changes;comment:恢复前的差异"`     // 恢复前的差异，Left 为环境原值，Right 为基线值
+	Status     string               `json:"status" form:"status" gorm:"column:status;comment:恢复结果;size:20;"`            // 恢复结果 success/failed
+	Error      string               `json:"error" gorm:"column:error;comment:错误信息;type:text;"`                          // 错误信息
+	UserID     uint                 `json:"userId" form:"userId" gorm:"column:user_id;comment:操作人;"`                    // 操作人
+}
+
+// TableName RiskBirdConfigRestore自定义表名 riskbird_config_restores
+func (RiskBirdConfigRestore) TableName() string {
+	return "riskbird_config_restores"
+}
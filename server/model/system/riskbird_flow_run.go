@@ -0,0 +1,74 @@
+package system
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// RiskBird 流程
+const (
+	RiskBirdFlowBalance = "balance" // 修改用户余额
+	RiskBirdFlowPoint   = "point"   // 修改用户积分
+)
+
+// 流程执行状态
+const (
+	RiskBirdFlowRunRunning = "running"
+	RiskBirdFlowRunSuccess = "success"
+	RiskBirdFlowRunFailed  = "failed"
+)
+
+// 步骤事件类型
+const (
+	RiskBirdStepStarted      = "started"      // 步骤开始
+	RiskBirdStepSucceeded    = "succeeded"    // 步骤成功
+	RiskBirdStepFailed       = "failed"       // 步骤失败
+	RiskBirdStepCompensating = "compensating" // 开始执行补偿
+	RiskBirdStepCompensated  = "compensated"  // 补偿完成
+	RiskBirdStepSkipped      = "skipped"      // 续跑时跳过断点前已完成的步骤
+	RiskBirdStepPlanned      = "planned"      // 预演时只记录计划，未执行
+	RiskBirdStepFinished     = "finished"     // 流程结束
+)
+
+// RiskBirdStepEvent 流程步骤事件
+type RiskBirdStepEvent struct {
+	RunID   uint        `json:"runId"`             // 流程执行记录ID
+	Seq     int         `json:"seq"`               // 事件序号，从1开始
+	Step    string      `json:"step"`              // 步骤名称
+	Event   string      `json:"event"`             // 事件类型
+	Message string      `json:"message,omitempty"` // 错误信息或说明
+	Payload interface{} `json:"payload,omitempty"` // 脱敏后的请求参数或结果
+	Time    time.Time   `json:"time"`              // 事件时间
+	Elapsed int64       `json:"elapsed"`           // 步骤耗时(毫秒)，开始事件为0
+}
+
+// RiskBirdFlowCheckpoint 流程失败时的断点，用于从失败的步骤继续执行
+type RiskBirdFlowCheckpoint struct {
+	Step string          `json:"step"`           // 失败的步骤，续跑时从该步骤开始
+	Redo []string        `json:"redo,omitempty"` // 失败后已补偿、续跑时需要重新执行的步骤
+	Vars json.RawMessage `json:"vars"`           // 已完成步骤的输出
+}
+
+// RiskBirdFlowRun RiskBird 流程执行记录
+type RiskBirdFlowRun struct {
+	global.GVA_MODEL
+	Flow       string              `json:"flow" form:"flow" gorm:"column:flow;index;comment:流程;size:20;"`       // 流程 balance/point
+	Env        string              `json:"env" form:"env" gorm:"column:env;comment:RiskBird环境;size:50;"`        // RiskBird环境
+	Phone      string              `json:"phone" form:"phone" gorm:"column:phone;index;comment:用户手机号;size:20;"` // 用户手机号
+	Status     string              `json:"status" form:"status" gorm:"column:status;comment:执行状态;size:20;"`     // 执行状态
+	Error      string              `json:"error" gorm:"column:error;type:text;comment:错误信息;"`                   // 错误信息
+	StartedAt  time.Time           `json:"startedAt" gorm:"column:started_at;comment:开始时间;"`                    // 开始时间
+	FinishedAt *time.Time          `json:"finishedAt" gorm:"column:finished_at;comment:结束时间;"`                  // 结束时间
+	Events     []RiskBirdStepEvent `json:"events" gorm:"serializer:json;type:text;column:events;comment:步骤事件"`  // 步骤事件
+	UserID     uint                `json:"userId" gorm:"column:user_id;comment:发起用户;"`                          // 发起用户
+
+	ResumedFrom uint                    `json:"resumedFrom" gorm:"column:resumed_from;comment:续跑的流程执行记录;"`                   // 从该执行记录的断点继续执行
+	Checkpoint  *RiskBirdFlowCheckpoint `json:"checkpoint" gorm:"serializer:json;type:text;column:checkpoint;comment:失败断点;"` // 失败时的断点，用于续跑
+}
+
+// TableName RiskBirdFlowRun自定义表名 riskbird_flow_runs
+func (RiskBirdFlowRun) TableName() string {
+	return "riskbird_flow_runs"
+}
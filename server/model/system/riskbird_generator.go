@@ -0,0 +1,110 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+)
+
+// 造数任务的积分写入方式
+const (
+	RiskBirdGeneratorStrategyAPI = "api" // 通过购买企业信用报告产生积分
+	RiskBirdGeneratorStrategyDB  = "db"  // 直接写入 point_acquisition，可生成多批积分
+)
+
+// 取值分布
+const (
+	RiskBirdDistUniform  = "uniform"   // 均匀分布
+	RiskBirdDistLongTail = "long_tail" // 长尾分布，多数取值靠近最小值，少数接近最大值
+)
+
+// 造数任务状态
+const (
+	RiskBirdGeneratorPending     = "pending"     // 等待执行
+	RiskBirdGeneratorRunning     = "running"     // 执行中
+	RiskBirdGeneratorStopped     = "stopped"     // 已手动停止，可继续执行
+	RiskBirdGeneratorInterrupted = "interrupted" // 服务重启导致中断，可继续执行
+	RiskBirdGeneratorSuccess     = "success"     // 全部账号完成
+	RiskBirdGeneratorPartial     = "partial"     // 部分账号失败，可继续执行重试失败的账号
+)
+
+// 造数账号的完成阶段，继续执行时从下一阶段开始
+const (
+	RiskBirdGeneratorStageAccount = "account" // 账号已注册或复用
+	RiskBirdGeneratorStageBalance = "balance" // 余额和订单历史已生成
+	RiskBirdGeneratorStageDone    = "done"    // 积分已生成
+)
+
+// RiskBirdMoneyRange 金额取值范围
+type RiskBirdMoneyRange struct {
+	Min  common.Money `json:"min"`  // 最小值
+	Max  common.Money `json:"max"`  // 最大值
+	Dist string       `json:"dist"` // 分布 uniform/long_tail，默认 uniform
+}
+
+// RiskBirdIntRange 整数取值范围
+type RiskBirdIntRange struct {
+	Min  int64  `json:"min"`  // 最小值
+	Max  int64  `json:"max"`  // 最大值
+	Dist string `json:"dist"` // 分布 uniform/long_tail，默认 uniform
+}
+
+// RiskBirdGeneratorParams 造数账号的数据分布
+type RiskBirdGeneratorParams struct {
+	Balance      RiskBirdMoneyRange `json:"balance"`      // 最终余额
+	Points       RiskBirdIntRange   `json:"points"`       // 可用积分
+	PointBatches RiskBirdIntRange   `json:"pointBatches"` // 积分批次数，仅 db 方式生效
+	Orders       RiskBirdIntRange   `json:"orders"`       // 充值次数，每次充值产生一组订单历史，余额为0的账号不充值
+}
+
+// RiskBirdGeneratorJob 批量造数任务
+type RiskBirdGeneratorJob struct {
+	global.GVA_MODEL
+	Name        string                  `json:"name" form:"name" gorm:"column:name;comment:任务名称;size:100;"`                     // 任务名称
+	Env         string                  `json:"env" form:"env" gorm:"column:env;comment:RiskBird环境;size:50;"`                   // RiskBird环境
+	Count       int                     `json:"count" gorm:"column:count;comment:账号数量;"`                                        // 账号数量
+	PhonePrefix string                  `json:"phonePrefix" gorm:"column:phone_prefix;comment:手机号前缀;size:11;"`                  // 手机号前缀
+	StartSeq    int                     `json:"startSeq" gorm:"column:start_seq;comment:起始序号;"`                                 // 起始序号
+	Password    string                  `json:"-" gorm:"serializer:riskbird_secret;column:password;comment:新注册账号的密码;size:255;"` // 新注册账号的密码，加密保存，只在导出清单中返回
+	AuthorityId uint                    `json:"-" gorm:"column:authority_id;comment:操作人角色ID;"`                                  // 创建或最近继续执行任务的操作人角色，任务内的修改余额和积分按该角色校验环境权限
+	Tag         string                  `json:"tag" gorm:"column:tag;comment:账号标签;size:50;"`                                    // 账号标签
+	Strategy    string                  `json:"strategy" gorm:"column:strategy;comment:积分写入方式;size:10;"`                        // 积分写入方式 api/db
+	Concurrency int                     `json:"concurrency" gorm:"column:concurrency;comment:并发数;"`                             // 并发数
+	Seed        int64                   `json:"seed" gorm:"column:seed;comment:随机种子;"`                                          // 随机种子
+	Params      RiskBirdGeneratorParams `json:"params" gorm:"serializer:json;type:text;column:params;comment:数据分布"`             // 数据分布
+	Status      string                  `json:"status" form:"status" gorm:"column:status;comment:任务状态;size:20;"`                // 任务状态
+	Succeeded   int                     `json:"succeeded" gorm:"column:succeeded;comment:已完成账号数;"`                              // 已完成账号数
+	Failed      int                     `json:"failed" gorm:"column:failed;comment:本轮失败账号数;"`                                   // 本轮执行失败的账号数
+	Error       string                  `json:"error" gorm:"column:error;type:text;comment:错误信息;"`                              // 错误信息
+	StartedAt   *time.Time              `json:"startedAt" gorm:"column:started_at;comment:最近一次开始时间;"`                           // 最近一次开始时间
+	FinishedAt  *time.Time              `json:"finishedAt" gorm:"column:finished_at;comment:最近一次结束时间;"`                         // 最近一次结束时间
+	UserID      uint                    `json:"userId" gorm:"column:user_id;comment:发起用户;"`                                     // 发起用户
+}
+
+// TableName RiskBirdGeneratorJob自定义表名 riskbird_generator_jobs
+func (RiskBirdGeneratorJob) TableName() string {
+	return "riskbird_generator_jobs"
+}
+
+// RiskBirdGeneratorItem 造数任务中的一个账号，创建任务时按分布抽样生成目标值，同时作为导出清单
+type RiskBirdGeneratorItem struct {
+	global.GVA_MODEL
+	JobID        uint         `json:"jobId" form:"jobId" gorm:"column:job_id;index;comment:造数任务ID;"` // 造数任务ID
+	Seq          int          `json:"seq" gorm:"column:seq;comment:序号;"`                             // 序号
+	Phone        string       `json:"phone" form:"phone" gorm:"column:phone;comment:手机号;size:20;"`   // 手机号
+	AccountID    uint         `json:"accountId" gorm:"column:account_id;comment:账号库ID;"`             // 账号库ID
+	UserID       int64        `json:"userId" gorm:"column:user_id;comment:RiskBird用户ID;"`            // RiskBird用户ID
+	Reused       bool         `json:"reused" gorm:"column:reused;comment:是否复用已登记账号;"`                // 是否复用已登记账号
+	Balance      common.Money `json:"balance" gorm:"column:balance;comment:目标余额;"`                   // 目标余额
+	Points       int64        `json:"points" gorm:"column:points;comment:目标积分;"`                     // 目标积分
+	PointBatches int          `json:"pointBatches" gorm:"column:point_batches;comment:积分批次数;"`       // 积分批次数
+	Orders       int          `json:"orders" gorm:"column:orders;comment:充值次数;"`                     // 充值次数
+	Stage        string       `json:"stage" form:"stage" gorm:"column:stage;comment:已完成阶段;size:20;"` // 已完成阶段
+	Error        string       `json:"error" gorm:"column:error;type:text;comment:最近一次失败原因;"`         // 最近一次失败原因
+}
+
+// TableName RiskBirdGeneratorItem自定义表名 riskbird_generator_items
+func (RiskBirdGeneratorItem) TableName() string {
+	return "riskbird_generator_items"
+}
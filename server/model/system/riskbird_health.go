@@ -0,0 +1,41 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// 健康检查项
+const (
+	RiskBirdHealthCheckDB       = "db"        // 数据库连通性
+	RiskBirdHealthCheckSchema   = "schema"    // 依赖的表和字段
+	RiskBirdHealthCheckFixture  = "fixture"   // 依赖的固定数据
+	RiskBirdHealthCheckUserAPI  = "user_api"  // 用户端接口
+	RiskBirdHealthCheckAdminAPI = "admin_api" // 后台管理接口
+	RiskBirdHealthCheckBreaker  = "breaker"   // 接口熔断器，Target 为熔断器状态
+)
+
+// RiskBirdHealthCheck 单项检查结果
+type RiskBirdHealthCheck struct {
+	Name    string `json:"name"`    // 检查项
+	Target  string `json:"target"`  // 检查对象，如表名、固定数据
+	Healthy bool   `json:"healthy"` // 是否正常
+	Message string `json:"message"` // 异常说明
+	Elapsed int64  `json:"elapsed"` // 耗时（毫秒）
+}
+
+// RiskBirdHealthReport RiskBird 环境健康检查报告
+type RiskBirdHealthReport struct {
+	global.GVA_MODEL
+	Env       string                `json:"env" form:"env" gorm:"column:env;index;comment:RiskBird环境;size:50;"`  // RiskBird环境
+	Healthy   bool                  `json:"healthy" form:"healthy" gorm:"column:healthy;comment:是否全部正常;"`        // 是否全部正常
+	Trigger   string                `json:"trigger" gorm:"column:trigger;comment:触发方式;size:20;"`                 // 触发方式 cron/manual
+	CheckedAt time.Time             `json:"checkedAt" gorm:"column:checked_at;comment:检查时间;"`                    // 检查时间
+	Checks    []RiskBirdHealthCheck `json:"checks" gorm:"serializer:json;type:text;column:checks;comment:检查项结果"` // 检查项结果
+}
+
+// TableName RiskBirdHealthReport自定义表名 riskbird_health_reports
+func (RiskBirdHealthReport) TableName() string {
+	return "riskbird_health_reports"
+}
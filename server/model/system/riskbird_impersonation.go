@@ -0,0 +1,24 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// RiskBirdImpersonationLog 代登录审计记录，角色无权代登录被拒绝时同样记录
+type RiskBirdImpersonationLog struct {
+	global.GVA_MODEL
+	Env          string `json:"env" form:"env" gorm:"column:env;index;comment:RiskBird环境;size:50;"`            // RiskBird环境
+	Flow         string `json:"flow" form:"flow" gorm:"column:flow;comment:发起代登录的流程;size:20;"`                 // 发起代登录的流程 balance/point
+	Mode         string `json:"mode" gorm:"column:mode;comment:代登录方式;size:20;"`                                // 代登录方式 admin/test-token
+	TargetUserID int64  `json:"targetUserId" form:"targetUserId" gorm:"column:target_user_id;comment:目标用户ID;"` // 目标 RiskBird 用户ID
+	Phone        string `json:"phone" form:"phone" gorm:"column:phone;index;comment:目标用户手机号;size:20;"`         // 目标用户手机号
+	OperatorID   uint   `json:"operatorId" form:"operatorId" gorm:"column:operator_id;comment:操作人;"`           // 操作人
+	AuthorityID  uint   `json:"authorityId" gorm:"column:authority_id;comment:操作人角色;"`                         // 操作人角色
+	Success      bool   `json:"success" form:"success" gorm:"column:success;comment:是否成功;"`                    // 是否成功
+	Error        string `json:"error" gorm:"column:error;comment:错误信息;type:text;"`                             // 错误信息
+}
+
+// TableName RiskBirdImpersonationLog自定义表名 riskbird_impersonation_logs
+func (RiskBirdImpersonationLog) TableName() string {
+	return "riskbird_impersonation_logs"
+}
@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// RiskBirdJobRecord RiskBird 定时任务接口调用记录
+type RiskBirdJobRecord struct {
+	global.GVA_MODEL
+	Env        string            `json:"env" form:"env" gorm:"column:env;index;comment:RiskBird环境;size:50;"`   // RiskBird环境
+	JobName    string            `json:"jobName" form:"jobName" gorm:"column:job_name;comment:任务标识;size:100;"` // 任务标识
+	Method     string            `json:"method" gorm:"column:method;comment:请求方式;size:10;"`                    // 请求方式
+	Path       string            `json:"path" gorm:"column:path;comment:接口路径;size:255;"`                       // 接口路径
+	Params     map[string]string `json:"params" gorm:"serializer:json;type:text;column:params;comment:请求参数"`   // 请求参数
+	StatusCode int               `json:"statusCode" gorm:"column:status_code;comment:HTTP状态码;"`                // HTTP状态码
+	Body       string            `json:"body" gorm:"column:body;type:text;comment:响应内容;"`                      // 响应内容
+	Error      string            `json:"error" gorm:"column:error;type:text;comment:错误信息;"`                    // 错误信息
+	Latency    int64             `json:"latency" gorm:"column:latency;comment:耗时(毫秒);"`                        // 耗时(毫秒)
+	UserID     uint              `json:"userId" gorm:"column:user_id;comment:触发用户;"`                           // 触发用户
+}
+
+// TableName RiskBirdJobRecord自定义表名 riskbird_job_records
+func (RiskBirdJobRecord) TableName() string {
+	return "riskbird_job_records"
+}
@@ -0,0 +1,56 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// 通知事件
+const (
+	RiskBirdNotifyFlowSucceeded      = "flow_succeeded"      // 异步余额、积分流程执行成功
+	RiskBirdNotifyFlowFailed         = "flow_failed"         // 异步余额、积分流程执行失败
+	RiskBirdNotifyGeneratorSucceeded = "generator_succeeded" // 造数任务全部完成
+	RiskBirdNotifyGeneratorFailed    = "generator_failed"    // 造数任务部分失败
+	RiskBirdNotifyResetSucceeded     = "reset_succeeded"     // 重置计划全部账号重置成功
+	RiskBirdNotifyResetFailed        = "reset_failed"        // 重置计划有账号重置失败
+	RiskBirdNotifyReconcileMismatch  = "reconcile_mismatch"  // 定时账务核对发现不一致
+)
+
+// RiskBirdNotifyEvents 全部通知事件
+var RiskBirdNotifyEvents = []string{
+	RiskBirdNotifyFlowSucceeded,
+	RiskBirdNotifyFlowFailed,
+	RiskBirdNotifyGeneratorSucceeded,
+	RiskBirdNotifyGeneratorFailed,
+	RiskBirdNotifyResetSucceeded,
+	RiskBirdNotifyResetFailed,
+	RiskBirdNotifyReconcileMismatch,
+}
+
+// RiskBirdNotifyPreference 用户的通知偏好。流程和造数事件只通知发起人，重置和核对事件通知全部订阅的用户
+type RiskBirdNotifyPreference struct {
+	global.GVA_MODEL
+	UserID         uint     `json:"userId" gorm:"column:user_id;uniqueIndex;comment:用户ID;"`              // 用户ID
+	Events         []string `json:"events" gorm:"serializer:json;type:text;column:events;comment:接收的事件"` // 接收的事件
+	EmailEnabled   bool     `json:"emailEnabled" gorm:"column:email_enabled;comment:是否发送邮件;"`            // 是否发送邮件
+	Email          string   `json:"email" gorm:"column:email;comment:收件邮箱;size:255;"`                    // 收件邮箱，为空时使用用户信息中的邮箱
+	WebhookEnabled bool     `json:"webhookEnabled" gorm:"column:webhook_enabled;comment:是否推送webhook;"`   // 是否推送到配置的 webhook
+}
+
+// TableName RiskBirdNotifyPreference自定义表名 riskbird_notify_preferences
+func (RiskBirdNotifyPreference) TableName() string {
+	return "riskbird_notify_preferences"
+}
+
+// RiskBirdNotification 一条完成通知，也是 webhook 的请求体
+type RiskBirdNotification struct {
+	Event    string      `json:"event"`          // 通知事件
+	Title    string      `json:"title"`          // 标题
+	Summary  []string    `json:"summary"`        // 任务摘要，每行一项
+	Link     string      `json:"link"`           // 详情链接
+	UserID   uint        `json:"userId"`         // 接收人，广播通知推送 webhook 时为0
+	Username string      `json:"username"`       // 接收人用户名
+	Data     interface{} `json:"data,omitempty"` // 任务记录
+	Time     time.Time   `json:"time"`           // 通知时间
+}
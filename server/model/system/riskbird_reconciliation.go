@@ -0,0 +1,45 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+)
+
+// 账务核对项
+const (
+	RiskBirdLedgerBalance = "balance" // 接口返回的总余额与余额账户表合计
+	RiskBirdLedgerPoints  = "points"  // 接口返回的可用积分与 point_acquisition 未过期剩余积分合计
+)
+
+// RiskBirdLedgerMismatch 一项不一致的账务，Rows 为参与计算的数据库记录
+type RiskBirdLedgerMismatch struct {
+	Item string      `json:"item"` // 核对项
+	API  string      `json:"api"`  // 接口返回值
+	DB   string      `json:"db"`   // 按数据库计算的期望值
+	Rows interface{} `json:"rows"` // 参与计算的数据库记录
+}
+
+// RiskBirdReconciliation 账号库中一个账号的账务核对结果
+type RiskBirdReconciliation struct {
+	global.GVA_MODEL
+	AccountID  uint                     `json:"accountId" form:"accountId" gorm:"column:account_id;index;comment:账号库ID;"`     // 账号库ID
+	Env        string                   `json:"env" form:"env" gorm:"column:env;index;comment:RiskBird环境;size:50;"`           // RiskBird环境
+	Phone      string                   `json:"phone" form:"phone" gorm:"column:phone;comment:用户手机号;size:20;"`                // 用户手机号
+	UserID     int64                    `json:"userId" gorm:"column:user_id;comment:RiskBird用户ID;"`                           // RiskBird用户ID
+	Trigger    string                   `json:"trigger" gorm:"column:trigger;comment:触发方式;size:20;"`                          // 触发方式 cron/manual
+	APIBalance common.Money             `json:"apiBalance" gorm:"column:api_balance;comment:接口返回的总余额;"`                       // 接口返回的总余额
+	DBBalance  common.Money             `json:"dbBalance" gorm:"column:db_balance;comment:数据库计算的总余额;"`                        // 数据库计算的总余额
+	APIPoints  int64                    `json:"apiPoints" gorm:"column:api_points;comment:接口返回的可用积分;"`                        // 接口返回的可用积分
+	DBPoints   int64                    `json:"dbPoints" gorm:"column:db_points;comment:数据库计算的可用积分;"`                         // 数据库计算的可用积分
+	Matched    bool                     `json:"matched" form:"matched" gorm:"column:matched;comment:是否一致;"`                   // 是否一致，核对出错时为 false
+	Mismatches []RiskBirdLedgerMismatch `json:"mismatches" gorm:"serializer:json;type:text;column:mismatches;comment:不一致的账务"` // 不一致的账务
+	Error      string                   `json:"error" gorm:"column:error;comment:核对失败原因;type:text;"`                          // 核对失败原因
+	CheckedAt  time.Time                `json:"checkedAt" gorm:"column:checked_at;comment:核对时间;"`                             // 核对时间
+}
+
+// TableName RiskBirdReconciliation自定义表名 riskbird_reconciliations
+func (RiskBirdReconciliation) TableName() string {
+	return "riskbird_reconciliations"
+}
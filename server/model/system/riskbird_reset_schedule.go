@@ -0,0 +1,66 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+)
+
+// RiskBirdResetAccount 需要定时重置的 RiskBird 账号，引用账号库中的账号，执行时从账号库读取登录密码
+type RiskBirdResetAccount struct {
+	AccountID uint   `json:"accountId"` // 账号库ID
+	Env       string `json:"env"`       // RiskBird环境，保存计划时按账号库填写
+	Phone     string `json:"phone"`     // 用户手机号，保存计划时按账号库填写
+}
+
+// RiskBirdResetSchedule RiskBird 测试账号定时重置计划
+type RiskBirdResetSchedule struct {
+	global.GVA_MODEL
+	Name           string                 `json:"name" form:"name" gorm:"column:name;comment:计划名称;size:100;" binding:"required"`    // 计划名称
+	Spec           string                 `json:"spec" form:"spec" gorm:"column:spec;comment:cron表达式;size:100;" binding:"required"` // cron表达式
+	Accounts       []RiskBirdResetAccount `json:"accounts" gorm:"serializer:json;type:text;column:accounts;comment:重置账号列表"`         // 重置账号列表
+	RechargeAmount common.Money           `json:"rechargeAmount" gorm:"column:recharge_amount;type:decimal(20,2);comment:基准充值金额;"`  // 基准充值金额
+	GiftAmount     common.Money           `json:"giftAmount" gorm:"column:gift_amount;type:decimal(20,2);comment:基准赠送金额;"`          // 基准赠送金额
+	PointAmount    int64                  `json:"pointAmount" form:"pointAmount" gorm:"column:point_amount;comment:基准积分;"`          // 基准积分
+	Enabled        bool                   `json:"enabled" form:"enabled" gorm:"column:enabled;comment:是否启用;"`                       // 是否启用
+	AuthorityId    uint                   `json:"-" gorm:"column:authority_id;comment:操作人角色ID;"`                                    // 创建或最近修改计划的操作人角色，执行时按该角色校验环境权限
+	LastRunAt      *time.Time             `json:"lastRunAt" gorm:"column:last_run_at;comment:上次执行时间;"`                              // 上次执行时间
+	LastStatus     string                 `json:"lastStatus" gorm:"column:last_status;comment:上次执行结果;size:20;"`                     // 上次执行结果
+	NextRunAt      *time.Time             `json:"nextRunAt" gorm:"-"`                                                               // 下次执行时间
+}
+
+// TableName RiskBirdResetSchedule自定义表名 riskbird_reset_schedules
+func (RiskBirdResetSchedule) TableName() string {
+	return "riskbird_reset_schedules"
+}
+
+const (
+	RiskBirdResetStatusSuccess = "success" // 全部账号重置成功
+	RiskBirdResetStatusPartial = "partial" // 部分账号重置失败
+	RiskBirdResetStatusFailed  = "failed"  // 全部账号重置失败
+)
+
+// RiskBirdResetAccountResult 单个账号的重置结果
+type RiskBirdResetAccountResult struct {
+	Env          string `json:"env"`          // RiskBird环境，早期记录为空，按默认环境处理
+	Phone        string `json:"phone"`        // 用户手机号
+	BalanceError string `json:"balanceError"` // 余额重置错误信息，为空表示成功
+	PointError   string `json:"pointError"`   // 积分重置错误信息，为空表示成功
+}
+
+// RiskBirdResetRun RiskBird 账号重置执行记录
+type RiskBirdResetRun struct {
+	global.GVA_MODEL
+	ScheduleID uint                         `json:"scheduleId" form:"scheduleId" gorm:"column:schedule_id;index;comment:重置计划ID;"` // 重置计划ID
+	Trigger    string                       `json:"trigger" gorm:"column:trigger;comment:触发方式;size:20;"`                          // 触发方式 cron/manual/mcp/cli
+	Status     string                       `json:"status" gorm:"column:status;comment:执行结果;size:20;"`                            // 执行结果
+	StartedAt  time.Time                    `json:"startedAt" gorm:"column:started_at;comment:开始时间;"`                             // 开始时间
+	FinishedAt time.Time                    `json:"finishedAt" gorm:"column:finished_at;comment:结束时间;"`                           // 结束时间
+	Results    []RiskBirdResetAccountResult `json:"results" gorm:"serializer:json;type:text;column:results;comment:账号重置结果"`       // 账号重置结果
+}
+
+// TableName RiskBirdResetRun自定义表名 riskbird_reset_runs
+func (RiskBirdResetRun) TableName() string {
+	return "riskbird_reset_runs"
+}
@@ -0,0 +1,107 @@
+package system
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"gorm.io/gorm/schema"
+)
+
+// RiskBirdSecretPrefix 加密后的密码前缀，没有前缀的是迁移前保存的明文
+const RiskBirdSecretPrefix = "enc:"
+
+// ErrRiskBirdSecretKey 未配置密钥时无法保存密码
+var ErrRiskBirdSecretKey = errors.New("未配置 riskbird.secret-key，无法保存登录密码，请配置密钥或使用验证码登录")
+
+func init() {
+	schema.RegisterSerializer("riskbird_secret", RiskBirdSecretSerializer{})
+}
+
+// RiskBirdSecretSerializer 登录密码字段的 gorm 序列化器，写入时使用 riskbird.secret-key 以 AES-GCM 加密，读取时解密
+type RiskBirdSecretSerializer struct{}
+
+// Scan 读取时解密，迁移前保存的明文原样返回
+func (RiskBirdSecretSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("riskbird_secret: invalid value type %T", dbValue)
+	}
+	plain, err := DecryptRiskBirdSecret(value)
+	if err != nil {
+		return err
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plain)
+	return nil
+}
+
+// Value 写入时加密，空密码保存为空
+func (RiskBirdSecretSerializer) Value(_ context.Context, _ *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, _ := fieldValue.(string)
+	return EncryptRiskBirdSecret(plain)
+}
+
+// riskBirdSecretAEAD 由配置的密钥派生 AES-256 密钥
+func riskBirdSecretAEAD() (cipher.AEAD, error) {
+	secret := global.GVA_CONFIG.RiskBird.SecretKey
+	if secret == "" {
+		return nil, ErrRiskBirdSecretKey
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptRiskBirdSecret 加密登录密码，空密码返回空
+func EncryptRiskBirdSecret(plain string) (string, error) {
+	if plain == "" {
+		return plain, nil
+	}
+	aead, err := riskBirdSecretAEAD()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return RiskBirdSecretPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(plain), nil)), nil
+}
+
+// DecryptRiskBirdSecret 解密登录密码，没有加密前缀的明文原样返回
+func DecryptRiskBirdSecret(value string) (string, error) {
+	data, ok := strings.CutPrefix(value, RiskBirdSecretPrefix)
+	if !ok {
+		return value, nil
+	}
+	aead, err := riskBirdSecretAEAD()
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", errors.New("登录密码密文格式错误")
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("解密登录密码失败，请检查 riskbird.secret-key 是否变更")
+	}
+	return string(plain), nil
+}
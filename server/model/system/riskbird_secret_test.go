@@ -0,0 +1,69 @@
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRiskBirdSecret(t *testing.T) {
+	global.GVA_CONFIG.RiskBird.SecretKey = ""
+	if _, err := EncryptRiskBirdSecret("pwd"); !errors.Is(err, ErrRiskBirdSecretKey) {
+		t.Fatalf("EncryptRiskBirdSecret() without key error = %v, want ErrRiskBirdSecretKey", err)
+	}
+	if got, err := EncryptRiskBirdSecret(""); err != nil || got != "" {
+		t.Errorf("EncryptRiskBirdSecret(\"\") = %q, %v, want empty", got, err)
+	}
+
+	global.GVA_CONFIG.RiskBird.SecretKey = "test-key"
+	encrypted, err := EncryptRiskBirdSecret("pwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encrypted, RiskBirdSecretPrefix) || strings.Contains(encrypted, "pwd") {
+		t.Errorf("EncryptRiskBirdSecret() = %q, want ciphertext", encrypted)
+	}
+	if got, err := DecryptRiskBirdSecret(encrypted); err != nil || got != "pwd" {
+		t.Errorf("DecryptRiskBirdSecret() = %q, %v, want pwd", got, err)
+	}
+	// 迁移前的明文原样读取
+	if got, err := DecryptRiskBirdSecret("plain"); err != nil || got != "plain" {
+		t.Errorf("DecryptRiskBirdSecret(plain) = %q, %v, want plain", got, err)
+	}
+
+	global.GVA_CONFIG.RiskBird.SecretKey = "other-key"
+	if _, err := DecryptRiskBirdSecret(encrypted); err == nil {
+		t.Error("DecryptRiskBirdSecret() with another key want error")
+	}
+}
+
+func TestRiskBirdSecretSerializer(t *testing.T) {
+	global.GVA_CONFIG.RiskBird.SecretKey = "test-key"
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&RiskBirdAccount{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Create(&RiskBirdAccount{Env: "default", Phone: "13800000000", Password: "pwd"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var stored string
+	db.Table(RiskBirdAccount{}.TableName()).Select("password").Scan(&stored)
+	if !strings.HasPrefix(stored, RiskBirdSecretPrefix) {
+		t.Errorf("stored password = %q, want ciphertext", stored)
+	}
+	var account RiskBirdAccount
+	if err = db.First(&account).Error; err != nil {
+		t.Fatal(err)
+	}
+	if account.Password != "pwd" {
+		t.Errorf("read password = %q, want pwd", account.Password)
+	}
+}
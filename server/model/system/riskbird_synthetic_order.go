@@ -0,0 +1,42 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+)
+
+// 合成订单类型
+const (
+	RiskBirdOrderKindReportPreOrder   = "report_pre_order"   // 企业信用报告预订单
+	RiskBirdOrderKindReportOrder      = "report_order"       // 企业信用报告订单
+	RiskBirdOrderKindRechargePreOrder = "recharge_pre_order" // 充值预订单
+	RiskBirdOrderKindRechargeOrder    = "recharge_order"     // 充值订单
+)
+
+// 合成订单状态
+const (
+	RiskBirdOrderStatusActive  = "active"  // 仍存在于 RiskBird
+	RiskBirdOrderStatusCleaned = "cleaned" // 已清理
+)
+
+// RiskBirdSyntheticOrder 余额、积分流程在 RiskBird 中创建的合成订单
+type RiskBirdSyntheticOrder struct {
+	global.GVA_MODEL
+	Env         string       `json:"env" form:"env" gorm:"column:env;index;comment:RiskBird环境;size:50;"`           // RiskBird环境
+	Phone       string       `json:"phone" form:"phone" gorm:"column:phone;index;comment:用户手机号;size:20;"`          // 用户手机号
+	UserID      int64        `json:"userId" form:"userId" gorm:"column:user_id;comment:RiskBird用户ID;"`             // RiskBird用户ID
+	Flow        string       `json:"flow" form:"flow" gorm:"column:flow;comment:来源流程;size:20;"`                    // 来源流程 balance/point
+	Kind        string       `json:"kind" form:"kind" gorm:"column:kind;comment:订单类型;size:30;"`                    // 订单类型
+	OrderNo     string       `json:"orderNo" form:"orderNo" gorm:"column:order_no;index;comment:订单号;size:64;"`     // 订单号
+	Amount      common.Money `json:"amount" gorm:"column:amount;type:decimal(20,2);comment:订单金额;"`                 // 订单金额
+	Status      string       `json:"status" form:"status" gorm:"column:status;comment:状态;size:20;default:active;"` // 状态
+	CleanAction string       `json:"cleanAction" gorm:"column:clean_action;comment:清理方式;size:20;"`                 // 清理方式
+	CleanedAt   *time.Time   `json:"cleanedAt" gorm:"column:cleaned_at;comment:清理时间;"`                             // 清理时间
+}
+
+// TableName RiskBirdSyntheticOrder自定义表名 riskbird_synthetic_orders
+func (RiskBirdSyntheticOrder) TableName() string {
+	return "riskbird_synthetic_orders"
+}
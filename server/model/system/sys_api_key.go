@@ -0,0 +1,43 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+// SysApiKey 服务账号API密钥，可代替 x-token 调用接口，权限由绑定的角色决定
+type SysApiKey struct {
+	global.GVA_MODEL
+	Name        string       `json:"name" form:"name" gorm:"column:name;comment:密钥名称;size:100;"`                // 密钥名称
+	KeyPrefix   string       `json:"keyPrefix" gorm:"column:key_prefix;comment:密钥前缀;size:20;"`                  // 密钥前缀，用于识别密钥
+	KeyHash     string       `json:"-" gorm:"column:key_hash;uniqueIndex;comment:密钥哈希;size:64;"`                // 密钥SHA-256哈希
+	UserID      uint         `json:"userId" form:"userId" gorm:"column:user_id;index;comment:服务账号ID;"`          // 服务账号ID
+	User        SysUser      `json:"user" gorm:"foreignKey:UserID;references:ID;comment:服务账号"`                  // 服务账号
+	AuthorityId uint         `json:"authorityId" gorm:"column:authority_id;comment:角色ID;"`                      // 角色ID
+	Authority   SysAuthority `json:"authority" gorm:"foreignKey:AuthorityId;references:AuthorityId;comment:角色"` // 角色
+	ExpiresAt   *time.Time   `json:"expiresAt" gorm:"column:expires_at;comment:过期时间;"`                          // 过期时间，为空表示永不过期
+	RevokedAt   *time.Time   `json:"revokedAt" gorm:"column:revoked_at;comment:吊销时间;"`                          // 吊销时间
+	LastUsedAt  *time.Time   `json:"lastUsedAt" gorm:"column:last_used_at;comment:最近使用时间;"`                     // 最近使用时间
+	UsageCount  int64        `json:"usageCount" gorm:"column:usage_count;comment:使用次数;"`                        // 使用次数
+	CreatedBy   uint         `json:"createdBy" gorm:"column:created_by;comment:创建人;"`                           // 创建人
+}
+
+func (SysApiKey) TableName() string {
+	return "sys_api_keys"
+}
+
+// SysApiKeyUsage API密钥调用记录
+type SysApiKeyUsage struct {
+	global.GVA_MODEL
+	ApiKeyID uint   `json:"apiKeyId" form:"apiKeyId" gorm:"column:api_key_id;index;comment:API密钥ID;"` // API密钥ID
+	Method   string `json:"method" gorm:"column:method;comment:请求方法;size:10;"`                        // 请求方法
+	Path     string `json:"path" gorm:"column:path;comment:请求路径;size:255;"`                           // 请求路径
+	Status   int    `json:"status" gorm:"column:status;comment:响应状态码;"`                               // 响应状态码
+	Ip       string `json:"ip" gorm:"column:ip;comment:请求IP;size:64;"`                                // 请求IP
+	Latency  int64  `json:"latency" gorm:"column:latency;comment:耗时(毫秒);"`                            // 耗时(毫秒)
+}
+
+func (SysApiKeyUsage) TableName() string {
+	return "sys_api_key_usages"
+}
@@ -21,30 +21,56 @@ type RouterGroup struct {
 	SysParamsRouter
 	SysVersionRouter
 	SysErrorRouter
+	ApiKeyRouter
+	RiskBirdResetScheduleRouter
+	RiskBirdJobRouter
+	RiskBirdPointAuditRouter
+	RiskBirdSyntheticOrderRouter
+	RiskBirdAccountRouter
+	RiskBirdFlowRouter
+	RiskBirdHealthRouter
+	RiskBirdConfigRouter
+	RiskBirdImpersonationRouter
+	RiskBirdReconciliationRouter
+	RiskBirdGeneratorRouter
+	RiskBirdNotifyRouter
 }
 
 var (
-	dbApi               = api.ApiGroupApp.SystemApiGroup.DBApi
-	jwtApi              = api.ApiGroupApp.SystemApiGroup.JwtApi
-	baseApi             = api.ApiGroupApp.SystemApiGroup.BaseApi
-	casbinApi           = api.ApiGroupApp.SystemApiGroup.CasbinApi
-	systemApi           = api.ApiGroupApp.SystemApiGroup.SystemApi
-	sysParamsApi        = api.ApiGroupApp.SystemApiGroup.SysParamsApi
-	autoCodeApi         = api.ApiGroupApp.SystemApiGroup.AutoCodeApi
-	authorityApi        = api.ApiGroupApp.SystemApiGroup.AuthorityApi
-	apiRouterApi        = api.ApiGroupApp.SystemApiGroup.SystemApiApi
-	dictionaryApi       = api.ApiGroupApp.SystemApiGroup.DictionaryApi
-	authorityBtnApi     = api.ApiGroupApp.SystemApiGroup.AuthorityBtnApi
-	authorityMenuApi    = api.ApiGroupApp.SystemApiGroup.AuthorityMenuApi
-	autoCodePluginApi   = api.ApiGroupApp.SystemApiGroup.AutoCodePluginApi
-	autocodeHistoryApi  = api.ApiGroupApp.SystemApiGroup.AutoCodeHistoryApi
-	operationRecordApi  = api.ApiGroupApp.SystemApiGroup.OperationRecordApi
-	autoCodePackageApi  = api.ApiGroupApp.SystemApiGroup.AutoCodePackageApi
-	dictionaryDetailApi = api.ApiGroupApp.SystemApiGroup.DictionaryDetailApi
-	autoCodeTemplateApi = api.ApiGroupApp.SystemApiGroup.AutoCodeTemplateApi
-	exportTemplateApi   = api.ApiGroupApp.SystemApiGroup.SysExportTemplateApi
-	sysVersionApi       = api.ApiGroupApp.SystemApiGroup.SysVersionApi
-	sysErrorApi         = api.ApiGroupApp.SystemApiGroup.SysErrorApi
-	userBalanceApi      = api.ApiGroupApp.SystemApiGroup.UserBalanceApi
-	userPointApi        = api.ApiGroupApp.SystemApiGroup.UserPointApi
+	dbApi                     = api.ApiGroupApp.SystemApiGroup.DBApi
+	jwtApi                    = api.ApiGroupApp.SystemApiGroup.JwtApi
+	baseApi                   = api.ApiGroupApp.SystemApiGroup.BaseApi
+	casbinApi                 = api.ApiGroupApp.SystemApiGroup.CasbinApi
+	systemApi                 = api.ApiGroupApp.SystemApiGroup.SystemApi
+	sysParamsApi              = api.ApiGroupApp.SystemApiGroup.SysParamsApi
+	autoCodeApi               = api.ApiGroupApp.SystemApiGroup.AutoCodeApi
+	authorityApi              = api.ApiGroupApp.SystemApiGroup.AuthorityApi
+	apiRouterApi              = api.ApiGroupApp.SystemApiGroup.SystemApiApi
+	dictionaryApi             = api.ApiGroupApp.SystemApiGroup.DictionaryApi
+	authorityBtnApi           = api.ApiGroupApp.SystemApiGroup.AuthorityBtnApi
+	authorityMenuApi          = api.ApiGroupApp.SystemApiGroup.AuthorityMenuApi
+	autoCodePluginApi         = api.ApiGroupApp.SystemApiGroup.AutoCodePluginApi
+	autocodeHistoryApi        = api.ApiGroupApp.SystemApiGroup.AutoCodeHistoryApi
+	operationRecordApi        = api.ApiGroupApp.SystemApiGroup.OperationRecordApi
+	autoCodePackageApi        = api.ApiGroupApp.SystemApiGroup.AutoCodePackageApi
+	dictionaryDetailApi       = api.ApiGroupApp.SystemApiGroup.DictionaryDetailApi
+	autoCodeTemplateApi       = api.ApiGroupApp.SystemApiGroup.AutoCodeTemplateApi
+	exportTemplateApi         = api.ApiGroupApp.SystemApiGroup.SysExportTemplateApi
+	sysVersionApi             = api.ApiGroupApp.SystemApiGroup.SysVersionApi
+	sysErrorApi               = api.ApiGroupApp.SystemApiGroup.SysErrorApi
+	apiKeyApi                 = api.ApiGroupApp.SystemApiGroup.ApiKeyApi
+	userBalanceApi            = api.ApiGroupApp.SystemApiGroup.UserBalanceApi
+	userPointApi              = api.ApiGroupApp.SystemApiGroup.UserPointApi
+	riskBirdResetScheduleApi  = api.ApiGroupApp.SystemApiGroup.RiskBirdResetScheduleApi
+	riskBirdJobApi            = api.ApiGroupApp.SystemApiGroup.RiskBirdJobApi
+	riskBirdPointAuditApi     = api.ApiGroupApp.SystemApiGroup.RiskBirdPointAuditApi
+	riskBirdSyntheticOrderApi = api.ApiGroupApp.SystemApiGroup.RiskBirdSyntheticOrderApi
+	riskBirdAccountApi        = api.ApiGroupApp.SystemApiGroup.RiskBirdAccountApi
+	riskBirdFlowApi           = api.ApiGroupApp.SystemApiGroup.RiskBirdFlowApi
+	riskBirdHealthApi         = api.ApiGroupApp.SystemApiGroup.RiskBirdHealthApi
+	riskBirdConfigApi         = api.ApiGroupApp.SystemApiGroup.RiskBirdConfigApi
+	riskBirdImpersonationApi  = api.ApiGroupApp.SystemApiGroup.RiskBirdImpersonationApi
+	riskBirdReconciliationApi = api.ApiGroupApp.SystemApiGroup.RiskBirdReconciliationApi
+	riskBirdGeneratorApi      = api.ApiGroupApp.SystemApiGroup.RiskBirdGeneratorApi
+	riskBirdNotifyApi         = api.ApiGroupApp.SystemApiGroup.RiskBirdNotifyApi
 )
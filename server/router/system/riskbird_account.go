@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdAccountRouter struct{}
+
+// InitRiskBirdAccountRouter 初始化 RiskBird账号库 路由信息
+func (s *RiskBirdAccountRouter) InitRiskBirdAccountRouter(Router *gin.RouterGroup) {
+	accountRouter := Router.Group("riskbird/account").Use(middleware.OperationRecord())
+	accountRouterWithoutRecord := Router.Group("riskbird/account")
+	{
+		accountRouter.POST("createAccount", riskBirdAccountApi.CreateAccount)       // 登记账号
+		accountRouter.DELETE("deleteAccount", riskBirdAccountApi.DeleteAccount)     // 删除账号
+		accountRouter.PUT("updateAccount", riskBirdAccountApi.UpdateAccount)        // 更新账号
+		accountRouter.POST("provisionAccount", riskBirdAccountApi.ProvisionAccount) // 注册并登记测试账号
+	}
+	{
+		accountRouterWithoutRecord.GET("findAccount", riskBirdAccountApi.FindAccount)               // 根据ID获取账号
+		accountRouterWithoutRecord.GET("getAccountList", riskBirdAccountApi.GetAccountList)         // 获取账号列表
+		accountRouterWithoutRecord.GET("getAccountTimeline", riskBirdAccountApi.GetAccountTimeline) // 获取账号变更时间线
+	}
+}
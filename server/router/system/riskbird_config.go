@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdConfigRouter struct{}
+
+// InitRiskBirdConfigRouter 初始化 RiskBird配置比对 路由信息
+func (s *RiskBirdConfigRouter) InitRiskBirdConfigRouter(Router *gin.RouterGroup) {
+	configRouter := Router.Group("riskbird/config").Use(middleware.OperationRecord())
+	configRouterWithoutRecord := Router.Group("riskbird/config")
+	{
+		configRouter.POST("createBaseline", riskBirdConfigApi.CreateBaseline)   // 从环境快照创建基线
+		configRouter.DELETE("deleteBaseline", riskBirdConfigApi.DeleteBaseline) // 删除基线
+		configRouter.POST("restoreBaseline", riskBirdConfigApi.RestoreBaseline) // 按基线恢复环境配置
+	}
+	{
+		configRouterWithoutRecord.GET("diffConfig", riskBirdConfigApi.DiffConfig)           // 比对配置
+		configRouterWithoutRecord.GET("findBaseline", riskBirdConfigApi.FindBaseline)       // 根据ID获取基线
+		configRouterWithoutRecord.GET("getBaselineList", riskBirdConfigApi.GetBaselineList) // 获取基线列表
+		configRouterWithoutRecord.GET("getRestoreList", riskBirdConfigApi.GetRestoreList)   // 获取恢复记录
+	}
+}
@@ -0,0 +1,23 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdFlowRouter struct{}
+
+// InitRiskBirdFlowRouter 初始化 RiskBird流程 路由信息
+func (s *RiskBirdFlowRouter) InitRiskBirdFlowRouter(Router *gin.RouterGroup) {
+	flowRouter := Router.Group("riskbird/flow").Use(middleware.OperationRecord())
+	flowRouterWithoutRecord := Router.Group("riskbird/flow")
+	{
+		flowRouter.POST("startModifyUserBalance", riskBirdFlowApi.StartModifyUserBalance) // 异步修改用户余额
+		flowRouter.POST("startModifyUserPoint", riskBirdFlowApi.StartModifyUserPoint)     // 异步修改用户积分
+	}
+	{
+		flowRouterWithoutRecord.GET("streamRunEvents", riskBirdFlowApi.StreamRunEvents) // 订阅流程步骤事件
+		flowRouterWithoutRecord.GET("findFlowRun", riskBirdFlowApi.FindFlowRun)         // 根据ID获取流程执行记录
+		flowRouterWithoutRecord.GET("getFlowRunList", riskBirdFlowApi.GetFlowRunList)   // 获取流程执行记录列表
+	}
+}
@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdGeneratorRouter struct{}
+
+// InitRiskBirdGeneratorRouter 初始化 RiskBird批量造数 路由信息
+func (s *RiskBirdGeneratorRouter) InitRiskBirdGeneratorRouter(Router *gin.RouterGroup) {
+	generatorRouter := Router.Group("riskbird/generator").Use(middleware.OperationRecord())
+	generatorRouterWithoutRecord := Router.Group("riskbird/generator")
+	{
+		generatorRouter.POST("createJob", riskBirdGeneratorApi.CreateJob) // 创建造数任务
+		generatorRouter.POST("resumeJob", riskBirdGeneratorApi.ResumeJob) // 继续执行造数任务
+		generatorRouter.POST("stopJob", riskBirdGeneratorApi.StopJob)     // 停止造数任务
+	}
+	{
+		generatorRouterWithoutRecord.GET("findJob", riskBirdGeneratorApi.FindJob)               // 根据ID获取造数任务
+		generatorRouterWithoutRecord.GET("getJobList", riskBirdGeneratorApi.GetJobList)         // 获取造数任务列表
+		generatorRouterWithoutRecord.GET("getItemList", riskBirdGeneratorApi.GetItemList)       // 获取账号清单
+		generatorRouterWithoutRecord.GET("exportManifest", riskBirdGeneratorApi.ExportManifest) // 导出账号清单
+	}
+}
@@ -0,0 +1,17 @@
+package system
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdHealthRouter struct{}
+
+// InitRiskBirdHealthRouter 初始化 RiskBird环境健康检查 路由信息
+func (s *RiskBirdHealthRouter) InitRiskBirdHealthRouter(Router *gin.RouterGroup) {
+	healthRouterWithoutRecord := Router.Group("riskbird/health")
+	{
+		healthRouterWithoutRecord.GET("checkEnv", riskBirdHealthApi.CheckEnv)                       // 立即检查环境
+		healthRouterWithoutRecord.GET("getLatestReports", riskBirdHealthApi.GetLatestReports)       // 获取各环境最近一次检查报告
+		healthRouterWithoutRecord.GET("getHealthReportList", riskBirdHealthApi.GetHealthReportList) // 获取检查报告列表
+	}
+}
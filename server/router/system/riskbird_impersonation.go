@@ -0,0 +1,15 @@
+package system
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdImpersonationRouter struct{}
+
+// InitRiskBirdImpersonationRouter 初始化 RiskBird代登录审计 路由信息
+func (s *RiskBirdImpersonationRouter) InitRiskBirdImpersonationRouter(Router *gin.RouterGroup) {
+	impersonationRouterWithoutRecord := Router.Group("riskbird/impersonation")
+	{
+		impersonationRouterWithoutRecord.GET("getImpersonationLogList", riskBirdImpersonationApi.GetImpersonationLogList) // 获取代登录审计记录
+	}
+}
@@ -0,0 +1,22 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdJobRouter struct{}
+
+// InitRiskBirdJobRouter 初始化 RiskBird定时任务 路由信息
+func (s *RiskBirdJobRouter) InitRiskBirdJobRouter(Router *gin.RouterGroup) {
+	jobRouter := Router.Group("riskbird/job").Use(middleware.OperationRecord())
+	jobRouterWithoutRecord := Router.Group("riskbird/job")
+	{
+		jobRouter.POST("triggerJob", riskBirdJobApi.TriggerJob) // 触发定时任务
+	}
+	{
+		jobRouterWithoutRecord.GET("getJobCatalog", riskBirdJobApi.GetJobCatalog)       // 获取定时任务目录
+		jobRouterWithoutRecord.GET("findJobRecord", riskBirdJobApi.FindJobRecord)       // 根据ID获取调用记录
+		jobRouterWithoutRecord.GET("getJobRecordList", riskBirdJobApi.GetJobRecordList) // 获取调用记录列表
+	}
+}
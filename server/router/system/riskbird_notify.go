@@ -0,0 +1,21 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdNotifyRouter struct{}
+
+// InitRiskBirdNotifyRouter 初始化 RiskBird完成通知 路由信息
+func (s *RiskBirdNotifyRouter) InitRiskBirdNotifyRouter(Router *gin.RouterGroup) {
+	notifyRouter := Router.Group("riskbird/notify").Use(middleware.OperationRecord())
+	notifyRouterWithoutRecord := Router.Group("riskbird/notify")
+	{
+		notifyRouter.PUT("setPreference", riskBirdNotifyApi.SetPreference)                // 设置通知偏好
+		notifyRouter.POST("sendTestNotification", riskBirdNotifyApi.SendTestNotification) // 发送测试通知
+	}
+	{
+		notifyRouterWithoutRecord.GET("getPreference", riskBirdNotifyApi.GetPreference) // 获取通知偏好
+	}
+}
@@ -0,0 +1,20 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdPointAuditRouter struct{}
+
+// InitRiskBirdPointAuditRouter 初始化 RiskBird积分审核 路由信息
+func (s *RiskBirdPointAuditRouter) InitRiskBirdPointAuditRouter(Router *gin.RouterGroup) {
+	pointAuditRouter := Router.Group("riskbird/pointAudit").Use(middleware.OperationRecord())
+	pointAuditRouterWithoutRecord := Router.Group("riskbird/pointAudit")
+	{
+		pointAuditRouter.POST("auditPointAcquisitions", riskBirdPointAuditApi.AuditPointAcquisitions) // 批量审核积分获取记录
+	}
+	{
+		pointAuditRouterWithoutRecord.GET("getPendingPointList", riskBirdPointAuditApi.GetPendingPointList) // 获取待审核积分获取记录
+	}
+}
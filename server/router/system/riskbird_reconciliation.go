@@ -0,0 +1,16 @@
+package system
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdReconciliationRouter struct{}
+
+// InitRiskBirdReconciliationRouter 初始化 RiskBird账务核对 路由信息
+func (s *RiskBirdReconciliationRouter) InitRiskBirdReconciliationRouter(Router *gin.RouterGroup) {
+	reconcileRouterWithoutRecord := Router.Group("riskbird/reconcile")
+	{
+		reconcileRouterWithoutRecord.POST("reconcileAccounts", riskBirdReconciliationApi.ReconcileAccounts)        // 核对账号账务
+		reconcileRouterWithoutRecord.GET("getReconciliationList", riskBirdReconciliationApi.GetReconciliationList) // 获取核对结果列表
+	}
+}
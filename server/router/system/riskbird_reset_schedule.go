@@ -0,0 +1,25 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdResetScheduleRouter struct{}
+
+// InitRiskBirdResetScheduleRouter 初始化 RiskBird账号重置计划 路由信息
+func (s *RiskBirdResetScheduleRouter) InitRiskBirdResetScheduleRouter(Router *gin.RouterGroup) {
+	resetScheduleRouter := Router.Group("riskbird/resetSchedule").Use(middleware.OperationRecord())
+	resetScheduleRouterWithoutRecord := Router.Group("riskbird/resetSchedule")
+	{
+		resetScheduleRouter.POST("createResetSchedule", riskBirdResetScheduleApi.CreateResetSchedule)   // 新建重置计划
+		resetScheduleRouter.DELETE("deleteResetSchedule", riskBirdResetScheduleApi.DeleteResetSchedule) // 删除重置计划
+		resetScheduleRouter.PUT("updateResetSchedule", riskBirdResetScheduleApi.UpdateResetSchedule)    // 更新重置计划
+		resetScheduleRouter.POST("runResetSchedule", riskBirdResetScheduleApi.RunResetSchedule)         // 立即执行重置计划
+	}
+	{
+		resetScheduleRouterWithoutRecord.GET("findResetSchedule", riskBirdResetScheduleApi.FindResetSchedule)       // 根据ID获取重置计划
+		resetScheduleRouterWithoutRecord.GET("getResetScheduleList", riskBirdResetScheduleApi.GetResetScheduleList) // 获取重置计划列表
+		resetScheduleRouterWithoutRecord.GET("getResetRunList", riskBirdResetScheduleApi.GetResetRunList)           // 获取重置执行记录
+	}
+}
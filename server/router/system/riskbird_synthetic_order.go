@@ -0,0 +1,20 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RiskBirdSyntheticOrderRouter struct{}
+
+// InitRiskBirdSyntheticOrderRouter 初始化 RiskBird合成订单 路由信息
+func (s *RiskBirdSyntheticOrderRouter) InitRiskBirdSyntheticOrderRouter(Router *gin.RouterGroup) {
+	syntheticOrderRouter := Router.Group("riskbird/syntheticOrder").Use(middleware.OperationRecord())
+	syntheticOrderRouterWithoutRecord := Router.Group("riskbird/syntheticOrder")
+	{
+		syntheticOrderRouter.POST("cleanupOrders", riskBirdSyntheticOrderApi.CleanupOrders) // 清理合成订单
+	}
+	{
+		syntheticOrderRouterWithoutRecord.GET("getSyntheticOrderList", riskBirdSyntheticOrderApi.GetSyntheticOrderList) // 获取合成订单列表
+	}
+}
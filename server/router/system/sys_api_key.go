@@ -0,0 +1,22 @@
+package system
+
+import (
+	"github.com/flipped-aurora/gin-vue-admin/server/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyRouter struct{}
+
+// InitApiKeyRouter 初始化 API密钥 路由信息
+func (s *ApiKeyRouter) InitApiKeyRouter(Router *gin.RouterGroup) {
+	apiKeyRouter := Router.Group("apiKey").Use(middleware.OperationRecord())
+	apiKeyRouterWithoutRecord := Router.Group("apiKey")
+	{
+		apiKeyRouter.POST("createApiKey", apiKeyApi.CreateApiKey) // 创建API密钥
+		apiKeyRouter.POST("revokeApiKey", apiKeyApi.RevokeApiKey) // 吊销API密钥
+	}
+	{
+		apiKeyRouterWithoutRecord.GET("getApiKeyList", apiKeyApi.GetApiKeyList)           // 获取API密钥列表
+		apiKeyRouterWithoutRecord.GET("getApiKeyUsageList", apiKeyApi.GetApiKeyUsageList) // 获取API密钥调用记录
+	}
+}
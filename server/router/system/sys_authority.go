@@ -11,13 +11,16 @@ func (s *AuthorityRouter) InitAuthorityRouter(Router *gin.RouterGroup) {
 	authorityRouter := Router.Group("authority").Use(middleware.OperationRecord())
 	authorityRouterWithoutRecord := Router.Group("authority")
 	{
-		authorityRouter.POST("createAuthority", authorityApi.CreateAuthority)   // 创建角色
-		authorityRouter.POST("deleteAuthority", authorityApi.DeleteAuthority)   // 删除角色
-		authorityRouter.PUT("updateAuthority", authorityApi.UpdateAuthority)    // 更新角色
-		authorityRouter.POST("copyAuthority", authorityApi.CopyAuthority)       // 拷贝角色
-		authorityRouter.POST("setDataAuthority", authorityApi.SetDataAuthority) // 设置角色资源权限
+		authorityRouter.POST("createAuthority", authorityApi.CreateAuthority)     // 创建角色
+		authorityRouter.POST("deleteAuthority", authorityApi.DeleteAuthority)     // 删除角色
+		authorityRouter.PUT("updateAuthority", authorityApi.UpdateAuthority)      // 更新角色
+		authorityRouter.POST("copyAuthority", authorityApi.CopyAuthority)         // 拷贝角色
+		authorityRouter.POST("setDataAuthority", authorityApi.SetDataAuthority)   // 设置角色资源权限
+		authorityRouter.POST("setRiskBirdScopes", authorityApi.SetRiskBirdScopes) // 设置角色RiskBird环境权限
 	}
 	{
-		authorityRouterWithoutRecord.POST("getAuthorityList", authorityApi.GetAuthorityList) // 获取角色列表
+		authorityRouterWithoutRecord.POST("getAuthorityList", authorityApi.GetAuthorityList)            // 获取角色列表
+		authorityRouterWithoutRecord.GET("getRiskBirdScopes", authorityApi.GetRiskBirdScopes)           // 获取角色RiskBird环境权限
+		authorityRouterWithoutRecord.GET("getRiskBirdAllowedEnvs", authorityApi.GetRiskBirdAllowedEnvs) // 获取当前用户可用的RiskBird环境
 	}
 }
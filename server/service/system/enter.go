@@ -2,11 +2,25 @@ package system
 
 type ServiceGroup struct {
 	JwtService
+	ApiKeyService
 	ApiService
 	MenuService
 	UserService
 	UserBalanceService
 	UserPointService
+	RiskBirdResetScheduleService
+	RiskBirdJobService
+	RiskBirdPointAuditService
+	RiskBirdSyntheticOrderService
+	RiskBirdAccountService
+	RiskBirdFlowService
+	RiskBirdHealthService
+	RiskBirdConfigService
+	RiskBirdImpersonationService
+	RiskBirdReconciliationService
+	RiskBirdGeneratorService
+	RiskBirdScopeService
+	RiskBirdNotifyService
 	CasbinService
 	InitDBService
 	AutoCodeService
@@ -0,0 +1,340 @@
+package system
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RiskBirdAccountService struct{}
+
+var RiskBirdAccountServiceApp = new(RiskBirdAccountService)
+
+// 从数据库读取验证码的最长等待时间
+const riskBirdSmsCodeTimeout = 10 * time.Second
+
+// CreateAccount 登记已有的 RiskBird 账号
+func (s *RiskBirdAccountService) CreateAccount(account *system.RiskBirdAccount) (err error) {
+	if account.Env == "" {
+		account.Env = config.RiskBirdDefaultEnv
+	}
+	if _, err = riskBirdEnv(account.Env); err != nil {
+		return err
+	}
+	if !errors.Is(global.GVA_DB.Where("env = ? AND phone = ?", account.Env, account.Phone).First(&system.RiskBirdAccount{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("该环境下已登记此手机号")
+	}
+	if account.Source == "" {
+		account.Source = system.RiskBirdAccountSourceManual
+	}
+	return global.GVA_DB.Create(account).Error
+}
+
+// DeleteAccount 删除登记的账号
+func (s *RiskBirdAccountService) DeleteAccount(ID uint) (err error) {
+	return global.GVA_DB.Delete(&system.RiskBirdAccount{}, "id = ?", ID).Error
+}
+
+// UpdateAccount 更新登记的账号，密码为空时保持不变；环境不可修改，手机号不能与同环境的其他账号重复
+func (s *RiskBirdAccountService) UpdateAccount(account system.RiskBirdAccount) (err error) {
+	old, err := getRiskBirdAccount(account.ID)
+	if err != nil {
+		return err
+	}
+	if !errors.Is(global.GVA_DB.Where("env = ? AND phone = ? AND id <> ?", old.Env, account.Phone, account.ID).First(&system.RiskBirdAccount{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("该环境下已登记此手机号")
+	}
+	fields := []string{"phone", "user_id", "tags", "remark"}
+	if account.Password != "" {
+		fields = append(fields, "password")
+	}
+	return global.GVA_DB.Model(&system.RiskBirdAccount{}).Where("id = ?", account.ID).Select(fields).Updates(&account).Error
+}
+
+// GetAccount 根据ID获取登记的账号，不返回密码
+func (s *RiskBirdAccountService) GetAccount(ID uint) (account system.RiskBirdAccount, err error) {
+	account, err = getRiskBirdAccount(ID)
+	account.Password = ""
+	return
+}
+
+// GetAccountOverview 登录账号库中的账号，查询其实时余额和积分
+func (s *RiskBirdAccountService) GetAccountOverview(ID uint) (overview systemRes.RiskBirdAccountOverview, err error) {
+	account, err := getRiskBirdAccount(ID)
+	if err != nil {
+		return overview, err
+	}
+	overview, err = s.InspectUser(account.Env, account.Phone, account.Password)
+	account.Password = ""
+	overview.Account = account
+	return overview, err
+}
+
+// InspectUser 登录 RiskBird 用户，查询其实时余额和积分，不要求账号已登记到账号库；密码为空时使用短信验证码登录
+func (s *RiskBirdAccountService) InspectUser(envName, phone, password string) (overview systemRes.RiskBirdAccountOverview, err error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return overview, err
+	}
+	client := newRiskBirdClient(env)
+	session, err := loginRiskBirdAccount(env, nil, client, phone, password)
+	if err != nil {
+		return overview, err
+	}
+	overview.Account = system.RiskBirdAccount{Env: env.Name, Phone: phone, UserID: session.UserID}
+	token := session.Token
+	if overview.Balance, err = client.GetBalance(token); err != nil {
+		return overview, fmt.Errorf("获取用户余额失败: %w", err)
+	}
+	if overview.Points, err = client.GetPointOverview(token); err != nil {
+		return overview, fmt.Errorf("获取用户积分信息失败: %w", err)
+	}
+	return overview, nil
+}
+
+// loginRiskBirdAccount 使用手机号和密码登录，密码为空时使用短信验证码登录，db 为 nil 时读取验证码临时连接数据库
+func loginRiskBirdAccount(env config.RiskBirdEnv, db *sql.DB, client *request.RiskBirdAPIClient, phone, password string) (session riskBirdSession, err error) {
+	if password == "" {
+		return loginRiskBirdUserBySms(env, db, client, phone)
+	}
+	loginResp, err := client.Login(phone, password)
+	if err != nil {
+		return session, fmt.Errorf("RiskBird用户登录失败: %w", err)
+	}
+	token, _ := loginResp["token"].(string)
+	return riskBirdSession{Token: token, UserID: riskBirdUserID(loginResp), Phone: phone}, nil
+}
+
+// GetAccountList 分页获取登记的账号，不返回密码
+func (s *RiskBirdAccountService) GetAccountList(info systemReq.RiskBirdAccountSearch) (list []system.RiskBirdAccount, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdAccount{})
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Phone != "" {
+		db = db.Where("phone LIKE ?", "%"+info.Phone+"%")
+	}
+	if info.Tag != "" {
+		db = db.Where("tags LIKE ?", "%\""+info.Tag+"\"%")
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Omit("password").Find(&list).Error
+	return list, total, err
+}
+
+// ProvisionAccount 注册新的 RiskBird 测试用户，登记到账号库，并按需设置初始余额和积分
+func (s *RiskBirdAccountService) ProvisionAccount(req systemReq.ProvisionRiskBirdAccount) (account system.RiskBirdAccount, err error) {
+	env, err := riskBirdEnv(req.Env)
+	if err != nil {
+		return account, err
+	}
+	if !errors.Is(global.GVA_DB.Where("env = ? AND phone = ?", env.Name, req.Phone).First(&system.RiskBirdAccount{}).Error, gorm.ErrRecordNotFound) {
+		return account, errors.New("该环境下已登记此手机号")
+	}
+	if req.PointAmount < 0 || req.RechargeAmount < 0 || req.GiftAmount < 0 {
+		return account, errors.New("初始余额和积分不能为负数")
+	}
+	if req.PointAmount%riskBirdPointsPerYuan != 0 {
+		return account, errors.New("初始积分必须是5的倍数")
+	}
+	if err = checkRiskBirdSecretKey(req.Password); err != nil {
+		return account, err
+	}
+	// 注册前校验初始余额和积分的环境权限，避免账号注册后才发现无权设置
+	if req.RechargeAmount > 0 || req.GiftAmount > 0 {
+		if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationBalance); err != nil {
+			return account, err
+		}
+	}
+	if req.PointAmount > 0 {
+		if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationPoint); err != nil {
+			return account, err
+		}
+	}
+
+	client := newRiskBirdClient(env)
+
+	// 1. 获取短信验证码
+	code, err := riskBirdSmsCode(env, nil, client, req.Phone)
+	if err != nil {
+		return account, err
+	}
+
+	// 2. 注册用户
+	registerPath := env.SMS.RegisterPath
+	if registerPath == "" {
+		registerPath = "/register"
+	}
+	if _, err = client.Register(registerPath, req.Phone, code, req.Password); err != nil {
+		return account, fmt.Errorf("注册RiskBird用户失败: %w", err)
+	}
+
+	// 3. 登录获取用户ID
+	loginResp, err := client.Login(req.Phone, req.Password)
+	if err != nil {
+		return account, fmt.Errorf("新用户登录失败: %w", err)
+	}
+
+	// 4. 登记到账号库
+	account = system.RiskBirdAccount{
+		Env:      env.Name,
+		Phone:    req.Phone,
+		Password: req.Password,
+		UserID:   riskBirdUserID(loginResp),
+		Tags:     req.Tags,
+		Source:   system.RiskBirdAccountSourceProvisioned,
+		Remark:   req.Remark,
+	}
+	if err = global.GVA_DB.Create(&account).Error; err != nil {
+		return account, err
+	}
+	account.Password = ""
+
+	// 5. 设置初始余额和积分
+	if req.RechargeAmount > 0 || req.GiftAmount > 0 {
+		err = UserBalanceServiceApp.ModifyUserBalance(systemReq.ModifyUserBalance{
+			Env:            env.Name,
+			Phone:          req.Phone,
+			Password:       req.Password,
+			RechargeAmount: req.RechargeAmount,
+			GiftAmount:     req.GiftAmount,
+
+			OperatorID:          req.OperatorID,
+			OperatorAuthorityID: req.OperatorAuthorityID,
+		})
+		if err != nil {
+			return account, fmt.Errorf("账号已创建，初始余额设置失败: %w", err)
+		}
+	}
+	if req.PointAmount > 0 {
+		err = UserPointServiceApp.ModifyUserPoint(systemReq.ModifyUserPoint{
+			Env:         env.Name,
+			Phone:       req.Phone,
+			Password:    req.Password,
+			PointAmount: req.PointAmount,
+
+			OperatorID:          req.OperatorID,
+			OperatorAuthorityID: req.OperatorAuthorityID,
+		})
+		if err != nil {
+			return account, fmt.Errorf("账号已创建，初始积分设置失败: %w", err)
+		}
+	}
+	return account, nil
+}
+
+// checkRiskBirdSecretKey 保存登录密码前确认已配置加密密钥，避免在 RiskBird 注册完成后才发现无法保存
+func checkRiskBirdSecretKey(password string) error {
+	if password != "" && global.GVA_CONFIG.RiskBird.SecretKey == "" {
+		return system.ErrRiskBirdSecretKey
+	}
+	return nil
+}
+
+// EncryptPlainPasswords 加密账号库和造数任务中以明文保存的登录密码，服务启动时执行
+func (s *RiskBirdAccountService) EncryptPlainPasswords() error {
+	for _, table := range []string{system.RiskBirdAccount{}.TableName(), system.RiskBirdGeneratorJob{}.TableName()} {
+		var rows []struct {
+			ID       uint
+			Password string
+		}
+		err := global.GVA_DB.Table(table).Select("id", "password").
+			Where("password <> '' AND password NOT LIKE ?", system.RiskBirdSecretPrefix+"%").Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			encrypted, err := system.EncryptRiskBirdSecret(row.Password)
+			if err != nil {
+				return fmt.Errorf("加密 %s 中的登录密码失败: %w", table, err)
+			}
+			if err = global.GVA_DB.Table(table).Where("id = ?", row.ID).UpdateColumn("password", encrypted).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getRiskBirdAccount 获取包含密码的账号信息，仅供服务内部使用
+func getRiskBirdAccount(ID uint) (account system.RiskBirdAccount, err error) {
+	err = global.GVA_DB.Where("id = ?", ID).First(&account).Error
+	return
+}
+
+// riskBirdSmsCode 发送短信验证码，并通过固定验证码或 RiskBird 数据库取得验证码，db 为 nil 时临时连接数据库
+func riskBirdSmsCode(env config.RiskBirdEnv, db *sql.DB, client *request.RiskBirdAPIClient, phone string) (string, error) {
+	sendPath := env.SMS.SendPath
+	if sendPath == "" {
+		sendPath = "/sendSmsCode"
+	}
+	sentAt := time.Now().Add(-time.Second)
+	if err := client.SendSmsCode(sendPath, phone); err != nil {
+		// 使用固定验证码时发送失败不影响后续流程
+		if env.SMS.BypassCode == "" {
+			return "", fmt.Errorf("发送短信验证码失败: %w", err)
+		}
+		global.GVA_LOG.Warn("发送短信验证码失败，使用固定验证码", zap.Error(err))
+	}
+	if env.SMS.BypassCode != "" {
+		return env.SMS.BypassCode, nil
+	}
+
+	if db == nil {
+		var err error
+		if db, err = newRiskBirdDB(env); err != nil {
+			return "", errors.New("连接数据库失败")
+		}
+		defer db.Close()
+	}
+
+	table := request.SmsCodeTable{
+		Table:        env.SMS.CodeTable,
+		MobileColumn: env.SMS.MobileColumn,
+		CodeColumn:   env.SMS.CodeColumn,
+		TimeColumn:   env.SMS.TimeColumn,
+	}
+	if table.Table == "" {
+		table.Table = "sms_code"
+	}
+	if table.MobileColumn == "" {
+		table.MobileColumn = "mobile"
+	}
+	if table.CodeColumn == "" {
+		table.CodeColumn = "code"
+	}
+	if table.TimeColumn == "" {
+		table.TimeColumn = "create_time"
+	}
+
+	deadline := time.Now().Add(riskBirdSmsCodeTimeout)
+	for {
+		code, err := request.GetLatestSmsCode(db, table, phone, sentAt)
+		if err == nil && code != "" {
+			return code, nil
+		}
+		if time.Now().After(deadline) {
+			global.GVA_LOG.Error("读取短信验证码失败", zap.Error(err))
+			return "", errors.New("读取短信验证码超时")
+		}
+		time.Sleep(time.Second)
+	}
+}
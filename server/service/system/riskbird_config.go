@@ -0,0 +1,280 @@
+package system
+
+import (
+	"errors"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+type RiskBirdConfigService struct{}
+
+var RiskBirdConfigServiceApp = new(RiskBirdConfigService)
+
+// riskBirdConfigSnapshot 一个环境或基线的价格和充值套餐配置
+type riskBirdConfigSnapshot struct {
+	productCfgs      []system.RiskBirdProductCfg
+	rechargeProducts []system.RiskBirdRechargeProduct
+}
+
+// loadRiskBirdConfig 读取环境当前的价格和充值套餐配置，返回环境名称。
+// 操作人角色需要有该环境的价格配置恢复权限
+func loadRiskBirdConfig(envName string, authorityID uint) (name string, snapshot riskBirdConfigSnapshot, err error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return "", snapshot, err
+	}
+	if err = checkRiskBirdScope(authorityID, env.Name, system.RiskBirdOperationConfigRestore); err != nil {
+		return "", snapshot, err
+	}
+	db, err := newRiskBirdDB(env)
+	if err != nil {
+		return "", snapshot, err
+	}
+	defer db.Close()
+	if snapshot.productCfgs, err = request.ListProductCfgs(db); err != nil {
+		return "", snapshot, err
+	}
+	if snapshot.rechargeProducts, err = request.ListRechargeProducts(db); err != nil {
+		return "", snapshot, err
+	}
+	return env.Name, snapshot, nil
+}
+
+// DiffConfig 比对两个环境的配置，或比对一个环境与基线
+func (s *RiskBirdConfigService) DiffConfig(req systemReq.DiffRiskBirdConfig, authorityID uint) (result systemRes.RiskBirdConfigDiffResult, err error) {
+	var left, right riskBirdConfigSnapshot
+	if result.Left, left, err = loadRiskBirdConfig(req.LeftEnv, authorityID); err != nil {
+		return result, err
+	}
+	if req.BaselineID != 0 {
+		baseline, err := s.GetBaseline(req.BaselineID)
+		if err != nil {
+			return result, err
+		}
+		result.Right = "baseline:" + baseline.Name
+		right = riskBirdConfigSnapshot{productCfgs: baseline.ProductCfgs, rechargeProducts: baseline.RechargeProducts}
+	} else {
+		if req.RightEnv == "" {
+			return result, errors.New("请指定右侧环境或基线")
+		}
+		if result.Right, right, err = loadRiskBirdConfig(req.RightEnv, authorityID); err != nil {
+			return result, err
+		}
+	}
+	result.Diffs = diffRiskBirdConfig(left, right)
+	result.Identical = len(result.Diffs) == 0
+	return result, nil
+}
+
+// CreateBaseline 将环境当前的配置保存为基线
+func (s *RiskBirdConfigService) CreateBaseline(req systemReq.CreateRiskBirdConfigBaseline, userID uint, authorityID uint) (baseline system.RiskBirdConfigBaseline, err error) {
+	name, snapshot, err := loadRiskBirdConfig(req.Env, authorityID)
+	if err != nil {
+		return baseline, err
+	}
+	baseline = system.RiskBirdConfigBaseline{
+		Name:             req.Name,
+		Env:              name,
+		ProductCfgs:      snapshot.productCfgs,
+		RechargeProducts: snapshot.rechargeProducts,
+		CreatedBy:        userID,
+	}
+	err = global.GVA_DB.Create(&baseline).Error
+	return baseline, err
+}
+
+// DeleteBaseline 删除基线
+func (s *RiskBirdConfigService) DeleteBaseline(ID uint) (err error) {
+	return global.GVA_DB.Delete(&system.RiskBirdConfigBaseline{}, "id = ?", ID).Error
+}
+
+// GetBaseline 根据ID获取基线
+func (s *RiskBirdConfigService) GetBaseline(ID uint) (baseline system.RiskBirdConfigBaseline, err error) {
+	err = global.GVA_DB.Where("id = ?", ID).First(&baseline).Error
+	return
+}
+
+// GetBaselineList 分页获取基线，列表不返回快照内容
+func (s *RiskBirdConfigService) GetBaselineList(info systemReq.RiskBirdConfigBaselineSearch) (list []system.RiskBirdConfigBaseline, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdConfigBaseline{})
+	if info.Name != "" {
+		db = db.Where("name LIKE ?", "%"+info.Name+"%")
+	}
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Omit("product_cfgs", "recharge_products").Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+// RestoreBaseline 将环境中与基线取值不同的配置恢复为基线值并记录审计。
+// 只更新两边都存在的记录，环境或基线中多出的记录只在差异中列出；
+// 恢复会覆盖正在执行的流程临时修改的价格，应在没有流程执行时操作
+func (s *RiskBirdConfigService) RestoreBaseline(req systemReq.RestoreRiskBirdConfigBaseline, userID uint) (restore system.RiskBirdConfigRestore, err error) {
+	baseline, err := s.GetBaseline(req.BaselineID)
+	if err != nil {
+		return restore, err
+	}
+	env, err := riskBirdEnv(req.Env)
+	if err != nil {
+		return restore, err
+	}
+	if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationConfigRestore); err != nil {
+		return restore, err
+	}
+	db, err := newRiskBirdDB(env)
+	if err != nil {
+		return restore, err
+	}
+	defer db.Close()
+	// 等待同一环境中修改报告价格的流程恢复价格后再比对和恢复，避免把流程临时修改的价格当作差异或被流程覆盖
+	lock := riskBirdPricingLock(env.Name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	var current riskBirdConfigSnapshot
+	if current.productCfgs, err = request.ListProductCfgs(db); err != nil {
+		return restore, err
+	}
+	if current.rechargeProducts, err = request.ListRechargeProducts(db); err != nil {
+		return restore, err
+	}
+	restore = system.RiskBirdConfigRestore{
+		BaselineID: baseline.ID,
+		Env:        env.Name,
+		Changes:    diffRiskBirdConfig(current, riskBirdConfigSnapshot{productCfgs: baseline.ProductCfgs, rechargeProducts: baseline.RechargeProducts}),
+		Status:     system.RiskBirdConfigRestoreSuccess,
+		UserID:     userID,
+	}
+
+	changedCfgs, changedProducts := map[int64]bool{}, map[int64]bool{}
+	for _, diff := range restore.Changes {
+		if diff.Kind != system.RiskBirdConfigChanged {
+			continue
+		}
+		if diff.Table == "p_product_cfg" {
+			changedCfgs[diff.ID] = true
+		} else {
+			changedProducts[diff.ID] = true
+		}
+	}
+	var productCfgs []system.RiskBirdProductCfg
+	for _, item := range baseline.ProductCfgs {
+		if changedCfgs[item.ID] {
+			productCfgs = append(productCfgs, item)
+		}
+	}
+	var rechargeProducts []system.RiskBirdRechargeProduct
+	for _, item := range baseline.RechargeProducts {
+		if changedProducts[item.ID] {
+			rechargeProducts = append(rechargeProducts, item)
+		}
+	}
+	if err = request.RestoreConfigRows(db, productCfgs, rechargeProducts); err != nil {
+		restore.Status = system.RiskBirdConfigRestoreFailed
+		restore.Error = err.Error()
+	}
+	if dbErr := global.GVA_DB.Create(&restore).Error; dbErr != nil {
+		global.GVA_LOG.Error("保存配置恢复记录失败", zap.Error(dbErr))
+	}
+	return restore, err
+}
+
+// GetRestoreList 分页获取配置恢复记录
+func (s *RiskBirdConfigService) GetRestoreList(info systemReq.RiskBirdConfigRestoreSearch) (list []system.RiskBirdConfigRestore, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdConfigRestore{})
+	if info.BaselineID != 0 {
+		db = db.Where("baseline_id = ?", info.BaselineID)
+	}
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+// diffRiskBirdConfig 按表和ID比对两份配置
+func diffRiskBirdConfig(left, right riskBirdConfigSnapshot) (diffs []system.RiskBirdConfigDiff) {
+	rightCfgs := make(map[int64]system.RiskBirdProductCfg, len(right.productCfgs))
+	for _, item := range right.productCfgs {
+		rightCfgs[item.ID] = item
+	}
+	for _, l := range left.productCfgs {
+		r, ok := rightCfgs[l.ID]
+		delete(rightCfgs, l.ID)
+		switch {
+		case !ok:
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_product_cfg", ID: l.ID, Kind: system.RiskBirdConfigOnlyLeft, Left: l.CfgValue})
+		case !equalCfgValue(l.CfgValue, r.CfgValue):
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_product_cfg", ID: l.ID, Field: "cfg_value", Kind: system.RiskBirdConfigChanged, Left: l.CfgValue, Right: r.CfgValue})
+		}
+	}
+	for _, r := range right.productCfgs {
+		if _, ok := rightCfgs[r.ID]; ok {
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_product_cfg", ID: r.ID, Kind: system.RiskBirdConfigOnlyRight, Right: r.CfgValue})
+		}
+	}
+
+	rightProducts := make(map[int64]system.RiskBirdRechargeProduct, len(right.rechargeProducts))
+	for _, item := range right.rechargeProducts {
+		rightProducts[item.ID] = item
+	}
+	for _, l := range left.rechargeProducts {
+		r, ok := rightProducts[l.ID]
+		delete(rightProducts, l.ID)
+		if !ok {
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_recharge_product", ID: l.ID, Kind: system.RiskBirdConfigOnlyLeft, Left: formatRechargeProduct(l)})
+			continue
+		}
+		if l.Amount != r.Amount {
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_recharge_product", ID: l.ID, Field: "amount", Kind: system.RiskBirdConfigChanged, Left: l.Amount.String(), Right: r.Amount.String()})
+		}
+		if l.GiftAmount != r.GiftAmount {
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_recharge_product", ID: l.ID, Field: "gift_amount", Kind: system.RiskBirdConfigChanged, Left: l.GiftAmount.String(), Right: r.GiftAmount.String()})
+		}
+	}
+	for _, r := range right.rechargeProducts {
+		if _, ok := rightProducts[r.ID]; ok {
+			diffs = append(diffs, system.RiskBirdConfigDiff{Table: "p_recharge_product", ID: r.ID, Kind: system.RiskBirdConfigOnlyRight, Right: formatRechargeProduct(r)})
+		}
+	}
+	return diffs
+}
+
+// equalCfgValue 配置值都是金额时按金额比较，避免 5 和 5.00 被判为不同
+func equalCfgValue(a, b string) bool {
+	if a == b {
+		return true
+	}
+	x, errX := common.ParseMoney(a)
+	y, errY := common.ParseMoney(b)
+	return errX == nil && errY == nil && x == y
+}
+
+func formatRechargeProduct(p system.RiskBirdRechargeProduct) string {
+	return "amount=" + p.Amount.String() + ", gift_amount=" + p.GiftAmount.String()
+}
@@ -0,0 +1,33 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+func TestDiffRiskBirdConfig(t *testing.T) {
+	left := riskBirdConfigSnapshot{
+		productCfgs: []system.RiskBirdProductCfg{{ID: 12, CfgValue: "5"}, {ID: 13, CfgValue: "on"}, {ID: 14, CfgValue: "1"}},
+		rechargeProducts: []system.RiskBirdRechargeProduct{
+			{ID: 5, Amount: 10000, GiftAmount: 0},
+		},
+	}
+	right := riskBirdConfigSnapshot{
+		productCfgs: []system.RiskBirdProductCfg{{ID: 12, CfgValue: "5.00"}, {ID: 13, CfgValue: "off"}},
+		rechargeProducts: []system.RiskBirdRechargeProduct{
+			{ID: 5, Amount: 10000, GiftAmount: 2000},
+			{ID: 6, Amount: 50000, GiftAmount: 0},
+		},
+	}
+	want := []system.RiskBirdConfigDiff{
+		{Table: "p_product_cfg", ID: 13, Field: "cfg_value", Kind: system.RiskBirdConfigChanged, Left: "on", Right: "off"},
+		{Table: "p_product_cfg", ID: 14, Kind: system.RiskBirdConfigOnlyLeft, Left: "1"},
+		{Table: "p_recharge_product", ID: 5, Field: "gift_amount", Kind: system.RiskBirdConfigChanged, Left: "0.00", Right: "20.00"},
+		{Table: "p_recharge_product", ID: 6, Kind: system.RiskBirdConfigOnlyRight, Right: "amount=500.00, gift_amount=0.00"},
+	}
+	if got := diffRiskBirdConfig(left, right); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffRiskBirdConfig() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package system
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+)
+
+// 未配置熔断策略时的默认值
+const (
+	riskBirdBreakerThreshold   = 5
+	riskBirdBreakerOpenSeconds = 30
+)
+
+// riskBirdEnv 根据名称获取 RiskBird 环境配置，名称为空时使用默认环境
+func riskBirdEnv(name string) (config.RiskBirdEnv, error) {
+	env, ok := global.GVA_CONFIG.RiskBird.Env(name)
+	if !ok {
+		return env, fmt.Errorf("RiskBird环境 %s 不存在", name)
+	}
+	return env, nil
+}
+
+// newRiskBirdDB 连接指定环境的 RiskBird 数据库
+func newRiskBirdDB(env config.RiskBirdEnv) (*sql.DB, error) {
+	return request.NewRiskBirdDB(request.RiskBirdDBConfig{
+		Host:     env.DB.Host,
+		Port:     env.DB.Port,
+		User:     env.DB.User,
+		Password: env.DB.Password,
+		Database: env.DB.Database,
+	})
+}
+
+// newRiskBirdClient 创建指定环境的 RiskBird API 客户端，同一环境的客户端共用一个熔断器
+func newRiskBirdClient(env config.RiskBirdEnv) *request.RiskBirdAPIClient {
+	client := request.NewRiskBirdAPIClient(env.API.BaseUrl)
+	client.Env = env.Name
+	if env.API.AdminBaseUrl != "" {
+		client.AdminBaseURL = env.API.AdminBaseUrl
+	}
+	resilience := global.GVA_CONFIG.RiskBird.Resilience
+	for endpoint, policy := range resilience.Retries {
+		client.Retry[endpoint] = request.RetryPolicy{
+			MaxAttempts: policy.MaxAttempts,
+			Backoff:     time.Duration(policy.Backoff) * time.Millisecond,
+			MaxBackoff:  time.Duration(policy.MaxBackoff) * time.Millisecond,
+		}
+	}
+	client.Breaker = riskBirdBreaker(env.Name)
+	return client
+}
+
+// riskBirdBreaker 获取环境的熔断器，未配置的参数使用默认值
+func riskBirdBreaker(envName string) *request.CircuitBreaker {
+	policy := global.GVA_CONFIG.RiskBird.Resilience.Breaker
+	if policy.FailureThreshold <= 0 {
+		policy.FailureThreshold = riskBirdBreakerThreshold
+	}
+	if policy.OpenSeconds <= 0 {
+		policy.OpenSeconds = riskBirdBreakerOpenSeconds
+	}
+	return request.GetCircuitBreaker(envName, policy.FailureThreshold, time.Duration(policy.OpenSeconds)*time.Second)
+}
+
+// checkRiskBirdAdminAccount 确认环境配置了后台管理员账号
+func checkRiskBirdAdminAccount(env config.RiskBirdEnv) error {
+	if env.API.AdminUsername == "" || env.API.AdminPassword == "" {
+		return fmt.Errorf("RiskBird环境 %s 未配置后台管理员账号 admin-username 和 admin-password", env.Name)
+	}
+	return nil
+}
+
+// riskBirdAdminLogin 使用环境配置的后台管理员账号登录
+func riskBirdAdminLogin(env config.RiskBirdEnv, client *request.RiskBirdAPIClient) (string, error) {
+	if err := checkRiskBirdAdminAccount(env); err != nil {
+		return "", err
+	}
+	return client.AdminLogin(env.API.AdminUsername, env.API.AdminPassword)
+}
@@ -0,0 +1,258 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"go.uber.org/zap"
+)
+
+type RiskBirdFlowService struct{}
+
+var RiskBirdFlowServiceApp = new(RiskBirdFlowService)
+
+// StartModifyUserBalance 异步执行修改余额流程，返回流程执行记录，步骤进度通过 SubscribeRun 获取
+func (s *RiskBirdFlowService) StartModifyUserBalance(req systemReq.ModifyUserBalance, userID uint) (run system.RiskBirdFlowRun, err error) {
+	if req.DryRun {
+		return run, errors.New("异步流程不支持预演，请使用修改用户余额接口预演")
+	}
+	if err = checkModifyUserBalance(req); err != nil {
+		return run, err
+	}
+	resume, err := riskBirdResumeCheckpoint(system.RiskBirdFlowBalance, req.Env, req.ResumeRunID)
+	if err != nil {
+		return run, err
+	}
+	return s.start(system.RiskBirdFlowBalance, req.Env, req.Phone, userID, req.ResumeRunID, func(progress *riskBirdProgress) (riskBirdPipelineResult, error) {
+		return UserBalanceServiceApp.modifyUserBalance(req, progress, riskBirdPipelineOptions{Resume: resume})
+	})
+}
+
+// StartModifyUserPoint 异步执行修改积分流程，返回流程执行记录，步骤进度通过 SubscribeRun 获取
+func (s *RiskBirdFlowService) StartModifyUserPoint(req systemReq.ModifyUserPoint, userID uint) (run system.RiskBirdFlowRun, err error) {
+	if req.DryRun {
+		return run, errors.New("异步流程不支持预演，请使用修改用户积分接口预演")
+	}
+	if err = checkModifyUserPoint(&req); err != nil {
+		return run, err
+	}
+	resume, err := riskBirdResumeCheckpoint(system.RiskBirdFlowPoint, req.Env, req.ResumeRunID)
+	if err != nil {
+		return run, err
+	}
+	return s.start(system.RiskBirdFlowPoint, req.Env, req.Phone, userID, req.ResumeRunID, func(progress *riskBirdProgress) (riskBirdPipelineResult, error) {
+		return UserPointServiceApp.modifyUserPoint(req, progress, riskBirdPipelineOptions{Resume: resume})
+	})
+}
+
+// StartModifyAccountBalance 使用账号库中的账号异步修改余额
+func (s *RiskBirdFlowService) StartModifyAccountBalance(req systemReq.ModifyRiskBirdAccountBalance, userID uint) (run system.RiskBirdFlowRun, err error) {
+	account, err := getRiskBirdAccount(req.AccountID)
+	if err != nil {
+		return run, err
+	}
+	return s.StartModifyUserBalance(systemReq.ModifyUserBalance{
+		Env:            account.Env,
+		Phone:          account.Phone,
+		Password:       account.Password,
+		SmsLogin:       account.Password == "",
+		RechargeAmount: req.RechargeAmount,
+		GiftAmount:     req.GiftAmount,
+
+		OperatorID:          userID,
+		OperatorAuthorityID: req.OperatorAuthorityID,
+	}, userID)
+}
+
+// StartModifyAccountPoint 使用账号库中的账号异步修改积分
+func (s *RiskBirdFlowService) StartModifyAccountPoint(req systemReq.ModifyRiskBirdAccountPoint, userID uint) (run system.RiskBirdFlowRun, err error) {
+	account, err := getRiskBirdAccount(req.AccountID)
+	if err != nil {
+		return run, err
+	}
+	return s.StartModifyUserPoint(systemReq.ModifyUserPoint{
+		Env:         account.Env,
+		Phone:       account.Phone,
+		Password:    account.Password,
+		SmsLogin:    account.Password == "",
+		PointAmount: req.PointAmount,
+		Strategy:    req.Strategy,
+
+		OperatorID:          userID,
+		OperatorAuthorityID: req.OperatorAuthorityID,
+	}, userID)
+}
+
+func (s *RiskBirdFlowService) start(flow, envName, phone string, userID, resumedFrom uint, fn func(progress *riskBirdProgress) (riskBirdPipelineResult, error)) (run system.RiskBirdFlowRun, err error) {
+	if run, err = s.create(flow, envName, phone, userID, resumedFrom); err != nil {
+		return run, err
+	}
+	go func() {
+		finished, _ := s.execute(run, fn)
+		notifyRiskBirdFlowRun(finished)
+	}()
+	return run, nil
+}
+
+// runSync 同步执行流程，同样保存流程执行记录，返回流程的执行错误
+func (s *RiskBirdFlowService) runSync(flow, envName, phone string, userID, resumedFrom uint, fn func(progress *riskBirdProgress) (riskBirdPipelineResult, error)) error {
+	run, err := s.create(flow, envName, phone, userID, resumedFrom)
+	if err != nil {
+		return err
+	}
+	_, err = s.execute(run, fn)
+	return err
+}
+
+// create 创建执行中的流程执行记录并开始收集步骤事件
+func (s *RiskBirdFlowService) create(flow, envName, phone string, userID, resumedFrom uint) (run system.RiskBirdFlowRun, err error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return run, err
+	}
+	run = system.RiskBirdFlowRun{
+		Flow:      flow,
+		Env:       env.Name,
+		Phone:     phone,
+		Status:    system.RiskBirdFlowRunRunning,
+		StartedAt: time.Now(),
+		UserID:    userID,
+
+		ResumedFrom: resumedFrom,
+	}
+	if err = global.GVA_DB.Create(&run).Error; err != nil {
+		return run, err
+	}
+	riskBirdFlowHub.open(run.ID)
+	return run, nil
+}
+
+// execute 执行流程并保存执行结果，失败时同时保存断点
+func (s *RiskBirdFlowService) execute(run system.RiskBirdFlowRun, fn func(progress *riskBirdProgress) (riskBirdPipelineResult, error)) (finished system.RiskBirdFlowRun, err error) {
+	progress := newRiskBirdProgress(run.Flow, run.Env, run.ID)
+	var result riskBirdPipelineResult
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("流程异常: %v", r)
+		}
+		progress.Finish(err)
+		run.Status = system.RiskBirdFlowRunSuccess
+		if err != nil {
+			run.Status = system.RiskBirdFlowRunFailed
+			run.Error = err.Error()
+			run.Checkpoint = result.Checkpoint
+		}
+		finishedAt := time.Now()
+		run.FinishedAt = &finishedAt
+		progress.emit("", system.RiskBirdStepFinished, run.Error, map[string]interface{}{"status": run.Status}, finishedAt.Sub(run.StartedAt))
+		run.Events = riskBirdFlowHub.events(run.ID)
+		if dbErr := global.GVA_DB.Model(&system.RiskBirdFlowRun{}).Where("id = ?", run.ID).
+			Select("status", "error", "finished_at", "events", "checkpoint").Updates(&run).Error; dbErr != nil {
+			global.GVA_LOG.Error("保存流程执行记录失败", zap.Uint("runId", run.ID), zap.Error(dbErr))
+		}
+		riskBirdFlowHub.close(run.ID)
+		finished = run
+	}()
+	result, err = fn(progress)
+	return run, err
+}
+
+// riskBirdResumeCheckpoint 获取续跑的断点，runID 为0时不续跑。只能续跑同一流程和环境中失败且保存了断点的执行记录
+func riskBirdResumeCheckpoint(flow, envName string, runID uint) (*system.RiskBirdFlowCheckpoint, error) {
+	if runID == 0 {
+		return nil, nil
+	}
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	// 调用方已按本次请求的环境校验过权限，这里只需确认记录属于同一环境
+	run, err := RiskBirdFlowServiceApp.GetFlowRun(runID, system.RiskBirdInternalAuthorityID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Flow != flow || run.Env != env.Name {
+		return nil, errors.New("续跑的流程执行记录与本次请求的流程或环境不一致")
+	}
+	if run.Status != system.RiskBirdFlowRunFailed || run.Checkpoint == nil {
+		return nil, errors.New("只能续跑失败且保存了断点的流程执行记录")
+	}
+	return run.Checkpoint, nil
+}
+
+// SubscribeRun 订阅流程步骤事件，返回已有事件和后续事件通道，流程已结束时通道为 nil
+func (s *RiskBirdFlowService) SubscribeRun(ID uint, authorityID uint) (history []system.RiskBirdStepEvent, events <-chan system.RiskBirdStepEvent, cancel func(), err error) {
+	// 执行记录在流程开始时已创建，先按记录的环境校验权限再订阅
+	run, err := s.GetFlowRun(ID, authorityID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	history, ch, ok := riskBirdFlowHub.subscribe(ID)
+	if ok {
+		return history, ch, func() { riskBirdFlowHub.unsubscribe(ID, ch) }, nil
+	}
+	// 读取记录后流程可能刚好结束，重新读取最终状态和事件
+	if err = global.GVA_DB.Where("id = ?", ID).First(&run).Error; err != nil {
+		return nil, nil, nil, err
+	}
+	if run.Status == system.RiskBirdFlowRunRunning {
+		// 服务重启等原因导致流程中断，没有可订阅的进度
+		return nil, nil, nil, errors.New("流程已中断")
+	}
+	return run.Events, nil, func() {}, nil
+}
+
+// GetFlowRun 根据ID获取流程执行记录，操作人角色需要有该流程在记录所在环境的操作权限
+func (s *RiskBirdFlowService) GetFlowRun(ID uint, authorityID uint) (run system.RiskBirdFlowRun, err error) {
+	if err = global.GVA_DB.Where("id = ?", ID).First(&run).Error; err != nil {
+		return run, err
+	}
+	if err = checkRiskBirdScope(authorityID, run.Env, riskBirdFlowOperation(run.Flow)); err != nil {
+		return system.RiskBirdFlowRun{}, err
+	}
+	return run, nil
+}
+
+// riskBirdFlowOperation 流程对应的环境权限操作类型
+func riskBirdFlowOperation(flow string) string {
+	if flow == system.RiskBirdFlowPoint {
+		return system.RiskBirdOperationPoint
+	}
+	return system.RiskBirdOperationBalance
+}
+
+// GetFlowRunList 分页获取流程执行记录，只返回操作人角色可以查看的环境
+func (s *RiskBirdFlowService) GetFlowRunList(info systemReq.RiskBirdFlowRunSearch, authorityID uint) (list []system.RiskBirdFlowRun, total int64, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdFlowRun{}).Omit("events").Where("env IN ?", envs)
+	if info.Flow != "" {
+		db = db.Where("flow = ?", info.Flow)
+	}
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Phone != "" {
+		db = db.Where("phone = ?", info.Phone)
+	}
+	if info.Status != "" {
+		db = db.Where("status = ?", info.Status)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
@@ -0,0 +1,278 @@
+package system
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+// 订阅者事件通道缓冲大小，订阅者读取过慢时断开，客户端可携带 Last-Event-ID 重连补齐
+const riskBirdFlowSubscriberBuffer = 64
+
+// riskBirdFlowHub 正在执行的流程的步骤事件，流程结束后事件保存在 riskbird_flow_runs 中
+var riskBirdFlowHub = &flowHub{runs: make(map[uint]*flowHubRun)}
+
+type flowHubRun struct {
+	events []system.RiskBirdStepEvent
+	subs   map[chan system.RiskBirdStepEvent]struct{}
+}
+
+type flowHub struct {
+	mu   sync.Mutex
+	runs map[uint]*flowHubRun
+}
+
+func (h *flowHub) open(runID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs[runID] = &flowHubRun{subs: make(map[chan system.RiskBirdStepEvent]struct{})}
+}
+
+// publish 记录事件并推送给全部订阅者
+func (h *flowHub) publish(event system.RiskBirdStepEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[event.RunID]
+	if !ok {
+		return
+	}
+	event.Seq = len(run.events) + 1
+	run.events = append(run.events, event)
+	for ch := range run.subs {
+		select {
+		case ch <- event:
+		default:
+			delete(run.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// events 返回流程当前全部事件
+func (h *flowHub) events(runID uint) []system.RiskBirdStepEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if run, ok := h.runs[runID]; ok {
+		return append([]system.RiskBirdStepEvent{}, run.events...)
+	}
+	return nil
+}
+
+// close 结束流程并关闭全部订阅
+func (h *flowHub) close(runID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[runID]
+	if !ok {
+		return
+	}
+	for ch := range run.subs {
+		close(ch)
+	}
+	delete(h.runs, runID)
+}
+
+// subscribe 订阅正在执行的流程，返回已有事件和后续事件通道，流程不在执行中时 ok 为 false
+func (h *flowHub) subscribe(runID uint) (history []system.RiskBirdStepEvent, ch chan system.RiskBirdStepEvent, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[runID]
+	if !ok {
+		return nil, nil, false
+	}
+	ch = make(chan system.RiskBirdStepEvent, riskBirdFlowSubscriberBuffer)
+	run.subs[ch] = struct{}{}
+	return append([]system.RiskBirdStepEvent{}, run.events...), ch, true
+}
+
+func (h *flowHub) unsubscribe(runID uint, ch chan system.RiskBirdStepEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[runID]
+	if !ok {
+		return
+	}
+	if _, ok = run.subs[ch]; ok {
+		delete(run.subs, ch)
+		close(ch)
+	}
+}
+
+// riskBirdProgress 流程步骤进度和指标，runID 为0时只记录指标不推送事件，为 nil 时什么都不记录
+type riskBirdProgress struct {
+	runID uint
+	flow  string
+	env   string
+	start time.Time
+}
+
+// newRiskBirdProgress 创建流程进度，同步调用流程时 runID 传0
+func newRiskBirdProgress(flow, envName string, runID uint) *riskBirdProgress {
+	if envName == "" {
+		envName = config.RiskBirdDefaultEnv
+	}
+	return &riskBirdProgress{runID: runID, flow: flow, env: envName, start: time.Now()}
+}
+
+// Finish 记录流程执行结果
+func (p *riskBirdProgress) Finish(err error) {
+	if p == nil {
+		return
+	}
+	observeRiskBirdFlowRun(p.flow, p.env, time.Since(p.start), err)
+}
+
+// riskBirdStep 正在执行的步骤
+type riskBirdStep struct {
+	progress    *riskBirdProgress
+	name        string
+	start       time.Time
+	compensated bool
+}
+
+func (p *riskBirdProgress) emit(step, event, message string, payload interface{}, elapsed time.Duration) {
+	if p.runID == 0 {
+		return
+	}
+	riskBirdFlowHub.publish(system.RiskBirdStepEvent{
+		RunID:   p.runID,
+		Step:    step,
+		Event:   event,
+		Message: message,
+		Payload: sanitizeRiskBirdPayload(payload),
+		Time:    time.Now(),
+		Elapsed: elapsed.Milliseconds(),
+	})
+}
+
+// Step 开始一个步骤，payload 为步骤的请求参数
+func (p *riskBirdProgress) Step(name string, payload interface{}) *riskBirdStep {
+	if p == nil {
+		return nil
+	}
+	p.emit(name, system.RiskBirdStepStarted, "", payload, 0)
+	return &riskBirdStep{progress: p, name: name, start: time.Now()}
+}
+
+// Compensate 开始一个补偿步骤
+func (p *riskBirdProgress) Compensate(name string) *riskBirdStep {
+	if p == nil {
+		return nil
+	}
+	p.emit(name, system.RiskBirdStepCompensating, "", nil, 0)
+	return &riskBirdStep{progress: p, name: name, start: time.Now(), compensated: true}
+}
+
+// Skip 记录续跑时跳过的步骤
+func (p *riskBirdProgress) Skip(name, message string) {
+	if p == nil {
+		return
+	}
+	p.emit(name, system.RiskBirdStepSkipped, message, nil, 0)
+}
+
+// Plan 记录预演时未执行的步骤，payload 为计划参数，result 为预计结果
+func (p *riskBirdProgress) Plan(name string, payload, result interface{}) {
+	if p == nil {
+		return
+	}
+	p.emit(name, system.RiskBirdStepPlanned, "", map[string]interface{}{"input": payload, "output": result}, 0)
+}
+
+// Done 结束步骤，err 不为空时记录为失败
+func (s *riskBirdStep) Done(result interface{}, err error) {
+	if s == nil {
+		return
+	}
+	event := system.RiskBirdStepSucceeded
+	if s.compensated {
+		event = system.RiskBirdStepCompensated
+	}
+	message := ""
+	if err != nil {
+		event = system.RiskBirdStepFailed
+		message = err.Error()
+	}
+	elapsed := time.Since(s.start)
+	observeRiskBirdFlowStep(s.progress.flow, s.progress.env, s.name, event, elapsed)
+	s.progress.emit(s.name, event, message, result, elapsed)
+}
+
+// 事件中需要隐藏的字段，riskBirdSensitiveKeys 按包含匹配，riskBirdSensitiveCodeKeys 按全名匹配
+var (
+	riskBirdSensitiveKeys     = []string{"password", "token", "authorization", "secret"}
+	riskBirdSensitiveCodeKeys = []string{"code", "smscode", "verifycode", "captcha"}
+)
+
+// sanitizeRiskBirdPayload 隐藏密码、令牌等敏感字段并对手机号打码
+func sanitizeRiskBirdPayload(payload interface{}) interface{} {
+	switch v := payload.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			lower := strings.ToLower(key)
+			switch {
+			case isRiskBirdSensitiveKey(lower):
+				result[key] = "******"
+			case strings.Contains(lower, "phone") || strings.Contains(lower, "mobile"):
+				if s, ok := value.(string); ok {
+					result[key] = maskRiskBirdPhone(s)
+				} else {
+					result[key] = sanitizeRiskBirdPayload(value)
+				}
+			default:
+				result[key] = sanitizeRiskBirdPayload(value)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i := range v {
+			result[i] = sanitizeRiskBirdPayload(v[i])
+		}
+		return result
+	case string, bool, int, int64, float64, json.Number, common.Money:
+		return v
+	default:
+		// 结构体等其他类型先转换为通用结构再处理
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		var generic interface{}
+		if err = json.Unmarshal(data, &generic); err != nil {
+			return nil
+		}
+		// 结构体切片等转换后同样需要逐项处理
+		return sanitizeRiskBirdPayload(generic)
+	}
+}
+
+func isRiskBirdSensitiveKey(key string) bool {
+	for _, sensitive := range riskBirdSensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	for _, sensitive := range riskBirdSensitiveCodeKeys {
+		if key == sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// maskRiskBirdPhone 手机号只保留前3位和后4位
+func maskRiskBirdPhone(phone string) string {
+	if len(phone) < 8 {
+		return "****"
+	}
+	return phone[:3] + strings.Repeat("*", len(phone)-7) + phone[len(phone)-4:]
+}
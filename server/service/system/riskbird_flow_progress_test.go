@@ -0,0 +1,121 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+func TestSanitizeRiskBirdPayload(t *testing.T) {
+	type login struct {
+		Phone    string `json:"phone"`
+		Password string `json:"password"`
+		Remark   string `json:"remark"`
+	}
+	for _, tt := range []struct {
+		name    string
+		payload interface{}
+		want    interface{}
+	}{
+		{"nil", nil, nil},
+		{
+			"嵌套的密码、令牌和验证码",
+			map[string]interface{}{
+				"request": map[string]interface{}{
+					"Password":     "secret",
+					"accessToken":  "abc",
+					"Code":         "123456",
+					"smsCode":      "654321",
+					"codeType":     "login",
+					"userPhone":    "13812345678",
+					"amount":       int64(100),
+					"headers":      []interface{}{map[string]interface{}{"Authorization": "Bearer x"}},
+					"mobileNumber": "1381234",
+				},
+			},
+			map[string]interface{}{
+				"request": map[string]interface{}{
+					"Password":     "******",
+					"accessToken":  "******",
+					"Code":         "******",
+					"smsCode":      "******",
+					"codeType":     "login",
+					"userPhone":    "138****5678",
+					"amount":       int64(100),
+					"headers":      []interface{}{map[string]interface{}{"Authorization": "******"}},
+					"mobileNumber": "****",
+				},
+			},
+		},
+		{
+			"结构体",
+			login{Phone: "13812345678", Password: "secret", Remark: "ok"},
+			map[string]interface{}{"phone": "138****5678", "password": "******", "remark": "ok"},
+		},
+		{
+			"结构体切片",
+			[]login{{Phone: "13812345678", Password: "secret"}},
+			[]interface{}{map[string]interface{}{"phone": "138****5678", "password": "******", "remark": ""}},
+		},
+		{
+			"手机号字段下的嵌套结构",
+			map[string]interface{}{"phoneInfo": map[string]interface{}{"token": "abc"}},
+			map[string]interface{}{"phoneInfo": map[string]interface{}{"token": "******"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeRiskBirdPayload(tt.payload); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sanitizeRiskBirdPayload() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowHubSlowSubscriber(t *testing.T) {
+	hub := &flowHub{runs: make(map[uint]*flowHubRun)}
+	hub.open(1)
+	_, slow, ok := hub.subscribe(1)
+	if !ok {
+		t.Fatal("subscribe() want ok for an open run")
+	}
+	// 订阅者不读取事件，缓冲区写满后被断开，不阻塞发布
+	total := riskBirdFlowSubscriberBuffer + 10
+	for i := 0; i < total; i++ {
+		hub.publish(system.RiskBirdStepEvent{RunID: 1, Step: "step"})
+	}
+	lastSeq := 0
+	for event := range slow {
+		lastSeq = event.Seq
+	}
+	if lastSeq != riskBirdFlowSubscriberBuffer {
+		t.Fatalf("slow subscriber received up to seq %d, want %d", lastSeq, riskBirdFlowSubscriberBuffer)
+	}
+
+	// 客户端携带 Last-Event-ID 重连，从历史事件中补齐断开后的事件
+	history, ch, ok := hub.subscribe(1)
+	if !ok {
+		t.Fatal("resubscribe() want ok for an open run")
+	}
+	var missed []int
+	for _, event := range history {
+		if event.Seq > lastSeq {
+			missed = append(missed, event.Seq)
+		}
+	}
+	if len(missed) != total-lastSeq || missed[0] != lastSeq+1 || missed[len(missed)-1] != total {
+		t.Fatalf("missed events = %v, want seq %d..%d", missed, lastSeq+1, total)
+	}
+	hub.publish(system.RiskBirdStepEvent{RunID: 1, Step: "next"})
+	if event := <-ch; event.Seq != total+1 {
+		t.Errorf("live event seq = %d, want %d", event.Seq, total+1)
+	}
+
+	hub.close(1)
+	if _, open := <-ch; open {
+		t.Error("close() should close subscriber channels")
+	}
+	if _, _, ok = hub.subscribe(1); ok {
+		t.Error("subscribe() want not ok after close")
+	}
+}
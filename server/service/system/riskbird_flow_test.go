@@ -0,0 +1,44 @@
+package system
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRiskBirdFlowRunSyncRecordsRun(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&system.RiskBirdFlowRun{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB := global.GVA_DB
+	global.GVA_DB = db
+	defer func() { global.GVA_DB = oldDB }()
+
+	flowErr := errors.New("登录失败")
+	err = RiskBirdFlowServiceApp.runSync(system.RiskBirdFlowPoint, "", "13800000000", 7, 0, func(progress *riskBirdProgress) (riskBirdPipelineResult, error) {
+		progress.Step("登录", nil).Done(nil, flowErr)
+		return riskBirdPipelineResult{}, flowErr
+	})
+	if !errors.Is(err, flowErr) {
+		t.Fatalf("runSync() err = %v, want %v", err, flowErr)
+	}
+	var run system.RiskBirdFlowRun
+	if err = db.First(&run).Error; err != nil {
+		t.Fatal(err)
+	}
+	if run.Env != config.RiskBirdDefaultEnv || run.UserID != 7 || run.Status != system.RiskBirdFlowRunFailed || run.Error != flowErr.Error() {
+		t.Errorf("run = %+v", run)
+	}
+	if run.FinishedAt == nil || len(run.Events) == 0 {
+		t.Errorf("run finishedAt = %v, events = %d, want finished with events", run.FinishedAt, len(run.Events))
+	}
+}
@@ -0,0 +1,616 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// riskBirdGeneratorConcurrency 未指定并发数时的默认值
+	riskBirdGeneratorConcurrency = 4
+	// riskBirdPhoneLength 手机号长度
+	riskBirdPhoneLength = 11
+	// riskBirdGeneratorPointDays 直接写入的积分批次的获取时间分布在最近多少天内
+	riskBirdGeneratorPointDays = 180
+)
+
+type RiskBirdGeneratorService struct{}
+
+var RiskBirdGeneratorServiceApp = new(RiskBirdGeneratorService)
+
+// 执行中的造数任务，值为停止任务的 context.CancelFunc
+var riskBirdGeneratorRunning sync.Map
+
+// CreateJob 创建造数任务，按分布为每个账号抽样目标值后立即开始执行
+func (s *RiskBirdGeneratorService) CreateJob(req systemReq.CreateRiskBirdGeneratorJob, userID uint) (job system.RiskBirdGeneratorJob, err error) {
+	env, err := riskBirdEnv(req.Env)
+	if err != nil {
+		return job, err
+	}
+	if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationGenerator); err != nil {
+		return job, err
+	}
+	if err = checkRiskBirdSecretKey(req.Password); err != nil {
+		return job, err
+	}
+	if err = checkRiskBirdGeneratorJob(req); err != nil {
+		return job, err
+	}
+	job = system.RiskBirdGeneratorJob{
+		Name:        req.Name,
+		Env:         env.Name,
+		Count:       req.Count,
+		PhonePrefix: req.PhonePrefix,
+		StartSeq:    req.StartSeq,
+		Password:    req.Password,
+		Tag:         req.Tag,
+		Strategy:    req.Strategy,
+		Concurrency: req.Concurrency,
+		Seed:        req.Seed,
+		Params:      req.Params,
+		Status:      system.RiskBirdGeneratorPending,
+		UserID:      userID,
+		AuthorityId: req.OperatorAuthorityID,
+	}
+	if job.Strategy == "" {
+		job.Strategy = system.RiskBirdGeneratorStrategyAPI
+	}
+	if job.Concurrency == 0 {
+		job.Concurrency = riskBirdGeneratorConcurrency
+	}
+	if job.Seed == 0 {
+		job.Seed = time.Now().UnixNano()
+	}
+
+	err = global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		if job.Tag == "" {
+			job.Tag = fmt.Sprintf("generator-%d", job.ID)
+			if err := tx.Model(&job).Update("tag", job.Tag).Error; err != nil {
+				return err
+			}
+		}
+		return tx.CreateInBatches(planRiskBirdGeneratorItems(job), 500).Error
+	})
+	if err != nil {
+		return job, err
+	}
+	err = s.start(&job)
+	return job, err
+}
+
+// checkRiskBirdGeneratorJob 校验手机号范围和数据分布
+func checkRiskBirdGeneratorJob(req systemReq.CreateRiskBirdGeneratorJob) error {
+	last := strconv.Itoa(req.StartSeq + req.Count - 1)
+	if len(req.PhonePrefix)+len(last) > riskBirdPhoneLength {
+		return fmt.Errorf("手机号前缀 %s 加序号 %s 超过%d位", req.PhonePrefix, last, riskBirdPhoneLength)
+	}
+	p := req.Params
+	ranges := []struct {
+		name     string
+		min, max int64
+		dist     string
+	}{
+		{"余额", p.Balance.Min.Cents(), p.Balance.Max.Cents(), p.Balance.Dist},
+		{"积分", p.Points.Min, p.Points.Max, p.Points.Dist},
+		{"积分批次数", p.PointBatches.Min, p.PointBatches.Max, p.PointBatches.Dist},
+		{"充值次数", p.Orders.Min, p.Orders.Max, p.Orders.Dist},
+	}
+	for _, r := range ranges {
+		if r.min < 0 || r.max < r.min {
+			return fmt.Errorf("%s的取值范围不正确", r.name)
+		}
+		if r.dist != "" && r.dist != system.RiskBirdDistUniform && r.dist != system.RiskBirdDistLongTail {
+			return fmt.Errorf("%s的分布 %s 不支持", r.name, r.dist)
+		}
+	}
+	return nil
+}
+
+// planRiskBirdGeneratorItems 按任务的随机种子抽样每个账号的目标值，相同种子得到相同结果
+func planRiskBirdGeneratorItems(job system.RiskBirdGeneratorJob) []system.RiskBirdGeneratorItem {
+	r := rand.New(rand.NewSource(job.Seed))
+	p := job.Params
+	items := make([]system.RiskBirdGeneratorItem, 0, job.Count)
+	for i := 0; i < job.Count; i++ {
+		seq := job.StartSeq + i
+		item := system.RiskBirdGeneratorItem{
+			JobID:   job.ID,
+			Seq:     seq,
+			Phone:   riskBirdGeneratorPhone(job.PhonePrefix, seq),
+			Balance: common.Money(sampleRiskBirdRange(r, p.Balance.Min.Cents(), p.Balance.Max.Cents(), p.Balance.Dist)),
+			Points:  sampleRiskBirdRange(r, p.Points.Min, p.Points.Max, p.Points.Dist),
+			Orders:  int(sampleRiskBirdRange(r, p.Orders.Min, p.Orders.Max, p.Orders.Dist)),
+		}
+		if job.Strategy == system.RiskBirdGeneratorStrategyDB {
+			item.PointBatches = int(sampleRiskBirdRange(r, p.PointBatches.Min, p.PointBatches.Max, p.PointBatches.Dist))
+		} else {
+			// 下单方式每1元产生5积分
+			item.Points -= item.Points % riskBirdPointsPerYuan
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// riskBirdGeneratorPhone 前缀加序号，中间补0到11位
+func riskBirdGeneratorPhone(prefix string, seq int) string {
+	s := strconv.Itoa(seq)
+	return prefix + strings.Repeat("0", riskBirdPhoneLength-len(prefix)-len(s)) + s
+}
+
+// sampleRiskBirdRange 在 [min, max] 内按分布抽样
+func sampleRiskBirdRange(r *rand.Rand, min, max int64, dist string) int64 {
+	if max <= min {
+		return min
+	}
+	u := r.Float64()
+	if dist == system.RiskBirdDistLongTail {
+		u = u * u * u
+	}
+	return min + int64(math.Round(u*float64(max-min)))
+}
+
+// ResumeJob 继续执行已停止、中断或部分失败的任务，只处理未完成的账号，已完成的阶段不会重复执行
+func (s *RiskBirdGeneratorService) ResumeJob(ID, authorityID uint) (job system.RiskBirdGeneratorJob, err error) {
+	if job, err = s.GetJob(ID, authorityID); err != nil {
+		return job, err
+	}
+	if job.Status == system.RiskBirdGeneratorSuccess {
+		return job, errors.New("任务已全部完成")
+	}
+	// 继续执行时任务内的修改按当前操作人的角色校验
+	if err = global.GVA_DB.Model(&system.RiskBirdGeneratorJob{}).Where("id = ?", job.ID).Update("authority_id", authorityID).Error; err != nil {
+		return job, err
+	}
+	job.AuthorityId = authorityID
+	err = s.start(&job)
+	return job, err
+}
+
+// StopJob 停止执行中的任务，正在处理的账号完成当前阶段后停止
+func (s *RiskBirdGeneratorService) StopJob(ID, authorityID uint) error {
+	if _, err := s.GetJob(ID, authorityID); err != nil {
+		return err
+	}
+	cancel, ok := riskBirdGeneratorRunning.Load(ID)
+	if !ok {
+		return errors.New("任务未在执行")
+	}
+	cancel.(context.CancelFunc)()
+	return nil
+}
+
+// MarkInterrupted 服务启动时将上次未结束的任务标记为中断，本进程中仍在执行的任务不标记
+func (s *RiskBirdGeneratorService) MarkInterrupted() error {
+	db := global.GVA_DB.Model(&system.RiskBirdGeneratorJob{}).Where("status = ?", system.RiskBirdGeneratorRunning)
+	var running []uint
+	riskBirdGeneratorRunning.Range(func(key, _ interface{}) bool {
+		running = append(running, key.(uint))
+		return true
+	})
+	if len(running) > 0 {
+		db = db.Where("id NOT IN ?", running)
+	}
+	return db.Update("status", system.RiskBirdGeneratorInterrupted).Error
+}
+
+func (s *RiskBirdGeneratorService) start(job *system.RiskBirdGeneratorJob) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, running := riskBirdGeneratorRunning.LoadOrStore(job.ID, cancel); running {
+		cancel()
+		return errors.New("任务正在执行中")
+	}
+	startedAt := time.Now()
+	job.Status = system.RiskBirdGeneratorRunning
+	job.StartedAt = &startedAt
+	job.FinishedAt = nil
+	job.Failed = 0
+	job.Error = ""
+	err := global.GVA_DB.Model(job).Select("status", "started_at", "finished_at", "failed", "error").Updates(job).Error
+	if err != nil {
+		riskBirdGeneratorRunning.Delete(job.ID)
+		cancel()
+		return err
+	}
+	go s.run(ctx, *job)
+	return nil
+}
+
+// run 以任务的并发数处理未完成的账号，结束时按账号状态汇总任务结果
+func (s *RiskBirdGeneratorService) run(ctx context.Context, job system.RiskBirdGeneratorJob) {
+	var runErr error
+	defer func() {
+		if r := recover(); r != nil {
+			runErr = fmt.Errorf("任务异常: %v", r)
+		}
+		s.finish(ctx, job, runErr)
+		if cancel, ok := riskBirdGeneratorRunning.LoadAndDelete(job.ID); ok {
+			cancel.(context.CancelFunc)()
+		}
+	}()
+
+	env, err := riskBirdEnv(job.Env)
+	if err != nil {
+		runErr = err
+		return
+	}
+	riskBirdDB, err := newRiskBirdDB(env)
+	if err != nil {
+		runErr = err
+		return
+	}
+	defer riskBirdDB.Close()
+
+	var items []system.RiskBirdGeneratorItem
+	err = global.GVA_DB.Where("job_id = ? AND stage <> ?", job.ID, system.RiskBirdGeneratorStageDone).Order("seq").Find(&items).Error
+	if err != nil {
+		runErr = err
+		return
+	}
+
+	queue := make(chan system.RiskBirdGeneratorItem)
+	var wg sync.WaitGroup
+	for i := 0; i < job.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				s.generateItem(ctx, job, riskBirdDB, item)
+			}
+		}()
+	}
+feed:
+	for _, item := range items {
+		select {
+		case queue <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// finish 汇总账号状态并保存任务结果
+func (s *RiskBirdGeneratorService) finish(ctx context.Context, job system.RiskBirdGeneratorJob, runErr error) {
+	var succeeded, failed int64
+	db := global.GVA_DB.Model(&system.RiskBirdGeneratorItem{}).Where("job_id = ?", job.ID)
+	db.Session(&gorm.Session{}).Where("stage = ?", system.RiskBirdGeneratorStageDone).Count(&succeeded)
+	db.Session(&gorm.Session{}).Where("stage <> ? AND error <> ''", system.RiskBirdGeneratorStageDone).Count(&failed)
+
+	finishedAt := time.Now()
+	job.Succeeded, job.Failed, job.FinishedAt = int(succeeded), int(failed), &finishedAt
+	switch {
+	case runErr != nil:
+		job.Status = system.RiskBirdGeneratorPartial
+		job.Error = runErr.Error()
+	case ctx.Err() != nil:
+		job.Status = system.RiskBirdGeneratorStopped
+	case job.Succeeded == job.Count:
+		job.Status = system.RiskBirdGeneratorSuccess
+	default:
+		job.Status = system.RiskBirdGeneratorPartial
+	}
+	err := global.GVA_DB.Model(&job).Select("status", "succeeded", "failed", "error", "finished_at").Updates(&job).Error
+	if err != nil {
+		global.GVA_LOG.Error("保存造数任务结果失败", zap.Uint("jobId", job.ID), zap.Error(err))
+	}
+	notifyRiskBirdGeneratorJob(job)
+}
+
+// generateItem 依次完成账号的注册或复用、余额和订单历史、积分三个阶段，每完成一个阶段保存一次
+func (s *RiskBirdGeneratorService) generateItem(ctx context.Context, job system.RiskBirdGeneratorJob, riskBirdDB *sql.DB, item system.RiskBirdGeneratorItem) {
+	err := s.generateStages(ctx, job, riskBirdDB, &item)
+	item.Error = ""
+	if err != nil {
+		item.Error = err.Error()
+		global.GVA_LOG.Warn("造数账号失败", zap.Uint("jobId", job.ID), zap.String("phone", item.Phone), zap.Error(err))
+	}
+	if dbErr := s.saveItem(item); dbErr != nil {
+		global.GVA_LOG.Error("保存造数账号失败", zap.Uint("jobId", job.ID), zap.String("phone", item.Phone), zap.Error(dbErr))
+	}
+	column := "succeeded"
+	if item.Stage != system.RiskBirdGeneratorStageDone {
+		if err == nil {
+			return
+		}
+		column = "failed"
+	}
+	global.GVA_DB.Model(&system.RiskBirdGeneratorJob{}).Where("id = ?", job.ID).Update(column, gorm.Expr(column+" + 1"))
+}
+
+func (s *RiskBirdGeneratorService) saveItem(item system.RiskBirdGeneratorItem) error {
+	return global.GVA_DB.Model(&item).Select("account_id", "user_id", "reused", "stage", "error").Updates(&item).Error
+}
+
+func (s *RiskBirdGeneratorService) generateStages(ctx context.Context, job system.RiskBirdGeneratorJob, riskBirdDB *sql.DB, item *system.RiskBirdGeneratorItem) error {
+	// 1. 注册新账号或复用账号库中已登记的账号
+	var account system.RiskBirdAccount
+	var err error
+	if item.Stage == "" {
+		if account, err = s.provisionOrReuse(job, item); err != nil {
+			return err
+		}
+		item.Stage = system.RiskBirdGeneratorStageAccount
+		if err = s.saveItem(*item); err != nil {
+			return err
+		}
+	} else if account, err = getRiskBirdAccount(item.AccountID); err != nil {
+		return fmt.Errorf("读取账号失败: %w", err)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// 2. 多次充值产生订单历史，最后一次充值到目标余额
+	if item.Stage == system.RiskBirdGeneratorStageAccount {
+		if err = s.generateBalance(job, account, *item); err != nil {
+			return err
+		}
+		item.Stage = system.RiskBirdGeneratorStageBalance
+		if err = s.saveItem(*item); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// 3. 生成积分
+	if err = s.generatePoints(job, riskBirdDB, account, *item); err != nil {
+		return err
+	}
+	item.Stage = system.RiskBirdGeneratorStageDone
+	return nil
+}
+
+// provisionOrReuse 账号库中已登记该手机号时直接复用，否则注册新用户并登记
+func (s *RiskBirdGeneratorService) provisionOrReuse(job system.RiskBirdGeneratorJob, item *system.RiskBirdGeneratorItem) (account system.RiskBirdAccount, err error) {
+	err = global.GVA_DB.Where("env = ? AND phone = ?", job.Env, item.Phone).First(&account).Error
+	if err == nil {
+		item.AccountID, item.UserID, item.Reused = account.ID, account.UserID, true
+		return account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return account, err
+	}
+	account, err = RiskBirdAccountServiceApp.ProvisionAccount(systemReq.ProvisionRiskBirdAccount{
+		Env:      job.Env,
+		Phone:    item.Phone,
+		Password: job.Password,
+		Tags:     []string{job.Tag},
+		Remark:   fmt.Sprintf("批量造数任务 %s", job.Name),
+
+		OperatorID:          job.UserID,
+		OperatorAuthorityID: job.AuthorityId,
+	})
+	if err != nil {
+		return account, err
+	}
+	account.Password = job.Password
+	item.AccountID, item.UserID = account.ID, account.UserID
+	return account, nil
+}
+
+// generateBalance 充值 Orders 次，中间几次的金额按余额分布抽样，余额为0的账号不充值
+func (s *RiskBirdGeneratorService) generateBalance(job system.RiskBirdGeneratorJob, account system.RiskBirdAccount, item system.RiskBirdGeneratorItem) error {
+	if item.Balance == 0 {
+		return nil
+	}
+	rounds := max(item.Orders, 1)
+	r := rand.New(rand.NewSource(job.Seed + int64(item.Seq)))
+	balance := job.Params.Balance
+	for i := 1; i <= rounds; i++ {
+		amount := item.Balance
+		if i < rounds {
+			amount = common.Money(sampleRiskBirdRange(r, max(balance.Min.Cents(), 1), balance.Max.Cents(), balance.Dist))
+		}
+		err := UserBalanceServiceApp.ModifyUserBalance(systemReq.ModifyUserBalance{
+			Env:            job.Env,
+			Phone:          account.Phone,
+			Password:       account.Password,
+			SmsLogin:       account.Password == "",
+			RechargeAmount: amount,
+
+			OperatorID:          job.UserID,
+			OperatorAuthorityID: job.AuthorityId,
+		})
+		if err != nil {
+			return fmt.Errorf("第%d次充值失败: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// generatePoints api 方式通过下单产生积分；db 方式清空已有积分后按批次直接写入积分记录
+func (s *RiskBirdGeneratorService) generatePoints(job system.RiskBirdGeneratorJob, riskBirdDB *sql.DB, account system.RiskBirdAccount, item system.RiskBirdGeneratorItem) error {
+	modify := func(points int64, strategy string) error {
+		return UserPointServiceApp.ModifyUserPoint(systemReq.ModifyUserPoint{
+			Env:         job.Env,
+			Phone:       account.Phone,
+			Password:    account.Password,
+			SmsLogin:    account.Password == "",
+			PointAmount: points,
+			Strategy:    strategy,
+
+			OperatorID:          job.UserID,
+			OperatorAuthorityID: job.AuthorityId,
+		})
+	}
+	if job.Strategy != system.RiskBirdGeneratorStrategyDB {
+		return modify(item.Points, systemReq.ModifyUserPointStrategyOrder)
+	}
+	if item.PointBatches <= 1 || item.Points < 2 {
+		return modify(item.Points, systemReq.ModifyUserPointStrategyDirect)
+	}
+	if item.UserID == 0 {
+		return errors.New("账号缺少RiskBird用户ID，无法直接写入积分")
+	}
+	if err := modify(0, systemReq.ModifyUserPointStrategyDirect); err != nil {
+		return err
+	}
+	r := rand.New(rand.NewSource(job.Seed + int64(item.Seq)))
+	for _, points := range splitRiskBirdPoints(r, item.Points, item.PointBatches) {
+		pointTime := time.Now().AddDate(0, 0, -r.Intn(riskBirdGeneratorPointDays)-1)
+		if _, err := request.InsertPointAcquisition(riskBirdDB, item.UserID, points, pointTime, pointTime.Add(riskBirdPointValidity)); err != nil {
+			return fmt.Errorf("写入积分记录失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// splitRiskBirdPoints 将积分随机拆分为 batches 批，每批至少1分
+func splitRiskBirdPoints(r *rand.Rand, total int64, batches int) []int64 {
+	if int64(batches) > total {
+		batches = int(total)
+	}
+	seen := make(map[int64]bool, batches-1)
+	cuts := make([]int64, 0, batches-1)
+	for len(cuts) < batches-1 {
+		cut := 1 + r.Int63n(total-1)
+		if !seen[cut] {
+			seen[cut] = true
+			cuts = append(cuts, cut)
+		}
+	}
+	slices.Sort(cuts)
+	parts := make([]int64, 0, batches)
+	var last int64
+	for _, cut := range cuts {
+		parts = append(parts, cut-last)
+		last = cut
+	}
+	return append(parts, total-last)
+}
+
+// GetJob 根据ID获取造数任务，校验操作人角色在任务环境中的造数权限
+func (s *RiskBirdGeneratorService) GetJob(ID, authorityID uint) (job system.RiskBirdGeneratorJob, err error) {
+	if err = global.GVA_DB.Where("id = ?", ID).First(&job).Error; err != nil {
+		return job, err
+	}
+	return job, checkRiskBirdScope(authorityID, job.Env, system.RiskBirdOperationGenerator)
+}
+
+// GetJobList 分页获取造数任务，只返回操作人角色可以查看的环境
+func (s *RiskBirdGeneratorService) GetJobList(info systemReq.RiskBirdGeneratorJobSearch, authorityID uint) (list []system.RiskBirdGeneratorJob, total int64, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdGeneratorJob{}).Where("env IN ?", envs)
+	if info.Name != "" {
+		db = db.Where("name LIKE ?", "%"+info.Name+"%")
+	}
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Status != "" {
+		db = db.Where("status = ?", info.Status)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+// GetItemList 分页获取造数任务的账号清单
+func (s *RiskBirdGeneratorService) GetItemList(info systemReq.RiskBirdGeneratorItemSearch, authorityID uint) (list []system.RiskBirdGeneratorItem, total int64, err error) {
+	if _, err = s.GetJob(info.JobID, authorityID); err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdGeneratorItem{}).Where("job_id = ?", info.JobID)
+	if info.Phone != "" {
+		db = db.Where("phone LIKE ?", "%"+info.Phone+"%")
+	}
+	if info.Stage != "" {
+		db = db.Where("stage = ?", info.Stage)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("seq").Find(&list).Error
+	return list, total, err
+}
+
+// ExportManifest 导出造数任务的账号清单为 CSV，包含登录密码，供性能测试脚本直接使用
+func (s *RiskBirdGeneratorService) ExportManifest(ID, authorityID uint) (data []byte, job system.RiskBirdGeneratorJob, err error) {
+	if job, err = s.GetJob(ID, authorityID); err != nil {
+		return nil, job, err
+	}
+	var items []system.RiskBirdGeneratorItem
+	if err = global.GVA_DB.Where("job_id = ?", ID).Order("seq").Find(&items).Error; err != nil {
+		return nil, job, err
+	}
+	// 复用的账号使用账号库中的密码
+	var accounts []system.RiskBirdAccount
+	passwords := make(map[uint]string)
+	if err = global.GVA_DB.Select("id", "password").Where("id IN (?)", global.GVA_DB.Model(&system.RiskBirdGeneratorItem{}).
+		Select("account_id").Where("job_id = ?", ID)).Find(&accounts).Error; err != nil {
+		return nil, job, err
+	}
+	for _, account := range accounts {
+		passwords[account.ID] = account.Password
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"seq", "env", "phone", "password", "account_id", "user_id", "balance", "points", "point_batches", "orders", "reused", "stage", "error"})
+	for _, item := range items {
+		password, ok := passwords[item.AccountID]
+		if !ok {
+			password = job.Password
+		}
+		_ = w.Write([]string{
+			strconv.Itoa(item.Seq),
+			job.Env,
+			item.Phone,
+			password,
+			strconv.FormatUint(uint64(item.AccountID), 10),
+			strconv.FormatInt(item.UserID, 10),
+			item.Balance.String(),
+			strconv.FormatInt(item.Points, 10),
+			strconv.Itoa(item.PointBatches),
+			strconv.Itoa(item.Orders),
+			strconv.FormatBool(item.Reused),
+			item.Stage,
+			item.Error,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), job, w.Error()
+}
@@ -0,0 +1,110 @@
+package system
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRiskBirdGeneratorPhone(t *testing.T) {
+	if got := riskBirdGeneratorPhone("1990", 42); got != "19900000042" {
+		t.Errorf("riskBirdGeneratorPhone() got = %s, want 19900000042", got)
+	}
+}
+
+func TestPlanRiskBirdGeneratorItems(t *testing.T) {
+	job := system.RiskBirdGeneratorJob{
+		Count:       50,
+		PhonePrefix: "199",
+		StartSeq:    1,
+		Strategy:    system.RiskBirdGeneratorStrategyAPI,
+		Seed:        7,
+		Params: system.RiskBirdGeneratorParams{
+			Balance: system.RiskBirdMoneyRange{Min: common.Yuan(10), Max: common.Yuan(1000), Dist: system.RiskBirdDistLongTail},
+			Points:  system.RiskBirdIntRange{Min: 0, Max: 5000},
+			Orders:  system.RiskBirdIntRange{Min: 1, Max: 3},
+		},
+	}
+	items := planRiskBirdGeneratorItems(job)
+	if len(items) != job.Count {
+		t.Fatalf("planRiskBirdGeneratorItems() got %d items, want %d", len(items), job.Count)
+	}
+	for _, item := range items {
+		if item.Balance < common.Yuan(10) || item.Balance > common.Yuan(1000) {
+			t.Errorf("item %d balance = %s, out of range", item.Seq, item.Balance)
+		}
+		if item.Points%riskBirdPointsPerYuan != 0 {
+			t.Errorf("item %d points = %d, want multiple of %d", item.Seq, item.Points, riskBirdPointsPerYuan)
+		}
+		if item.Orders < 1 || item.Orders > 3 {
+			t.Errorf("item %d orders = %d, out of range", item.Seq, item.Orders)
+		}
+	}
+	// 相同种子生成相同的计划，保证继续执行和重新创建结果一致
+	if again := planRiskBirdGeneratorItems(job); !reflect.DeepEqual(items, again) {
+		t.Error("planRiskBirdGeneratorItems() not deterministic for the same seed")
+	}
+}
+
+func TestSplitRiskBirdPoints(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, tt := range []struct {
+		total   int64
+		batches int
+		want    int
+	}{{100, 4, 4}, {3, 5, 3}, {1, 1, 1}} {
+		parts := splitRiskBirdPoints(r, tt.total, tt.batches)
+		var sum int64
+		for _, p := range parts {
+			if p < 1 {
+				t.Errorf("splitRiskBirdPoints(%d, %d) got part %d", tt.total, tt.batches, p)
+			}
+			sum += p
+		}
+		if len(parts) != tt.want || sum != tt.total {
+			t.Errorf("splitRiskBirdPoints(%d, %d) got = %v", tt.total, tt.batches, parts)
+		}
+	}
+}
+
+func TestMarkInterruptedSkipsRunningJobs(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&system.RiskBirdGeneratorJob{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB := global.GVA_DB
+	global.GVA_DB = db
+	defer func() { global.GVA_DB = oldDB }()
+
+	jobs := []system.RiskBirdGeneratorJob{{Status: system.RiskBirdGeneratorRunning}, {Status: system.RiskBirdGeneratorRunning}}
+	if err = db.Create(&jobs).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 第一个任务仍在本进程中执行
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	riskBirdGeneratorRunning.Store(jobs[0].ID, cancel)
+	defer riskBirdGeneratorRunning.Delete(jobs[0].ID)
+
+	if err = RiskBirdGeneratorServiceApp.MarkInterrupted(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{system.RiskBirdGeneratorRunning, system.RiskBirdGeneratorInterrupted}
+	for i, job := range jobs {
+		var got system.RiskBirdGeneratorJob
+		db.First(&got, job.ID)
+		if got.Status != want[i] {
+			t.Errorf("job %d status = %s, want %s", job.ID, got.Status, want[i])
+		}
+	}
+}
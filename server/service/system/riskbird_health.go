@@ -0,0 +1,266 @@
+package system
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+const (
+	// riskBirdHealthCheckSpec 未配置 health-check-spec 时的检查频率
+	riskBirdHealthCheckSpec = "*/10 * * * *"
+	// riskBirdHealthCheckTaskName 健康检查在 GVA_Timer 中的任务名称
+	riskBirdHealthCheckTaskName = "riskbird-health-check"
+	// riskBirdHealthCheckTimeout 单项检查超时时间，避免环境故障时检查本身长时间阻塞
+	riskBirdHealthCheckTimeout = 10 * time.Second
+	// riskBirdHealthRetention 健康检查报告保留时间
+	riskBirdHealthRetention = 7 * 24 * time.Hour
+)
+
+type RiskBirdHealthService struct{}
+
+var RiskBirdHealthServiceApp = new(RiskBirdHealthService)
+
+// CheckEnv 检查指定环境的数据库、表结构、固定数据和接口，结果保存为健康检查报告
+func (s *RiskBirdHealthService) CheckEnv(envName, trigger string, authorityID uint) (report system.RiskBirdHealthReport, err error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return report, err
+	}
+	if err = checkRiskBirdScope(authorityID, env.Name, system.RiskBirdOperationHealthCheck); err != nil {
+		return report, err
+	}
+	report = system.RiskBirdHealthReport{
+		Env:       env.Name,
+		Trigger:   trigger,
+		CheckedAt: time.Now(),
+	}
+	report.Checks = append(report.Checks, checkRiskBirdDB(env)...)
+	report.Checks = append(report.Checks, checkRiskBirdAPI(env)...)
+	report.Healthy = true
+	for _, check := range report.Checks {
+		if !check.Healthy {
+			report.Healthy = false
+			break
+		}
+	}
+	// 命令行等未配置管理后台数据库的场景只返回报告
+	if global.GVA_DB == nil {
+		return report, nil
+	}
+	err = global.GVA_DB.Create(&report).Error
+	return report, err
+}
+
+// CheckAllEnvs 依次检查全部环境，由定时任务调用
+func (s *RiskBirdHealthService) CheckAllEnvs(trigger string) {
+	for _, name := range global.GVA_CONFIG.RiskBird.EnvNames() {
+		report, err := s.CheckEnv(name, trigger, system.RiskBirdInternalAuthorityID)
+		if err != nil {
+			global.GVA_LOG.Error("RiskBird环境健康检查失败", zap.String("env", name), zap.Error(err))
+			continue
+		}
+		if !report.Healthy {
+			global.GVA_LOG.Warn("RiskBird环境异常", zap.String("env", name), zap.Any("checks", report.Checks))
+		}
+	}
+	err := global.GVA_DB.Where("checked_at < ?", time.Now().Add(-riskBirdHealthRetention)).Delete(&system.RiskBirdHealthReport{}).Error
+	if err != nil {
+		global.GVA_LOG.Error("清理RiskBird健康检查报告失败", zap.Error(err))
+	}
+}
+
+// RegisterHealthCheck 在 GVA_Timer 中注册定时健康检查
+func (s *RiskBirdHealthService) RegisterHealthCheck() error {
+	// 重新加载配置时会再次注册，先移除已注册的任务，避免重复执行
+	global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, riskBirdHealthCheckTaskName)
+	spec := global.GVA_CONFIG.RiskBird.HealthCheckSpec
+	if spec == "off" {
+		return nil
+	}
+	if spec == "" {
+		spec = riskBirdHealthCheckSpec
+	}
+	_, err := global.GVA_Timer.AddTaskByFunc(RiskBirdCronName, spec, func() {
+		s.CheckAllEnvs("cron")
+	}, riskBirdHealthCheckTaskName)
+	return err
+}
+
+// GetLatestReports 获取操作人角色可以查看的每个环境最近一次的健康检查报告，尚未检查过的环境不返回
+func (s *RiskBirdHealthService) GetLatestReports(authorityID uint) (list []system.RiskBirdHealthReport, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range envs {
+		var reports []system.RiskBirdHealthReport
+		if err = global.GVA_DB.Where("env = ?", name).Order("id desc").Limit(1).Find(&reports).Error; err != nil {
+			return nil, err
+		}
+		list = append(list, reports...)
+	}
+	return list, nil
+}
+
+// GetHealthReportList 分页获取健康检查报告，只返回操作人角色可以查看的环境
+func (s *RiskBirdHealthService) GetHealthReportList(info systemReq.RiskBirdHealthReportSearch, authorityID uint) (list []system.RiskBirdHealthReport, total int64, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdHealthReport{}).Where("env IN ?", envs)
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Healthy != nil {
+		db = db.Where("healthy = ?", *info.Healthy)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+// runRiskBirdHealthCheck 执行单项检查并记录耗时
+func runRiskBirdHealthCheck(name, target string, check func() error) system.RiskBirdHealthCheck {
+	start := time.Now()
+	err := check()
+	result := system.RiskBirdHealthCheck{
+		Name:    name,
+		Target:  target,
+		Healthy: err == nil,
+		Elapsed: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// checkRiskBirdDB 检查数据库连通性、依赖的字段和固定数据，数据库不可用时其余项直接记为失败
+func checkRiskBirdDB(env config.RiskBirdEnv) []system.RiskBirdHealthCheck {
+	tables := make([]string, 0, len(request.RiskBirdRequiredColumns))
+	for table := range request.RiskBirdRequiredColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	fixtures := []struct {
+		table string
+		id    int
+	}{
+		{table: "p_product_cfg", id: request.ReportPriceCfgID},
+		{table: "p_recharge_product", id: request.RechargeProductID},
+	}
+
+	var db *sql.DB
+	checks := []system.RiskBirdHealthCheck{runRiskBirdHealthCheck(system.RiskBirdHealthCheckDB, env.DB.Database, func() (err error) {
+		if db, err = newRiskBirdDB(env); err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), riskBirdHealthCheckTimeout)
+		defer cancel()
+		return db.PingContext(ctx)
+	})}
+	if db != nil {
+		defer db.Close()
+	}
+	if !checks[0].Healthy {
+		for _, table := range tables {
+			checks = append(checks, system.RiskBirdHealthCheck{Name: system.RiskBirdHealthCheckSchema, Target: table, Message: "数据库不可用"})
+		}
+		for _, fixture := range fixtures {
+			checks = append(checks, system.RiskBirdHealthCheck{Name: system.RiskBirdHealthCheckFixture, Target: fmt.Sprintf("%s#%d", fixture.table, fixture.id), Message: "数据库不可用"})
+		}
+		return checks
+	}
+
+	for _, table := range tables {
+		required := request.RiskBirdRequiredColumns[table]
+		checks = append(checks, runRiskBirdHealthCheck(system.RiskBirdHealthCheckSchema, table, func() error {
+			columns, err := request.GetTableColumns(db, table)
+			if err != nil {
+				return err
+			}
+			if len(columns) == 0 {
+				return fmt.Errorf("表 %s 不存在", table)
+			}
+			existing := make(map[string]bool, len(columns))
+			for _, column := range columns {
+				existing[strings.ToLower(column)] = true
+			}
+			var missing []string
+			for _, column := range required {
+				if !existing[column] {
+					missing = append(missing, column)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("缺少字段: %s", strings.Join(missing, ", "))
+			}
+			return nil
+		}))
+	}
+	for _, fixture := range fixtures {
+		checks = append(checks, runRiskBirdHealthCheck(system.RiskBirdHealthCheckFixture, fmt.Sprintf("%s#%d", fixture.table, fixture.id), func() error {
+			exists, err := request.RowExists(db, fixture.table, fixture.id)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("%s 中不存在ID为 %d 的记录", fixture.table, fixture.id)
+			}
+			return nil
+		}))
+	}
+	return checks
+}
+
+// checkRiskBirdAPI 检查用户端接口、后台管理接口和环境熔断器。
+// 探测不经过熔断器也不重试，熔断期间同样能反映接口的真实状态
+func checkRiskBirdAPI(env config.RiskBirdEnv) []system.RiskBirdHealthCheck {
+	client := newRiskBirdClient(env)
+	client.Client.Timeout = riskBirdHealthCheckTimeout
+	client.Breaker = nil
+	client.Retry = nil
+	return []system.RiskBirdHealthCheck{
+		checkRiskBirdBreaker(env),
+		runRiskBirdHealthCheck(system.RiskBirdHealthCheckUserAPI, client.BaseURL, client.ProbeUserAPI),
+		runRiskBirdHealthCheck(system.RiskBirdHealthCheckAdminAPI, client.AdminBaseURL, func() error {
+			_, err := riskBirdAdminLogin(env, client)
+			return err
+		}),
+	}
+}
+
+// checkRiskBirdBreaker 报告环境熔断器状态，熔断或半开时记为异常
+func checkRiskBirdBreaker(env config.RiskBirdEnv) system.RiskBirdHealthCheck {
+	snapshot := riskBirdBreaker(env.Name).Snapshot()
+	check := system.RiskBirdHealthCheck{
+		Name:    system.RiskBirdHealthCheckBreaker,
+		Target:  snapshot.State,
+		Healthy: snapshot.State == request.BreakerClosed,
+	}
+	if !check.Healthy {
+		check.Message = fmt.Sprintf("连续失败%d次，熔断时间: %s，最近错误: %s",
+			snapshot.Failures, snapshot.OpenedAt.Format(time.DateTime), snapshot.LastError)
+	}
+	return check
+}
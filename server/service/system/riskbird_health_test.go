@@ -0,0 +1,35 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+func TestRegisterHealthCheckReplacesTask(t *testing.T) {
+	defer global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, riskBirdHealthCheckTaskName)
+
+	// 重新加载配置时再次注册，只保留一个任务
+	for range 2 {
+		if err := RiskBirdHealthServiceApp.RegisterHealthCheck(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, riskBirdHealthCheckTaskName)
+	if _, ok := global.GVA_Timer.FindTask(RiskBirdCronName, riskBirdHealthCheckTaskName); ok {
+		t.Error("RegisterHealthCheck() registered the task more than once")
+	}
+
+	// 关闭后重新加载时移除已注册的任务
+	if err := RiskBirdHealthServiceApp.RegisterHealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+	global.GVA_CONFIG.RiskBird.HealthCheckSpec = "off"
+	defer func() { global.GVA_CONFIG.RiskBird.HealthCheckSpec = "" }()
+	if err := RiskBirdHealthServiceApp.RegisterHealthCheck(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := global.GVA_Timer.FindTask(RiskBirdCronName, riskBirdHealthCheckTaskName); ok {
+		t.Error("RegisterHealthCheck() with spec off kept the task")
+	}
+}
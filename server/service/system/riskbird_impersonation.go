@@ -0,0 +1,228 @@
+package system
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+type RiskBirdImpersonationService struct{}
+
+var RiskBirdImpersonationServiceApp = new(RiskBirdImpersonationService)
+
+// riskBirdLogin 流程定位并登录 RiskBird 用户所需的信息
+type riskBirdLogin struct {
+	Flow         string
+	Phone        string
+	Password     string
+	SmsLogin     bool
+	Impersonate  bool
+	TargetUserID int64
+	OperatorID   uint
+	AuthorityID  uint
+}
+
+// riskBirdSession 登录后的用户会话
+type riskBirdSession struct {
+	Token  string
+	UserID int64
+	Phone  string
+}
+
+// checkRiskBirdLogin 校验登录方式，代登录时检查环境是否开启代登录以及操作人角色是否允许
+func checkRiskBirdLogin(envName string, login riskBirdLogin) error {
+	if !login.Impersonate {
+		if login.Phone == "" {
+			return errors.New("请输入手机号")
+		}
+		if login.Password == "" && !login.SmsLogin {
+			return errors.New("请输入密码或使用验证码登录")
+		}
+		if login.TargetUserID != 0 {
+			return errors.New("按用户ID操作需要使用代登录")
+		}
+		return nil
+	}
+	if login.Phone == "" && login.TargetUserID == 0 {
+		return errors.New("代登录需要指定用户ID或手机号")
+	}
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return err
+	}
+	return checkRiskBirdImpersonation(env, login)
+}
+
+// checkRiskBirdImpersonation 检查代登录配置和操作人角色，被拒绝时记录审计
+func checkRiskBirdImpersonation(env config.RiskBirdEnv, login riskBirdLogin) error {
+	cfg := env.Impersonation
+	var err error
+	switch {
+	case cfg.Mode != config.RiskBirdImpersonateAdmin && cfg.Mode != config.RiskBirdImpersonateTestToken:
+		err = fmt.Errorf("RiskBird环境 %s 未开启代登录", env.Name)
+	case cfg.TokenPath == "":
+		err = fmt.Errorf("RiskBird环境 %s 未配置代登录接口", env.Name)
+	case !slices.Contains(cfg.AuthorityIds, login.AuthorityID):
+		err = errors.New("当前角色无权使用代登录")
+	}
+	if err != nil {
+		recordRiskBirdImpersonation(env, login, login.TargetUserID, login.Phone, err)
+	}
+	return err
+}
+
+// loginRiskBirdUser 流程的用户登录步骤，使用手机号和密码或短信验证码登录，或按环境配置代登录
+func loginRiskBirdUser(env config.RiskBirdEnv, db *sql.DB, client *request.RiskBirdAPIClient, login riskBirdLogin) (session riskBirdSession, err error) {
+	if login.Impersonate {
+		return impersonateRiskBirdUser(env, db, client, login)
+	}
+	if login.SmsLogin {
+		return loginRiskBirdUserBySms(env, db, client, login.Phone)
+	}
+
+	loginResp, err := client.Login(login.Phone, login.Password)
+	if err != nil {
+		global.GVA_LOG.Error("RiskBird用户登录失败",
+			zap.String("phone", login.Phone),
+			zap.String("error_message", err.Error()))
+		return session, errors.New("请输入正确的手机号和密码")
+	}
+	global.GVA_LOG.Info("RiskBird用户登录成功", zap.String("phone", login.Phone))
+	token, _ := loginResp["token"].(string)
+	return riskBirdSession{Token: token, UserID: riskBirdUserID(loginResp), Phone: login.Phone}, nil
+}
+
+// loginRiskBirdUserBySms 发送短信验证码，取得验证码后登录，用于没有密码的测试账号
+func loginRiskBirdUserBySms(env config.RiskBirdEnv, db *sql.DB, client *request.RiskBirdAPIClient, phone string) (session riskBirdSession, err error) {
+	code, err := riskBirdSmsCode(env, db, client, phone)
+	if err != nil {
+		return session, err
+	}
+	loginPath := env.SMS.LoginPath
+	if loginPath == "" {
+		loginPath = "/loginBySms"
+	}
+	loginResp, err := client.LoginBySms(loginPath, phone, code)
+	if err != nil {
+		global.GVA_LOG.Error("RiskBird用户验证码登录失败",
+			zap.String("phone", phone),
+			zap.String("error_message", err.Error()))
+		return session, fmt.Errorf("RiskBird用户验证码登录失败: %w", err)
+	}
+	global.GVA_LOG.Info("RiskBird用户验证码登录成功", zap.String("phone", phone))
+	token, _ := loginResp["token"].(string)
+	return riskBirdSession{Token: token, UserID: riskBirdUserID(loginResp), Phone: phone}, nil
+}
+
+// riskBirdUserTable 补全用户表结构的默认值
+func riskBirdUserTable(cfg config.RiskBirdImpersonation) request.UserTable {
+	table := request.UserTable{Table: cfg.UserTable, MobileColumn: cfg.MobileColumn}
+	if table.Table == "" {
+		table.Table = "p_user"
+	}
+	if table.MobileColumn == "" {
+		table.MobileColumn = "mobile"
+	}
+	return table
+}
+
+// impersonateRiskBirdUser 查找目标用户并签发token，无论成功与否都记录审计
+func impersonateRiskBirdUser(env config.RiskBirdEnv, db *sql.DB, client *request.RiskBirdAPIClient, login riskBirdLogin) (session riskBirdSession, err error) {
+	// 流程开始前已校验，这里再次校验避免排队期间配置变更
+	if err = checkRiskBirdImpersonation(env, login); err != nil {
+		return session, err
+	}
+	cfg := env.Impersonation
+	defer func() {
+		recordRiskBirdImpersonation(env, login, session.UserID, session.Phone, err)
+	}()
+
+	session.UserID, session.Phone, err = request.FindUser(db, riskBirdUserTable(cfg), login.TargetUserID, login.Phone)
+	if errors.Is(err, sql.ErrNoRows) {
+		session.UserID, session.Phone = login.TargetUserID, login.Phone
+		return session, errors.New("RiskBird用户不存在")
+	}
+	if err != nil {
+		global.GVA_LOG.Error("查询RiskBird用户失败", zap.Error(err))
+		return session, errors.New("查询RiskBird用户失败")
+	}
+
+	if cfg.Mode == config.RiskBirdImpersonateAdmin {
+		adminToken, err := riskBirdAdminLogin(env, client)
+		if err != nil {
+			global.GVA_LOG.Error("管理员登录失败", zap.Error(err))
+			return session, errors.New("管理员登录失败")
+		}
+		session.Token, err = client.ImpersonateUser(adminToken, cfg.TokenPath, session.UserID)
+		return session, err
+	}
+	session.Token, err = client.IssueTestToken(cfg.TokenPath, cfg.Secret, session.UserID)
+	return session, err
+}
+
+// recordRiskBirdImpersonation 记录代登录审计
+func recordRiskBirdImpersonation(env config.RiskBirdEnv, login riskBirdLogin, userID int64, phone string, err error) {
+	// 命令行工具未配置管理后台数据库时不记录
+	if global.GVA_DB == nil {
+		return
+	}
+	log := system.RiskBirdImpersonationLog{
+		Env:          env.Name,
+		Flow:         login.Flow,
+		Mode:         env.Impersonation.Mode,
+		TargetUserID: userID,
+		Phone:        phone,
+		OperatorID:   login.OperatorID,
+		AuthorityID:  login.AuthorityID,
+		Success:      err == nil,
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+	if dbErr := global.GVA_DB.Create(&log).Error; dbErr != nil {
+		global.GVA_LOG.Error("记录代登录审计失败", zap.Error(dbErr))
+	}
+}
+
+// GetImpersonationLogList 分页获取代登录审计记录，只返回操作人角色可以查看的环境
+func (s *RiskBirdImpersonationService) GetImpersonationLogList(info systemReq.RiskBirdImpersonationLogSearch, authorityID uint) (list []system.RiskBirdImpersonationLog, total int64, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdImpersonationLog{}).Where("env IN ?", envs)
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Phone != "" {
+		db = db.Where("phone = ?", info.Phone)
+	}
+	if info.TargetUserID != 0 {
+		db = db.Where("target_user_id = ?", info.TargetUserID)
+	}
+	if info.OperatorID != 0 {
+		db = db.Where("operator_id = ?", info.OperatorID)
+	}
+	if info.Success != nil {
+		db = db.Where("success = ?", *info.Success)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
@@ -0,0 +1,79 @@
+package system
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+func TestCheckRiskBirdLogin(t *testing.T) {
+	global.GVA_CONFIG.RiskBird = config.RiskBird{
+		Impersonation: config.RiskBirdImpersonation{
+			Mode:         config.RiskBirdImpersonateAdmin,
+			TokenPath:    "/admin/user/token",
+			AuthorityIds: []uint{888},
+		},
+		Envs: []config.RiskBirdEnv{{Name: "staging"}},
+	}
+	tests := []struct {
+		name    string
+		env     string
+		login   riskBirdLogin
+		wantErr bool
+	}{
+		{name: "password", login: riskBirdLogin{Phone: "13800000000", Password: "secret"}},
+		{name: "missing password", login: riskBirdLogin{Phone: "13800000000"}, wantErr: true},
+		{name: "sms login", login: riskBirdLogin{Phone: "13800000000", SmsLogin: true}},
+		{name: "sms login without phone", login: riskBirdLogin{SmsLogin: true}, wantErr: true},
+		{name: "user id without impersonation", login: riskBirdLogin{Phone: "13800000000", Password: "secret", TargetUserID: 1}, wantErr: true},
+		{name: "impersonate by user id", login: riskBirdLogin{Impersonate: true, TargetUserID: 1, AuthorityID: 888}},
+		{name: "impersonate without target", login: riskBirdLogin{Impersonate: true, AuthorityID: 888}, wantErr: true},
+		{name: "authority not allowed", login: riskBirdLogin{Impersonate: true, TargetUserID: 1, AuthorityID: 9528}, wantErr: true},
+		{name: "env not enabled", env: "staging", login: riskBirdLogin{Impersonate: true, TargetUserID: 1, AuthorityID: 888}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkRiskBirdLogin(tt.env, tt.login); (err != nil) != tt.wantErr {
+				t.Errorf("checkRiskBirdLogin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoginRiskBirdUserBySms(t *testing.T) {
+	global.GVA_LOG = zap.NewNop()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sendSmsCode":
+			_, _ = w.Write([]byte(`{"code":20000}`))
+		case "/loginBySms":
+			if r.URL.Query().Get("code") != "666666" {
+				_, _ = w.Write([]byte(`{"code":40001,"msg":"验证码错误"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":20000,"data":{"token":"t1","user":{"id":42}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	env := config.RiskBirdEnv{Name: "default", SMS: config.RiskBirdSMS{BypassCode: "666666"}}
+	session, err := loginRiskBirdUser(env, nil, request.NewRiskBirdAPIClient(server.URL), riskBirdLogin{Phone: "13800000000", SmsLogin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Token != "t1" || session.UserID != 42 {
+		t.Errorf("loginRiskBirdUser() session = %+v, want token t1 user 42", session)
+	}
+
+	env.SMS.BypassCode = "000000"
+	if _, err = loginRiskBirdUser(env, nil, request.NewRiskBirdAPIClient(server.URL), riskBirdLogin{Phone: "13800000000", SmsLogin: true}); err == nil {
+		t.Error("loginRiskBirdUser() with wrong code want error")
+	}
+}
@@ -0,0 +1,140 @@
+package system
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+)
+
+type RiskBirdJobService struct{}
+
+var RiskBirdJobServiceApp = new(RiskBirdJobService)
+
+// 响应内容最多保存的字节数，MySQL text 列最多保存 65535 字节
+const riskBirdJobBodyLimit = 64*1024 - 1
+
+// builtinRiskBirdJobs 流程中已在使用的定时任务接口，环境未配置同名任务时自动加入目录
+var builtinRiskBirdJobs = []config.RiskBirdJob{
+	{Name: "expirePoint", Path: "/guest/job/expirePoint", Method: "GET", Description: "积分失效"},
+	{Name: "pointAuditDay", Path: "/guest/job/pointAuditDay", Method: "GET", Description: "积分日审核"},
+}
+
+// GetJobCatalog 获取指定环境的定时任务接口目录
+func (s *RiskBirdJobService) GetJobCatalog(envName string) ([]config.RiskBirdJob, error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	jobs := append([]config.RiskBirdJob{}, env.Jobs...)
+	for _, builtin := range builtinRiskBirdJobs {
+		exists := false
+		for _, job := range jobs {
+			if job.Name == builtin.Name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			jobs = append(jobs, builtin)
+		}
+	}
+	return jobs, nil
+}
+
+// TriggerJob 触发定时任务接口并记录调用结果
+func (s *RiskBirdJobService) TriggerJob(req systemReq.TriggerRiskBirdJob, userID uint) (record system.RiskBirdJobRecord, err error) {
+	env, err := riskBirdEnv(req.Env)
+	if err != nil {
+		return record, err
+	}
+	if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationJob); err != nil {
+		return record, err
+	}
+	jobs, err := s.GetJobCatalog(env.Name)
+	if err != nil {
+		return record, err
+	}
+	var job *config.RiskBirdJob
+	for i := range jobs {
+		if jobs[i].Name == req.JobName {
+			job = &jobs[i]
+			break
+		}
+	}
+	if job == nil {
+		return record, fmt.Errorf("RiskBird环境 %s 中不存在定时任务 %s", env.Name, req.JobName)
+	}
+
+	record = system.RiskBirdJobRecord{
+		Env:     env.Name,
+		JobName: job.Name,
+		Method:  job.Method,
+		Path:    job.Path,
+		Params:  req.Params,
+		UserID:  userID,
+	}
+	if record.Method == "" {
+		record.Method = "GET"
+	}
+
+	client := request.NewRiskBirdAPIClient(env.API.BaseUrl)
+	start := time.Now()
+	statusCode, body, callErr := client.TriggerJob(record.Method, job.Path, req.Params)
+	record.Latency = time.Since(start).Milliseconds()
+	record.StatusCode = statusCode
+	record.Body = truncateRiskBirdJobBody(body)
+	if callErr != nil {
+		record.Error = callErr.Error()
+	}
+
+	if err = global.GVA_DB.Create(&record).Error; err != nil {
+		return record, err
+	}
+	return record, callErr
+}
+
+// truncateRiskBirdJobBody 截断超出长度的响应内容，截断位置退回到完整的 UTF-8 字符边界
+func truncateRiskBirdJobBody(body []byte) string {
+	if len(body) <= riskBirdJobBodyLimit {
+		return string(body)
+	}
+	end := riskBirdJobBodyLimit
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+	return string(body[:end])
+}
+
+// GetJobRecord 根据ID获取调用记录
+func (s *RiskBirdJobService) GetJobRecord(ID uint) (record system.RiskBirdJobRecord, err error) {
+	err = global.GVA_DB.Where("id = ?", ID).First(&record).Error
+	return
+}
+
+// GetJobRecordList 分页获取调用记录
+func (s *RiskBirdJobService) GetJobRecordList(info systemReq.RiskBirdJobRecordSearch) (list []system.RiskBirdJobRecord, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdJobRecord{})
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.JobName != "" {
+		db = db.Where("job_name = ?", info.JobName)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
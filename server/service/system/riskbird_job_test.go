@@ -0,0 +1,34 @@
+package system
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateRiskBirdJobBody(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		body string
+		want int
+	}{
+		{"未超出长度", "ok", 2},
+		{"恰好等于上限", strings.Repeat("a", riskBirdJobBodyLimit), riskBirdJobBodyLimit},
+		{"ASCII 超出上限", strings.Repeat("a", riskBirdJobBodyLimit+10), riskBirdJobBodyLimit},
+		// 65535 = 3*21845，汉字在上限处正好完整，前面加一个字节后最后一个汉字被截断
+		{"多字节字符跨越上限", "a" + strings.Repeat("积", riskBirdJobBodyLimit/3), riskBirdJobBodyLimit - 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateRiskBirdJobBody([]byte(tt.body))
+			if len(got) != tt.want {
+				t.Errorf("truncateRiskBirdJobBody() len = %d, want %d", len(got), tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Error("truncateRiskBirdJobBody() returned invalid UTF-8")
+			}
+			if !strings.HasPrefix(tt.body, got) {
+				t.Error("truncateRiskBirdJobBody() is not a prefix of body")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package system
+
+import (
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RiskBird 流程指标，接口和数据库指标见 utils/request/riskbird_metrics.go，标签中不能出现手机号等用户数据
+var (
+	riskBirdFlowRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "riskbird_flow_runs_total",
+		Help: "RiskBird 流程执行次数，status 为 success/failed",
+	}, []string{"flow", "env", "status"})
+	riskBirdFlowDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "riskbird_flow_duration_seconds",
+		Help:    "RiskBird 流程执行耗时",
+		Buckets: []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"flow", "env"})
+	riskBirdFlowSteps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "riskbird_flow_steps_total",
+		Help: "RiskBird 流程步骤执行次数，result 为 succeeded/failed/compensated",
+	}, []string{"flow", "env", "step", "result"})
+	riskBirdFlowStepDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "riskbird_flow_step_duration_seconds",
+		Help:    "RiskBird 流程步骤耗时",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"flow", "env", "step"})
+)
+
+func observeRiskBirdFlowStep(flow, env, step, result string, elapsed time.Duration) {
+	riskBirdFlowSteps.WithLabelValues(flow, env, step, result).Inc()
+	riskBirdFlowStepDuration.WithLabelValues(flow, env, step).Observe(elapsed.Seconds())
+}
+
+func observeRiskBirdFlowRun(flow, env string, elapsed time.Duration, err error) {
+	status := system.RiskBirdFlowRunSuccess
+	if err != nil {
+		status = system.RiskBirdFlowRunFailed
+	}
+	riskBirdFlowRuns.WithLabelValues(flow, env, status).Inc()
+	riskBirdFlowDuration.WithLabelValues(flow, env).Observe(elapsed.Seconds())
+}
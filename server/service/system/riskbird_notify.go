@@ -0,0 +1,380 @@
+package system
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	emailService "github.com/flipped-aurora/gin-vue-admin/server/plugin/email/service"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RiskBirdNotifyService struct{}
+
+var RiskBirdNotifyServiceApp = new(RiskBirdNotifyService)
+
+// webhook 默认超时时间
+const riskBirdWebhookTimeout = 5 * time.Second
+
+// GetPreference 获取用户的通知偏好，未设置时返回不接收任何通知的默认偏好
+func (s *RiskBirdNotifyService) GetPreference(userID uint) (pref system.RiskBirdNotifyPreference, err error) {
+	err = global.GVA_DB.Where("user_id = ?", userID).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return system.RiskBirdNotifyPreference{UserID: userID, Events: []string{}}, nil
+	}
+	return pref, err
+}
+
+// SetPreference 设置用户的通知偏好
+func (s *RiskBirdNotifyService) SetPreference(userID uint, req systemReq.SetRiskBirdNotifyPreference) (pref system.RiskBirdNotifyPreference, err error) {
+	for _, event := range req.Events {
+		if !slices.Contains(system.RiskBirdNotifyEvents, event) {
+			return pref, fmt.Errorf("未知的通知事件: %s", event)
+		}
+	}
+	if pref, err = s.GetPreference(userID); err != nil {
+		return pref, err
+	}
+	pref.Events, pref.EmailEnabled, pref.Email, pref.WebhookEnabled = req.Events, req.EmailEnabled, req.Email, req.WebhookEnabled
+	if pref.ID == 0 {
+		err = global.GVA_DB.Create(&pref).Error
+		return pref, err
+	}
+	err = global.GVA_DB.Select("events", "email_enabled", "email", "webhook_enabled").Updates(&pref).Error
+	return pref, err
+}
+
+// SendTestNotification 按用户当前的偏好立即发送一条测试通知，不检查订阅的事件，用于验证邮箱和 webhook 配置
+func (s *RiskBirdNotifyService) SendTestNotification(userID uint) error {
+	pref, err := s.GetPreference(userID)
+	if err != nil {
+		return err
+	}
+	if !pref.EmailEnabled && !pref.WebhookEnabled {
+		return errors.New("未开启任何通知渠道")
+	}
+	var user system.SysUser
+	if err = global.GVA_DB.Select("id", "username", "email").Where("id = ?", userID).First(&user).Error; err != nil {
+		return err
+	}
+	return sendRiskBirdNotification(pref, user, system.RiskBirdNotification{
+		Event:   "test",
+		Title:   "RiskBird测试通知",
+		Summary: []string{"收到这条消息说明通知配置正确"},
+		Link:    riskBirdNotifyLink("", 0),
+		Time:    time.Now(),
+	})
+}
+
+// notifyRiskBird 在后台发送通知。ownerID 不为0时只通知该用户，否则通知全部订阅了该事件、且角色可以查看 envs 中全部环境的用户。
+// 邮件逐个接收人发送，webhook 地址是全局配置，只要有接收人开启就推送一次；发送失败只记录日志
+func notifyRiskBird(ownerID uint, envs []string, n system.RiskBirdNotification) {
+	if global.GVA_DB == nil {
+		return
+	}
+	n.Time = time.Now()
+	go func() {
+		prefs, users, err := riskBirdNotifyRecipients(ownerID, envs, n.Event)
+		if err != nil {
+			global.GVA_LOG.Error("查询通知接收人失败", zap.String("event", n.Event), zap.Error(err))
+			return
+		}
+		webhook := false
+		for i, pref := range prefs {
+			webhook = webhook || pref.WebhookEnabled
+			if !pref.EmailEnabled {
+				continue
+			}
+			if err := sendRiskBirdEmail(pref, users[i], n); err != nil {
+				global.GVA_LOG.Error("发送RiskBird通知邮件失败",
+					zap.String("event", n.Event),
+					zap.Uint("userId", pref.UserID),
+					zap.Error(err))
+			}
+		}
+		if !webhook {
+			return
+		}
+		if ownerID != 0 {
+			n.UserID, n.Username = users[0].ID, users[0].Username
+		}
+		if err := postRiskBirdWebhook(n); err != nil {
+			global.GVA_LOG.Error("推送RiskBird通知webhook失败", zap.String("event", n.Event), zap.Error(err))
+		}
+	}()
+}
+
+// riskBirdNotifyRecipients 查询订阅了事件的接收人，返回的偏好和用户一一对应
+func riskBirdNotifyRecipients(ownerID uint, envs []string, event string) (prefs []system.RiskBirdNotifyPreference, users []system.SysUser, err error) {
+	db := global.GVA_DB.Where("email_enabled = ? OR webhook_enabled = ?", true, true)
+	if ownerID != 0 {
+		db = db.Where("user_id = ?", ownerID)
+	}
+	var all []system.RiskBirdNotifyPreference
+	if err = db.Find(&all).Error; err != nil {
+		return nil, nil, err
+	}
+	all = slices.DeleteFunc(all, func(p system.RiskBirdNotifyPreference) bool {
+		return !slices.Contains(p.Events, event)
+	})
+	if len(all) == 0 {
+		return nil, nil, nil
+	}
+	userIDs := make([]uint, 0, len(all))
+	for _, pref := range all {
+		userIDs = append(userIDs, pref.UserID)
+	}
+	var list []system.SysUser
+	if err = global.GVA_DB.Select("id", "username", "email", "authority_id").Where("id IN ?", userIDs).Find(&list).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, pref := range all {
+		i := slices.IndexFunc(list, func(u system.SysUser) bool { return u.ID == pref.UserID })
+		if i < 0 {
+			continue
+		}
+		if ownerID == 0 && !riskBirdNotifyCanView(list[i].AuthorityId, envs) {
+			continue
+		}
+		prefs, users = append(prefs, pref), append(users, list[i])
+	}
+	return prefs, users, nil
+}
+
+// riskBirdNotifyCanView 广播通知只发给角色可以查看涉及的全部环境的用户
+func riskBirdNotifyCanView(authorityID uint, envs []string) bool {
+	allowed, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		global.GVA_LOG.Error("查询通知接收人环境权限失败", zap.Uint("authorityId", authorityID), zap.Error(err))
+		return false
+	}
+	for _, env := range envs {
+		if !slices.Contains(allowed, env) {
+			return false
+		}
+	}
+	return true
+}
+
+// sendRiskBirdNotification 按偏好通过邮件和 webhook 发送给一个接收人
+func sendRiskBirdNotification(pref system.RiskBirdNotifyPreference, user system.SysUser, n system.RiskBirdNotification) error {
+	var errs []error
+	if pref.EmailEnabled {
+		if err := sendRiskBirdEmail(pref, user, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if pref.WebhookEnabled {
+		n.UserID, n.Username = user.ID, user.Username
+		if err := postRiskBirdWebhook(n); err != nil {
+			errs = append(errs, fmt.Errorf("推送webhook失败: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendRiskBirdEmail 发送通知邮件，未单独设置收件邮箱时使用用户邮箱
+func sendRiskBirdEmail(pref system.RiskBirdNotifyPreference, user system.SysUser, n system.RiskBirdNotification) error {
+	n.UserID, n.Username = user.ID, user.Username
+	to := pref.Email
+	if to == "" {
+		to = user.Email
+	}
+	if to == "" {
+		return errors.New("未设置收件邮箱")
+	}
+	if err := (&emailService.EmailService{}).SendEmail(to, n.Title, riskBirdNotifyEmailBody(n)); err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	return nil
+}
+
+func riskBirdNotifyEmailBody(n system.RiskBirdNotification) string {
+	var b strings.Builder
+	b.WriteString("<h3>" + html.EscapeString(n.Title) + "</h3><ul>")
+	for _, line := range n.Summary {
+		b.WriteString("<li>" + html.EscapeString(line) + "</li>")
+	}
+	b.WriteString("</ul>")
+	if n.Link != "" {
+		b.WriteString(`<p><a href="` + html.EscapeString(n.Link) + `">查看详情</a></p>`)
+	}
+	return b.String()
+}
+
+// postRiskBirdWebhook 以 JSON 推送通知，配置了密钥时附带请求体的 HMAC-SHA256 签名
+func postRiskBirdWebhook(n system.RiskBirdNotification) error {
+	cfg := global.GVA_CONFIG.RiskBird.Notify
+	if cfg.WebhookURL == "" {
+		return errors.New("未配置 webhook 地址")
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-RiskBird-Event", n.Event)
+	if cfg.WebhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(cfg.WebhookSecret))
+		mac.Write(body)
+		req.Header.Set("X-RiskBird-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	timeout := riskBirdWebhookTimeout
+	if cfg.WebhookTimeout > 0 {
+		timeout = time.Duration(cfg.WebhookTimeout) * time.Second
+	}
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// riskBirdNotifyLink 生成管理后台详情页链接，未配置后台地址时返回空
+func riskBirdNotifyLink(page string, id uint) string {
+	site := strings.TrimRight(global.GVA_CONFIG.RiskBird.Notify.SiteURL, "/")
+	if site == "" || page == "" {
+		return site
+	}
+	if id == 0 {
+		return fmt.Sprintf("%s/#/layout/%s", site, page)
+	}
+	return fmt.Sprintf("%s/#/layout/%s?id=%d", site, page, id)
+}
+
+// notifyRiskBirdFlowRun 异步流程结束后通知发起人
+func notifyRiskBirdFlowRun(run system.RiskBirdFlowRun) {
+	if run.UserID == 0 {
+		return
+	}
+	event, result := system.RiskBirdNotifyFlowSucceeded, "成功"
+	if run.Status != system.RiskBirdFlowRunSuccess {
+		event, result = system.RiskBirdNotifyFlowFailed, "失败"
+	}
+	summary := []string{
+		"环境: " + run.Env,
+		"手机号: " + run.Phone,
+		"耗时: " + run.FinishedAt.Sub(run.StartedAt).Round(time.Second).String(),
+	}
+	if run.Error != "" {
+		summary = append(summary, "错误: "+run.Error)
+	}
+	run.Events = nil
+	notifyRiskBird(run.UserID, nil, system.RiskBirdNotification{
+		Event:   event,
+		Title:   fmt.Sprintf("RiskBird%s流程执行%s", riskBirdFlowName(run.Flow), result),
+		Summary: summary,
+		Link:    riskBirdNotifyLink("riskbirdFlowRun", run.ID),
+		Data:    run,
+	})
+}
+
+// notifyRiskBirdGeneratorJob 造数任务执行结束后通知创建人，手动停止的任务不通知
+func notifyRiskBirdGeneratorJob(job system.RiskBirdGeneratorJob) {
+	if job.UserID == 0 {
+		return
+	}
+	var event, result string
+	switch job.Status {
+	case system.RiskBirdGeneratorSuccess:
+		event, result = system.RiskBirdNotifyGeneratorSucceeded, "全部完成"
+	case system.RiskBirdGeneratorPartial:
+		event, result = system.RiskBirdNotifyGeneratorFailed, "部分失败"
+	default:
+		return
+	}
+	summary := []string{
+		"环境: " + job.Env,
+		fmt.Sprintf("账号数: %d，成功 %d，失败 %d", job.Count, job.Succeeded, job.Failed),
+		"标签: " + job.Tag,
+	}
+	if job.Error != "" {
+		summary = append(summary, "错误: "+job.Error)
+	}
+	notifyRiskBird(job.UserID, nil, system.RiskBirdNotification{
+		Event:   event,
+		Title:   fmt.Sprintf("RiskBird造数任务 %s %s", job.Name, result),
+		Summary: summary,
+		Link:    riskBirdNotifyLink("riskbirdGenerator", job.ID),
+		Data:    job,
+	})
+}
+
+// notifyRiskBirdResetRun 重置计划执行结束后通知订阅的用户
+func notifyRiskBirdResetRun(schedule system.RiskBirdResetSchedule, run system.RiskBirdResetRun) {
+	event := system.RiskBirdNotifyResetSucceeded
+	if run.Status != system.RiskBirdResetStatusSuccess {
+		event = system.RiskBirdNotifyResetFailed
+	}
+	summary := []string{"触发方式: " + run.Trigger, "执行结果: " + run.Status}
+	var envs []string
+	for _, account := range schedule.Accounts {
+		if !slices.Contains(envs, account.Env) {
+			envs = append(envs, account.Env)
+		}
+	}
+	for _, result := range run.Results {
+		if result.BalanceError != "" {
+			summary = append(summary, fmt.Sprintf("%s 余额重置失败: %s", result.Phone, result.BalanceError))
+		}
+		if result.PointError != "" {
+			summary = append(summary, fmt.Sprintf("%s 积分重置失败: %s", result.Phone, result.PointError))
+		}
+	}
+	notifyRiskBird(0, envs, system.RiskBirdNotification{
+		Event:   event,
+		Title:   fmt.Sprintf("RiskBird重置计划 %s 执行结果: %s", schedule.Name, run.Status),
+		Summary: summary,
+		Link:    riskBirdNotifyLink("riskbirdResetSchedule", schedule.ID),
+		Data:    run,
+	})
+}
+
+// notifyRiskBirdReconcileMismatch 定时核对发现不一致时按环境分别通知订阅的用户
+func notifyRiskBirdReconcileMismatch(list []system.RiskBirdReconciliation) {
+	var envs []string
+	summaries := map[string][]string{}
+	for _, result := range list {
+		if result.Matched {
+			continue
+		}
+		line := fmt.Sprintf("%s %s: 余额 接口%s/数据库%s，积分 接口%d/数据库%d",
+			result.Env, result.Phone, result.APIBalance, result.DBBalance, result.APIPoints, result.DBPoints)
+		if result.Error != "" {
+			line = fmt.Sprintf("%s %s: 核对失败 %s", result.Env, result.Phone, result.Error)
+		}
+		if _, ok := summaries[result.Env]; !ok {
+			envs = append(envs, result.Env)
+		}
+		summaries[result.Env] = append(summaries[result.Env], line)
+	}
+	for _, env := range envs {
+		notifyRiskBird(0, []string{env}, system.RiskBirdNotification{
+			Event:   system.RiskBirdNotifyReconcileMismatch,
+			Title:   fmt.Sprintf("RiskBird环境 %s 账务核对发现%d个账号不一致", env, len(summaries[env])),
+			Summary: summaries[env],
+			Link:    riskBirdNotifyLink("riskbirdReconciliation", 0),
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package system
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPostRiskBirdWebhook(t *testing.T) {
+	var got system.RiskBirdNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		if sig := r.Header.Get("X-RiskBird-Signature"); sig != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+
+	global.GVA_CONFIG.RiskBird.Notify.WebhookURL = server.URL
+	global.GVA_CONFIG.RiskBird.Notify.WebhookSecret = "secret"
+	global.GVA_CONFIG.RiskBird.Notify.SiteURL = "https://admin.example.com/"
+	defer func() { global.GVA_CONFIG.RiskBird.Notify = config.RiskBirdNotify{} }()
+
+	n := system.RiskBirdNotification{
+		Event:   system.RiskBirdNotifyFlowFailed,
+		Title:   "RiskBird修改余额流程执行失败",
+		Summary: []string{"环境: default"},
+		Link:    riskBirdNotifyLink("riskbirdFlowRun", 3),
+	}
+	if err := postRiskBirdWebhook(n); err != nil {
+		t.Fatal(err)
+	}
+	if got.Event != n.Event || got.Link != "https://admin.example.com/#/layout/riskbirdFlowRun?id=3" {
+		t.Errorf("webhook got = %+v", got)
+	}
+
+	global.GVA_CONFIG.RiskBird.Notify.WebhookSecret = "wrong"
+	if err := postRiskBirdWebhook(n); err == nil {
+		t.Error("postRiskBirdWebhook() want error for rejected signature")
+	}
+}
+
+func TestRiskBirdNotifyRecipients(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&system.SysUser{}, &system.RiskBirdNotifyPreference{}, &system.RiskBirdAuthorityScope{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldEnvs := global.GVA_DB, global.GVA_CONFIG.RiskBird.Envs
+	global.GVA_DB = db
+	global.GVA_CONFIG.RiskBird.Envs = []config.RiskBirdEnv{{Name: "staging"}}
+	defer func() { global.GVA_DB, global.GVA_CONFIG.RiskBird.Envs = oldDB, oldEnvs }()
+
+	// 用户1的角色未配置环境权限，可以查看全部环境；用户2的角色只能查看默认环境
+	users := []system.SysUser{{Username: "all", AuthorityId: 1}, {Username: "default-only", AuthorityId: 2}}
+	if err = db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	scope := system.RiskBirdAuthorityScope{AuthorityId: 2, Env: config.RiskBirdDefaultEnv, Operations: []string{system.RiskBirdOperationReconcile}}
+	if err = db.Create(&scope).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range users {
+		pref := system.RiskBirdNotifyPreference{UserID: user.ID, Events: []string{system.RiskBirdNotifyReconcileMismatch}, WebhookEnabled: true}
+		if err = db.Create(&pref).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		ownerID uint
+		envs    []string
+		event   string
+		want    []uint
+	}{
+		{"默认环境广播给两人", 0, []string{config.RiskBirdDefaultEnv}, system.RiskBirdNotifyReconcileMismatch, []uint{users[0].ID, users[1].ID}},
+		{"无权查看的环境不通知", 0, []string{"staging"}, system.RiskBirdNotifyReconcileMismatch, []uint{users[0].ID}},
+		{"涉及多个环境时需要全部可见", 0, []string{config.RiskBirdDefaultEnv, "staging"}, system.RiskBirdNotifyReconcileMismatch, []uint{users[0].ID}},
+		{"发起人通知不按环境过滤", users[1].ID, []string{"staging"}, system.RiskBirdNotifyReconcileMismatch, []uint{users[1].ID}},
+		{"未订阅的事件", 0, nil, system.RiskBirdNotifyFlowFailed, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs, got, err := riskBirdNotifyRecipients(tt.ownerID, tt.envs, tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []uint
+			for i, user := range got {
+				if prefs[i].UserID != user.ID {
+					t.Errorf("pref %d belongs to user %d, want %d", i, prefs[i].UserID, user.ID)
+				}
+				ids = append(ids, user.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("riskBirdNotifyRecipients() got = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
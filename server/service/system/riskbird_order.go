@@ -0,0 +1,60 @@
+package system
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+// riskBirdDuplicateWindow 重复检测时查询的订单时间范围，需覆盖管理后台与 RiskBird 数据库之间的时钟误差
+const riskBirdDuplicateWindow = 10 * time.Minute
+
+// createRiskBirdOrder 创建订单或预订单。创建接口不自动重试：请求超时或返回5xx时 RiskBird 可能已经生成了订单，
+// 此时对比调用前后用户的订单，已生成则沿用新订单号，未生成才重试一次
+func createRiskBirdOrder(db *sql.DB, table request.OrderTable, userID int64, create func() (string, error)) (string, error) {
+	if userID == 0 {
+		return create()
+	}
+	since := time.Now().Add(-riskBirdDuplicateWindow)
+	before, snapshotErr := request.FindOrderNosSince(db, table, userID, since)
+	orderNo, err := create()
+	if !errors.Is(err, request.ErrOutcomeUnknown) {
+		return orderNo, err
+	}
+	if snapshotErr != nil {
+		global.GVA_LOG.Error("查询订单失败，无法进行重复检测", zap.String("table", table.Table), zap.Error(snapshotErr))
+		return "", err
+	}
+	after, findErr := request.FindOrderNosSince(db, table, userID, since)
+	if findErr != nil {
+		global.GVA_LOG.Error("查询订单失败，无法进行重复检测", zap.String("table", table.Table), zap.Error(findErr))
+		return "", err
+	}
+
+	existing := make(map[string]bool, len(before))
+	for _, no := range before {
+		existing[no] = true
+	}
+	var created []string
+	for _, no := range after {
+		if !existing[no] {
+			created = append(created, no)
+		}
+	}
+	switch len(created) {
+	case 0:
+		global.GVA_LOG.Warn("创建订单结果未知且未生成订单，重试一次", zap.String("table", table.Table), zap.Error(err))
+		return create()
+	case 1:
+		global.GVA_LOG.Warn("创建订单结果未知，已检测到生成的订单", zap.String("table", table.Table), zap.String("orderNo", created[0]))
+		return created[0], nil
+	default:
+		return "", fmt.Errorf("%w，检测到多个新订单: %s", err, strings.Join(created, ", "))
+	}
+}
@@ -0,0 +1,278 @@
+package system
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+// riskBirdFlowVars 流程步骤之间传递的数据，流程失败时随断点保存，续跑时恢复
+type riskBirdFlowVars struct {
+	UserID             int64             `json:"userId"`             // RiskBird 用户ID
+	Phone              string            `json:"phone"`              // 用户手机号
+	Balance            common.Money      `json:"balance"`            // 流程开始时的余额
+	Points             int64             `json:"points"`             // 流程开始时的可用积分
+	Orders             map[string]string `json:"orders"`             // 已创建的订单号，键为订单类型
+	PointAcquisitionID int64             `json:"pointAcquisitionId"` // 待审核的积分获取记录ID
+}
+
+// riskBirdFlowState 流程执行时的上下文
+type riskBirdFlowState struct {
+	Flow          string
+	Env           config.RiskBirdEnv
+	DB            *sql.DB
+	Client        *request.RiskBirdAPIClient
+	OrderTable    request.OrderTable
+	PreOrderTable request.OrderTable
+	Login         riskBirdLogin
+
+	Token      string // 用户登录后的token，不随断点保存
+	AdminToken string // 管理员token，不随断点保存
+	Vars       riskBirdFlowVars
+
+	pricingLock *sync.Mutex // 修改报告价格到恢复价格期间持有的环境锁
+}
+
+// 修改报告价格的流程和恢复配置基线会修改共享的产品配置，同一环境内必须串行执行
+var riskBirdPricingLocks sync.Map
+
+func riskBirdPricingLock(env string) *sync.Mutex {
+	lock, _ := riskBirdPricingLocks.LoadOrStore(env, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// lockPricing 修改报告价格前获取环境锁，步骤重试或续跑时不重复获取
+func (st *riskBirdFlowState) lockPricing() {
+	if st.pricingLock != nil {
+		return
+	}
+	st.pricingLock = riskBirdPricingLock(st.Env.Name)
+	st.pricingLock.Lock()
+}
+
+// unlockPricing 恢复报告价格或流程结束时释放环境锁
+func (st *riskBirdFlowState) unlockPricing() {
+	if st.pricingLock == nil {
+		return
+	}
+	st.pricingLock.Unlock()
+	st.pricingLock = nil
+}
+
+// newRiskBirdFlowState 连接环境的数据库和接口，返回的 close 用于释放数据库连接
+func newRiskBirdFlowState(flow, envName string, login riskBirdLogin) (st *riskBirdFlowState, close func(), err error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := newRiskBirdDB(env)
+	if err != nil {
+		global.GVA_LOG.Error("连接RiskBird数据库失败", zap.Error(err))
+		return nil, nil, errors.New("连接数据库失败")
+	}
+	orderTable, preOrderTable := riskBirdOrderTables(global.GVA_CONFIG.RiskBird.OrderTables)
+	st = &riskBirdFlowState{
+		Flow:          flow,
+		Env:           env,
+		DB:            db,
+		Client:        newRiskBirdClient(env),
+		OrderTable:    orderTable,
+		PreOrderTable: preOrderTable,
+		Login:         login,
+		Vars:          riskBirdFlowVars{Phone: login.Phone, Orders: map[string]string{}},
+	}
+	return st, func() { db.Close() }, nil
+}
+
+// riskBirdStepUnit 流程中可复用的步骤
+type riskBirdStepUnit struct {
+	Name     string
+	ReadOnly bool                // 只读步骤不修改 RiskBird 数据，预演时同样执行
+	Rerun    bool                // 续跑时断点之前的该步骤同样重新执行，用于登录等不保存结果的步骤
+	Retry    request.RetryPolicy // 步骤失败时的重试策略，MaxAttempts 小于2时不重试；结果未知的错误不重试
+	Error    string              // 失败时返回给调用方的信息，为空时返回原始错误
+
+	When     func(st *riskBirdFlowState) bool                                                          // 为 nil 或返回 true 时执行
+	Input    func(st *riskBirdFlowState) map[string]interface{}                                        // 步骤参数，用于进度事件和预演计划
+	Run      func(st *riskBirdFlowState, input map[string]interface{}) (map[string]interface{}, error) // 执行步骤，返回步骤结果
+	Simulate func(st *riskBirdFlowState, input map[string]interface{}) map[string]interface{}          // 预演时代替 Run，更新流程数据并返回预计结果
+	Undo     func(st *riskBirdFlowState) (map[string]interface{}, error)                               // 步骤成功后流程失败时执行的补偿
+	UndoName string                                                                                    // 补偿步骤名称
+	Settles  string                                                                                    // 执行成功后不再需要补偿的步骤
+}
+
+// riskBirdIdempotentRetry 数据库更新等幂等步骤的重试策略
+var riskBirdIdempotentRetry = request.RetryPolicy{MaxAttempts: 2, Backoff: 500 * time.Millisecond}
+
+// riskBirdPipeline 由步骤组成的流程，步骤按顺序执行，失败时倒序执行已成功步骤的补偿
+type riskBirdPipeline struct {
+	Flow  string
+	Steps []riskBirdStepUnit
+}
+
+// riskBirdPipelineOptions 流程执行选项
+type riskBirdPipelineOptions struct {
+	DryRun bool                           // 预演，只执行只读步骤，其余步骤记录为计划
+	Resume *system.RiskBirdFlowCheckpoint // 从断点继续执行
+}
+
+// riskBirdPipelineResult 流程执行结果
+type riskBirdPipelineResult struct {
+	Plan       []systemRes.RiskBirdFlowPlanStep // 各步骤的执行情况
+	Checkpoint *system.RiskBirdFlowCheckpoint   // 失败时的断点
+	Vars       riskBirdFlowVars                 // 执行结束时的流程数据
+}
+
+// Run 执行流程，progress 不为 nil 时记录每个步骤的进度
+func (p riskBirdPipeline) Run(st *riskBirdFlowState, progress *riskBirdProgress, opts riskBirdPipelineOptions) (result riskBirdPipelineResult, err error) {
+	resumeAt := 0
+	var redo []string
+	if opts.Resume != nil {
+		if resumeAt = slices.IndexFunc(p.Steps, func(u riskBirdStepUnit) bool { return u.Name == opts.Resume.Step }); resumeAt < 0 {
+			return result, fmt.Errorf("断点步骤 %s 不在流程中", opts.Resume.Step)
+		}
+		if err = json.Unmarshal(opts.Resume.Vars, &st.Vars); err != nil {
+			return result, fmt.Errorf("读取断点数据失败: %w", err)
+		}
+		if st.Vars.Orders == nil {
+			st.Vars.Orders = map[string]string{}
+		}
+		redo = slices.Clone(opts.Resume.Redo)
+	}
+
+	var pending []int // 已成功且尚未解除补偿的步骤
+	for i, unit := range p.Steps {
+		if i < resumeAt && !unit.Rerun && !slices.Contains(redo, unit.Name) {
+			progress.Skip(unit.Name, "断点前已完成")
+			result.Plan = append(result.Plan, systemRes.RiskBirdFlowPlanStep{Step: unit.Name, Status: system.RiskBirdStepSkipped, ReadOnly: unit.ReadOnly})
+			continue
+		}
+		if unit.When != nil && !unit.When(st) {
+			continue
+		}
+		var input map[string]interface{}
+		if unit.Input != nil {
+			input = unit.Input(st)
+		}
+
+		if opts.DryRun && !unit.ReadOnly {
+			var output map[string]interface{}
+			if unit.Simulate != nil {
+				output = unit.Simulate(st, input)
+			}
+			progress.Plan(unit.Name, input, output)
+			result.Plan = append(result.Plan, systemRes.RiskBirdFlowPlanStep{Step: unit.Name, Status: system.RiskBirdStepPlanned, Input: input, Output: output})
+			continue
+		}
+
+		output, stepErr := runRiskBirdStep(st, progress, unit, input)
+		if stepErr != nil {
+			redo = append(redo, p.compensate(st, progress, pending)...)
+			result.Vars = st.Vars
+			if !opts.DryRun {
+				result.Checkpoint = riskBirdCheckpoint(unit.Name, redo, st.Vars)
+			}
+			if unit.Error == "" {
+				global.GVA_LOG.Error("RiskBird流程步骤失败", zap.String("flow", p.Flow), zap.String("step", unit.Name), zap.Error(stepErr))
+				return result, stepErr
+			}
+			global.GVA_LOG.Error(unit.Error, zap.String("flow", p.Flow), zap.Error(stepErr))
+			return result, errors.New(unit.Error)
+		}
+		result.Plan = append(result.Plan, systemRes.RiskBirdFlowPlanStep{Step: unit.Name, Status: system.RiskBirdStepSucceeded, ReadOnly: unit.ReadOnly, Input: input, Output: output})
+
+		// 断点步骤之前重新执行的步骤已从待重做列表中完成
+		redo = slices.DeleteFunc(redo, func(name string) bool { return name == unit.Name })
+		if unit.Undo != nil {
+			pending = append(pending, i)
+		}
+		if unit.Settles != "" {
+			pending = slices.DeleteFunc(pending, func(j int) bool { return p.Steps[j].Name == unit.Settles })
+		}
+	}
+	result.Vars = st.Vars
+	return result, nil
+}
+
+// compensate 倒序执行补偿，返回已补偿、续跑时需要重新执行的步骤
+func (p riskBirdPipeline) compensate(st *riskBirdFlowState, progress *riskBirdProgress, pending []int) (redo []string) {
+	for i := len(pending) - 1; i >= 0; i-- {
+		unit := p.Steps[pending[i]]
+		step := progress.Compensate(unit.UndoName)
+		output, err := unit.Undo(st)
+		step.Done(output, err)
+		if err != nil {
+			global.GVA_LOG.Error("RiskBird流程补偿失败", zap.String("flow", p.Flow), zap.String("step", unit.UndoName), zap.Error(err))
+		}
+		redo = append(redo, unit.Name)
+	}
+	return redo
+}
+
+// runRiskBirdStep 执行单个步骤，按步骤的重试策略重试
+func runRiskBirdStep(st *riskBirdFlowState, progress *riskBirdProgress, unit riskBirdStepUnit, input map[string]interface{}) (output map[string]interface{}, err error) {
+	for attempt := 1; ; attempt++ {
+		step := progress.Step(unit.Name, input)
+		output, err = unit.Run(st, input)
+		step.Done(output, err)
+		if err == nil || attempt >= unit.Retry.MaxAttempts || errors.Is(err, request.ErrOutcomeUnknown) {
+			return output, err
+		}
+		wait := unit.Retry.Delay(attempt)
+		global.GVA_LOG.Warn("RiskBird流程步骤失败，准备重试",
+			zap.String("flow", st.Flow),
+			zap.String("step", unit.Name),
+			zap.Int("attempt", attempt),
+			zap.Duration("wait", wait),
+			zap.Error(err))
+		time.Sleep(wait)
+	}
+}
+
+// riskBirdCheckpoint 生成断点，流程数据无法序列化时不生成
+func riskBirdCheckpoint(step string, redo []string, vars riskBirdFlowVars) *system.RiskBirdFlowCheckpoint {
+	data, err := json.Marshal(vars)
+	if err != nil {
+		global.GVA_LOG.Error("保存流程断点失败", zap.String("step", step), zap.Error(err))
+		return nil
+	}
+	return &system.RiskBirdFlowCheckpoint{Step: step, Redo: redo, Vars: data}
+}
+
+// runRiskBirdFlow 连接环境并执行流程
+func runRiskBirdFlow(pipeline riskBirdPipeline, envName string, login riskBirdLogin, progress *riskBirdProgress, opts riskBirdPipelineOptions) (result riskBirdPipelineResult, err error) {
+	st, closeDB, err := newRiskBirdFlowState(pipeline.Flow, envName, login)
+	if err != nil {
+		return result, err
+	}
+	defer closeDB()
+	// 流程在恢复价格之前失败且补偿未执行时，同样释放环境锁
+	defer st.unlockPricing()
+	return pipeline.Run(st, progress, opts)
+}
+
+// riskBirdFlowPlan 将预演结果转换为接口返回的计划，步骤参数和结果按进度事件的规则脱敏
+func riskBirdFlowPlan(flow, envName string, result riskBirdPipelineResult) systemRes.RiskBirdFlowPlan {
+	if envName == "" {
+		envName = config.RiskBirdDefaultEnv
+	}
+	plan := systemRes.RiskBirdFlowPlan{Flow: flow, Env: envName, Phone: result.Vars.Phone, UserID: result.Vars.UserID}
+	for _, step := range result.Plan {
+		step.Input, _ = sanitizeRiskBirdPayload(step.Input).(map[string]interface{})
+		step.Output, _ = sanitizeRiskBirdPayload(step.Output).(map[string]interface{})
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan
+}
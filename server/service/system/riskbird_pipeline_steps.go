@@ -0,0 +1,394 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+)
+
+// riskBirdReportPrice 企业信用报告导出默认价格
+var riskBirdReportPrice = common.Yuan(5)
+
+// riskBirdDryRunOrderNo 预演时代替尚未创建的订单号
+const riskBirdDryRunOrderNo = "<预演订单号>"
+
+// riskBirdRowUpdate 描述步骤对 RiskBird 数据库的修改，用于进度事件和预演计划
+func riskBirdRowUpdate(table string, where, set map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"table": table, "where": where, "set": set}
+}
+
+// riskBirdLoginReadOnly 密码登录为只读步骤；验证码登录会发送短信并消耗验证码，代登录会记录审计，预演时都不执行
+func riskBirdLoginReadOnly(login riskBirdLogin) bool {
+	return !login.Impersonate && !login.SmsLogin
+}
+
+// riskBirdLoginStep 用户登录，代登录时按环境配置签发token。续跑时重新登录并校验用户与断点一致
+func riskBirdLoginStep(login riskBirdLogin) riskBirdStepUnit {
+	name := "用户登录"
+	switch {
+	case login.Impersonate:
+		name = "代登录"
+	case login.SmsLogin:
+		name = "验证码登录"
+	}
+	return riskBirdStepUnit{
+		Name:     name,
+		ReadOnly: riskBirdLoginReadOnly(login),
+		Rerun:    true,
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			if st.Login.Impersonate {
+				return map[string]interface{}{"phone": st.Login.Phone, "userId": st.Login.TargetUserID, "mode": st.Env.Impersonation.Mode}
+			}
+			if st.Login.SmsLogin {
+				return map[string]interface{}{"phone": st.Login.Phone, "mode": "sms"}
+			}
+			return map[string]interface{}{"phone": st.Login.Phone}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			session, err := loginRiskBirdUser(st.Env, st.DB, st.Client, st.Login)
+			if err != nil {
+				return nil, err
+			}
+			if st.Vars.UserID != 0 && st.Vars.UserID != session.UserID {
+				return nil, errors.New("登录用户与断点记录的用户不一致，无法续跑")
+			}
+			st.Token = session.Token
+			st.Vars.UserID, st.Vars.Phone = session.UserID, session.Phone
+			return map[string]interface{}{"userId": session.UserID}, nil
+		},
+		// 预演时不登录，从用户表查出将要登录的用户
+		Simulate: func(st *riskBirdFlowState, _ map[string]interface{}) map[string]interface{} {
+			userID, phone, err := request.FindUser(st.DB, riskBirdUserTable(st.Env.Impersonation), st.Login.TargetUserID, st.Login.Phone)
+			if err != nil {
+				return map[string]interface{}{"error": "查询RiskBird用户失败"}
+			}
+			st.Vars.UserID, st.Vars.Phone = userID, phone
+			return map[string]interface{}{"userId": userID}
+		},
+	}
+}
+
+// riskBirdBalanceLookupStep 获取当前余额，登录步骤预演时不执行的，按余额账户表预估
+func riskBirdBalanceLookupStep(login riskBirdLogin) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:     "获取当前余额",
+		ReadOnly: riskBirdLoginReadOnly(login),
+		Error:    "获取用户余额失败",
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			balance, err := st.Client.GetBalance(st.Token)
+			if err != nil {
+				return nil, err
+			}
+			st.Vars.Balance = balance
+			return map[string]interface{}{"balance": balance}, nil
+		},
+		Simulate: func(st *riskBirdFlowState, _ map[string]interface{}) map[string]interface{} {
+			_, balance, err := request.ListBalanceRows(st.DB, riskBirdBalanceTable(global.GVA_CONFIG.RiskBird.BalanceTable), st.Vars.UserID)
+			if err != nil {
+				return map[string]interface{}{"error": "查询余额账户失败"}
+			}
+			st.Vars.Balance = balance
+			return map[string]interface{}{"balance": balance}
+		},
+	}
+}
+
+// riskBirdPointLookupStep 获取当前可用积分，登录步骤预演时不执行的，按积分获取记录预估
+func riskBirdPointLookupStep(login riskBirdLogin) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:     "获取当前可用积分",
+		ReadOnly: riskBirdLoginReadOnly(login),
+		Error:    "获取用户积分信息失败",
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			points, err := st.Client.GetPointOverview(st.Token)
+			if err != nil {
+				return nil, err
+			}
+			st.Vars.Points = points
+			return map[string]interface{}{"points": points}, nil
+		},
+		Simulate: func(st *riskBirdFlowState, _ map[string]interface{}) map[string]interface{} {
+			_, points, err := request.ListAvailablePointAcquisitions(st.DB, st.Vars.UserID, time.Now())
+			if err != nil {
+				return map[string]interface{}{"error": "查询积分获取记录失败"}
+			}
+			st.Vars.Points = points
+			return map[string]interface{}{"points": points}
+		},
+	}
+}
+
+// riskBirdReportPriceStep 修改企业信用报告导出价格，流程失败时恢复默认价格。
+// 修改前获取环境锁，直到恢复价格后释放，避免同一环境的其他流程按错误的价格下单
+func riskBirdReportPriceStep(price func(st *riskBirdFlowState) common.Money) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:  "修改企业信用报告导出价格",
+		Retry: riskBirdIdempotentRetry,
+		Error: "修改产品配置失败",
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			return map[string]interface{}{
+				"price":  price(st),
+				"update": riskBirdRowUpdate("p_product_cfg", map[string]interface{}{"id": request.ReportPriceCfgID}, map[string]interface{}{"cfg_value": price(st)}),
+			}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			st.lockPricing()
+			return nil, request.UpdateProductCfg(st.DB, request.ReportPriceCfgID, price(st))
+		},
+		UndoName: "恢复企业信用报告导出价格",
+		Undo: func(st *riskBirdFlowState) (map[string]interface{}, error) {
+			err := request.UpdateProductCfg(st.DB, request.ReportPriceCfgID, riskBirdReportPrice)
+			if err == nil {
+				st.unlockPricing()
+			}
+			return map[string]interface{}{"price": riskBirdReportPrice}, err
+		},
+	}
+}
+
+// riskBirdRestoreReportPriceStep 恢复企业信用报告导出默认价格，成功后不再需要补偿
+func riskBirdRestoreReportPriceStep() riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:    "恢复企业信用报告导出价格",
+		Retry:   riskBirdIdempotentRetry,
+		Error:   "恢复产品配置失败",
+		Settles: "修改企业信用报告导出价格",
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			return map[string]interface{}{
+				"price":  riskBirdReportPrice,
+				"update": riskBirdRowUpdate("p_product_cfg", map[string]interface{}{"id": request.ReportPriceCfgID}, map[string]interface{}{"cfg_value": riskBirdReportPrice}),
+			}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			if err := request.UpdateProductCfg(st.DB, request.ReportPriceCfgID, riskBirdReportPrice); err != nil {
+				return nil, err
+			}
+			st.unlockPricing()
+			return nil, nil
+		},
+	}
+}
+
+// riskBirdRechargeProductStep 修改充值套餐的充值金额和赠送金额
+func riskBirdRechargeProductStep(amount, giftAmount common.Money) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:  "修改充值套餐",
+		Retry: riskBirdIdempotentRetry,
+		Error: "修改充值套餐失败",
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			return map[string]interface{}{
+				"amount":     amount,
+				"giftAmount": giftAmount,
+				"update": riskBirdRowUpdate("p_recharge_product", map[string]interface{}{"id": request.RechargeProductID},
+					map[string]interface{}{"amount": amount, "gift_amount": giftAmount}),
+			}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			return nil, request.UpdateRechargeProduct(st.DB, request.RechargeProductID, amount, giftAmount)
+		},
+	}
+}
+
+// riskBirdCreateOrderStep 创建订单或预订单并登记为合成订单，kind 决定调用的接口和订单表。
+// 创建接口结果未知时由 createRiskBirdOrder 做重复检测，步骤本身不重试
+func riskBirdCreateOrderStep(name, kind string, amount func(st *riskBirdFlowState) common.Money, payload func(st *riskBirdFlowState) map[string]interface{}) riskBirdStepUnit {
+	preOrder := kind == system.RiskBirdOrderKindReportPreOrder || kind == system.RiskBirdOrderKindRechargePreOrder
+	return riskBirdStepUnit{
+		Name:  name,
+		Error: name + "失败",
+		Input: payload,
+		Run: func(st *riskBirdFlowState, input map[string]interface{}) (map[string]interface{}, error) {
+			table, create := st.OrderTable, st.Client.CreateOrder
+			if preOrder {
+				table, create = st.PreOrderTable, st.Client.CreatePreOrder
+			}
+			orderNo, err := createRiskBirdOrder(st.DB, table, st.Vars.UserID, func() (string, error) {
+				return create(st.Token, input)
+			})
+			if err != nil {
+				return nil, err
+			}
+			st.Vars.Orders[kind] = orderNo
+			recordSyntheticOrder(system.RiskBirdSyntheticOrder{
+				Env: st.Env.Name, Phone: st.Vars.Phone, UserID: st.Vars.UserID, Flow: st.Flow,
+				Kind: kind, OrderNo: orderNo, Amount: amount(st),
+			})
+			return map[string]interface{}{"orderNo": orderNo}, nil
+		},
+		Simulate: func(st *riskBirdFlowState, _ map[string]interface{}) map[string]interface{} {
+			st.Vars.Orders[kind] = riskBirdDryRunOrderNo
+			return map[string]interface{}{"orderNo": riskBirdDryRunOrderNo}
+		},
+	}
+}
+
+// riskBirdUpdateOrderStep 将 kind 类型的订单状态更新为成功
+func riskBirdUpdateOrderStep(name, kind string) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name: name,
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			return map[string]interface{}{"orderNo": st.Vars.Orders[kind], "status": "success"}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			return nil, st.Client.UpdateOrder(st.Token, st.Vars.Orders[kind], "success")
+		},
+	}
+}
+
+// riskBirdReportPreOrderPayload 企业信用报告预订单参数
+func riskBirdReportPreOrderPayload(amount common.Money) map[string]interface{} {
+	return map[string]interface{}{
+		"productCode":     "paid_report",
+		"productNum":      "2",
+		"sendEmail":       "",
+		"totalAmount":     amount,
+		"transactionType": "C",
+		"tradeType":       "JSAPI",
+		"selectConditionData": map[string]interface{}{
+			"entName":     "乐视网信息技术（北京）股份有限公司",
+			"entid":       "7jShe5V5mqx",
+			"fileType":    "pdf,word",
+			"groupIdList": "9,2,5,6,7,8,",
+		},
+	}
+}
+
+// riskBirdReportOrderPayload 企业信用报告订单参数，payByBalance 为 true 时使用余额支付，否则在线支付以获得积分
+func riskBirdReportOrderPayload(amount common.Money, preOrderNo string, payByBalance bool) map[string]interface{} {
+	payload := map[string]interface{}{
+		"balanceAmount":     0,
+		"payAmount":         amount,
+		"payMethod":         "webpay",
+		"productNum":        "2",
+		"totalAmount":       amount,
+		"tradeType":         "JSAPI",
+		"unifiedPreOrderNo": preOrderNo,
+	}
+	if payByBalance {
+		payload["balanceAmount"] = amount
+		payload["payAmount"] = "0.00"
+		payload["payMethod"] = "balance"
+	}
+	return payload
+}
+
+// riskBirdExpirePointsSteps 将用户全部积分的失效时间改为昨天，再调用积分失效定时任务使其失效
+func riskBirdExpirePointsSteps(when func(st *riskBirdFlowState) bool) []riskBirdStepUnit {
+	return []riskBirdStepUnit{
+		{
+			Name:  "修改积分失效时间",
+			When:  when,
+			Retry: riskBirdIdempotentRetry,
+			Error: "修改积分失效时间失败",
+			Input: func(st *riskBirdFlowState) map[string]interface{} {
+				expireTime := time.Now().AddDate(0, 0, -1)
+				return map[string]interface{}{
+					"expireTime": expireTime,
+					"update": riskBirdRowUpdate("point_acquisition", map[string]interface{}{"user_id": st.Vars.UserID, "left_points >": 0},
+						map[string]interface{}{"expire_time": expireTime}),
+				}
+			},
+			Run: func(st *riskBirdFlowState, input map[string]interface{}) (map[string]interface{}, error) {
+				return nil, request.UpdatePointExpireTime(st.DB, st.Vars.UserID, input["expireTime"])
+			},
+		},
+		{
+			Name:  "调用积分失效定时任务",
+			When:  when,
+			Error: "调用积分失效定时任务接口失败",
+			Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+				return nil, st.Client.ExpirePoint(st.Token)
+			},
+		},
+	}
+}
+
+// riskBirdWaitStep 等待 RiskBird 异步处理完成
+func riskBirdWaitStep(name string, d time.Duration, when func(st *riskBirdFlowState) bool) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name: name,
+		When: when,
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			return map[string]interface{}{"seconds": int(d.Seconds())}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			time.Sleep(d)
+			return nil, nil
+		},
+	}
+}
+
+// riskBirdPointTimeStep 将用户最新的积分获取记录的发生时间改为昨天，使其可以参与日审核
+func riskBirdPointTimeStep(when func(st *riskBirdFlowState) bool) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:  "修改积分获取时间",
+		When:  when,
+		Retry: riskBirdIdempotentRetry,
+		Error: "修改积分获取时间失败",
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			pointTime := time.Now().AddDate(0, 0, -1)
+			return map[string]interface{}{
+				"pointTime": pointTime,
+				"update": riskBirdRowUpdate("point_acquisition", map[string]interface{}{"user_id": st.Vars.UserID, "id": "最新一条积分获取记录"},
+					map[string]interface{}{"point_time": pointTime}),
+			}
+		},
+		Run: func(st *riskBirdFlowState, input map[string]interface{}) (map[string]interface{}, error) {
+			id, err := request.GetLatestPointAcquisitionID(st.DB, st.Vars.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("查询积分获取记录失败: %w", err)
+			}
+			st.Vars.PointAcquisitionID = id
+			output := map[string]interface{}{"pointAcquisitionId": id}
+			return output, request.UpdatePointAcquisitionTime(st.DB, id, input["pointTime"])
+		},
+	}
+}
+
+// riskBirdPointAuditDayStep 调用积分审核日度定时任务
+func riskBirdPointAuditDayStep(when func(st *riskBirdFlowState) bool) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:  "调用积分日审核定时任务",
+		When:  when,
+		Error: "调用积分日审核定时任务接口失败",
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			return nil, st.Client.PointAuditDay(st.Token)
+		},
+	}
+}
+
+// riskBirdAdminLoginStep 登录后台管理系统，续跑时重新登录
+func riskBirdAdminLoginStep(when func(st *riskBirdFlowState) bool) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:     "管理员登录",
+		When:     when,
+		ReadOnly: true,
+		Rerun:    true,
+		Error:    "管理员登录失败",
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			token, err := riskBirdAdminLogin(st.Env, st.Client)
+			st.AdminToken = token
+			return nil, err
+		},
+	}
+}
+
+// riskBirdAuditPointStep 审核通过流程生成的积分获取记录
+func riskBirdAuditPointStep(when func(st *riskBirdFlowState) bool) riskBirdStepUnit {
+	return riskBirdStepUnit{
+		Name:  "审核积分获取记录",
+		When:  when,
+		Error: "积分审核失败",
+		Input: func(st *riskBirdFlowState) map[string]interface{} {
+			return map[string]interface{}{"pointAcquisitionId": st.Vars.PointAcquisitionID}
+		},
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			return nil, st.Client.AuditPointAcquisition(st.AdminToken, []int64{st.Vars.PointAcquisitionID}, request.RiskBirdAuditApprove, 2)
+		},
+	}
+}
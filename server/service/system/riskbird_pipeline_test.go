@@ -0,0 +1,184 @@
+package system
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// testRiskBirdPipeline 构造测试流程：登录 -> 改价(可补偿) -> 下单 -> 恢复价格 -> 更新订单，failAt 指定失败的步骤
+func testRiskBirdPipeline(calls *[]string, failAt string) riskBirdPipeline {
+	unit := func(name string) riskBirdStepUnit {
+		return riskBirdStepUnit{
+			Name: name,
+			Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+				*calls = append(*calls, name)
+				if name == failAt {
+					return nil, errors.New("failed")
+				}
+				if name == "下单" {
+					st.Vars.Orders["order"] = "NO1"
+				}
+				return nil, nil
+			},
+		}
+	}
+	login := unit("登录")
+	login.ReadOnly, login.Rerun = true, true
+	price := unit("改价")
+	price.UndoName = "恢复价格"
+	price.Undo = func(*riskBirdFlowState) (map[string]interface{}, error) {
+		*calls = append(*calls, "补偿")
+		return nil, nil
+	}
+	restore := unit("恢复价格")
+	restore.Settles = "改价"
+	return riskBirdPipeline{Flow: "test", Steps: []riskBirdStepUnit{login, price, unit("下单"), restore, unit("更新订单")}}
+}
+
+func newTestRiskBirdFlowState() *riskBirdFlowState {
+	return &riskBirdFlowState{Flow: "test", Vars: riskBirdFlowVars{Orders: map[string]string{}}}
+}
+
+func TestRiskBirdPipelineCompensateAndResume(t *testing.T) {
+	global.GVA_LOG = zap.NewNop()
+
+	// 恢复价格之前失败时补偿改价，断点要求续跑时重做改价
+	var calls []string
+	result, err := testRiskBirdPipeline(&calls, "恢复价格").Run(newTestRiskBirdFlowState(), nil, riskBirdPipelineOptions{})
+	if err == nil {
+		t.Fatal("Run() want error")
+	}
+	if want := []string{"登录", "改价", "下单", "恢复价格", "补偿"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Run() calls = %v, want %v", calls, want)
+	}
+	checkpoint := result.Checkpoint
+	if checkpoint == nil || checkpoint.Step != "恢复价格" || !reflect.DeepEqual(checkpoint.Redo, []string{"改价"}) {
+		t.Fatalf("Run() checkpoint = %+v, want step 恢复价格 redo [改价]", checkpoint)
+	}
+
+	// 续跑时重新登录、重做改价，跳过已完成的下单，并恢复订单号
+	calls = nil
+	st := newTestRiskBirdFlowState()
+	result, err = testRiskBirdPipeline(&calls, "").Run(st, nil, riskBirdPipelineOptions{Resume: checkpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"登录", "改价", "恢复价格", "更新订单"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("resume calls = %v, want %v", calls, want)
+	}
+	if st.Vars.Orders["order"] != "NO1" {
+		t.Errorf("resume order = %q, want NO1", st.Vars.Orders["order"])
+	}
+	if result.Plan[2].Status != system.RiskBirdStepSkipped {
+		t.Errorf("resume plan[2] status = %s, want %s", result.Plan[2].Status, system.RiskBirdStepSkipped)
+	}
+
+	// 恢复价格成功后不再补偿改价
+	calls = nil
+	_, _ = testRiskBirdPipeline(&calls, "更新订单").Run(newTestRiskBirdFlowState(), nil, riskBirdPipelineOptions{})
+	if want := []string{"登录", "改价", "下单", "恢复价格", "更新订单"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("settled calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRiskBirdPipelineDryRun(t *testing.T) {
+	global.GVA_LOG = zap.NewNop()
+	var calls []string
+	result, err := testRiskBirdPipeline(&calls, "").Run(newTestRiskBirdFlowState(), nil, riskBirdPipelineOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"登录"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("dry run calls = %v, want %v", calls, want)
+	}
+	if len(result.Plan) != 5 || result.Plan[0].Status != system.RiskBirdStepSucceeded || result.Plan[4].Status != system.RiskBirdStepPlanned {
+		t.Errorf("dry run plan = %+v", result.Plan)
+	}
+}
+
+func TestRiskBirdLoginStepReadOnly(t *testing.T) {
+	tests := []struct {
+		login riskBirdLogin
+		want  bool
+	}{
+		{riskBirdLogin{Phone: "13800000000", Password: "pwd"}, true},
+		{riskBirdLogin{Phone: "13800000000", SmsLogin: true}, false},
+		{riskBirdLogin{Phone: "13800000000", Impersonate: true}, false},
+	}
+	for _, tt := range tests {
+		// 验证码登录和代登录在预演时只记录为计划，不发送短信也不记录审计
+		for _, unit := range []riskBirdStepUnit{riskBirdLoginStep(tt.login), riskBirdBalanceLookupStep(tt.login), riskBirdPointLookupStep(tt.login)} {
+			if unit.ReadOnly != tt.want {
+				t.Errorf("%s ReadOnly = %v, want %v (login %+v)", unit.Name, unit.ReadOnly, tt.want, tt.login)
+			}
+			if !unit.ReadOnly && unit.Simulate == nil {
+				t.Errorf("%s has no Simulate for dry run", unit.Name)
+			}
+		}
+	}
+}
+
+func TestRiskBirdPipelinePricingLock(t *testing.T) {
+	global.GVA_LOG = zap.NewNop()
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gormDB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec("CREATE TABLE p_product_cfg (id INTEGER PRIMARY KEY, cfg_value TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	price := riskBirdReportPriceStep(func(*riskBirdFlowState) common.Money { return common.Yuan(1) })
+	locked := riskBirdStepUnit{
+		Name: "下单",
+		Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+			// 修改价格到恢复价格之间，同一环境的其他流程无法获取锁
+			if riskBirdPricingLock("test").TryLock() {
+				return nil, errors.New("pricing lock not held")
+			}
+			return nil, nil
+		},
+	}
+	failed := riskBirdStepUnit{
+		Name: "失败",
+		Run: func(*riskBirdFlowState, map[string]interface{}) (map[string]interface{}, error) {
+			return nil, errors.New("failed")
+		},
+	}
+	tests := []struct {
+		name    string
+		steps   []riskBirdStepUnit
+		wantErr bool
+	}{
+		{name: "恢复价格后释放", steps: []riskBirdStepUnit{price, locked, riskBirdRestoreReportPriceStep()}},
+		{name: "补偿后释放", steps: []riskBirdStepUnit{price, locked, failed}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newTestRiskBirdFlowState()
+			st.Env.Name, st.DB = "test", db
+			_, err := riskBirdPipeline{Flow: "test", Steps: tt.steps}.Run(st, nil, riskBirdPipelineOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			lock := riskBirdPricingLock("test")
+			if !lock.TryLock() {
+				t.Fatal("pricing lock not released")
+			}
+			lock.Unlock()
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package system
+
+import (
+	"errors"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+type RiskBirdPointAuditService struct{}
+
+var RiskBirdPointAuditServiceApp = new(RiskBirdPointAuditService)
+
+// 单次提交审核的最大记录数
+const riskBirdAuditBatchSize = 100
+
+// 默认审核类型，与积分修改流程保持一致
+const defaultRiskBirdAuditType = 2
+
+// GetPendingPointAcquisitions 分页获取待审核的积分获取记录
+func (s *RiskBirdPointAuditService) GetPendingPointAcquisitions(info systemReq.RiskBirdPendingPointSearch) (list []request.PointAcquisition, total int64, err error) {
+	env, err := riskBirdEnv(info.Env)
+	if err != nil {
+		return nil, 0, err
+	}
+	db, err := newRiskBirdDB(env)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer db.Close()
+
+	if info.Page <= 0 {
+		info.Page = 1
+	}
+	if info.PageSize <= 0 {
+		info.PageSize = 10
+	}
+	return request.ListPendingPointAcquisitions(db, info.UserID, info.PageSize, info.PageSize*(info.Page-1))
+}
+
+// AuditPointAcquisitions 批量审核积分获取记录，返回已审核的记录数
+func (s *RiskBirdPointAuditService) AuditPointAcquisitions(req systemReq.AuditRiskBirdPointAcquisitions) (int, error) {
+	env, err := riskBirdEnv(req.Env)
+	if err != nil {
+		return 0, err
+	}
+	if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationPointAudit); err != nil {
+		return 0, err
+	}
+	if req.AuditType == 0 {
+		req.AuditType = defaultRiskBirdAuditType
+	}
+
+	ids := req.IDs
+	if len(ids) == 0 {
+		if req.UserID == 0 {
+			return 0, errors.New("请指定需要审核的记录ID或用户ID")
+		}
+		db, err := newRiskBirdDB(env)
+		if err != nil {
+			return 0, err
+		}
+		ids, err = request.GetPendingPointAcquisitionIDs(db, req.UserID)
+		db.Close()
+		if err != nil {
+			global.GVA_LOG.Error("查询待审核积分记录失败", zap.Error(err))
+			return 0, errors.New("查询待审核积分记录失败")
+		}
+		if len(ids) == 0 {
+			return 0, nil
+		}
+	}
+
+	client := newRiskBirdClient(env)
+	adminToken, err := riskBirdAdminLogin(env, client)
+	if err != nil {
+		global.GVA_LOG.Error("管理员登录失败", zap.Error(err))
+		return 0, errors.New("管理员登录失败")
+	}
+
+	audited := 0
+	for start := 0; start < len(ids); start += riskBirdAuditBatchSize {
+		end := min(start+riskBirdAuditBatchSize, len(ids))
+		if err = client.AuditPointAcquisition(adminToken, ids[start:end], req.AuditResult, req.AuditType); err != nil {
+			global.GVA_LOG.Error("积分审核失败", zap.Int("audited", audited), zap.Error(err))
+			return audited, err
+		}
+		audited = end
+	}
+	return audited, nil
+}
@@ -0,0 +1,242 @@
+package system
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+const (
+	// riskBirdReconcileSpec 未配置 reconcile-spec 时的核对时间
+	riskBirdReconcileSpec = "0 2 * * *"
+	// riskBirdReconcileTaskName 账务核对在 GVA_Timer 中的任务名称
+	riskBirdReconcileTaskName = "riskbird-reconcile"
+	// riskBirdReconcileRetention 账务核对结果保留时间
+	riskBirdReconcileRetention = 30 * 24 * time.Hour
+)
+
+type RiskBirdReconciliationService struct{}
+
+var RiskBirdReconciliationServiceApp = new(RiskBirdReconciliationService)
+
+// ReconcileAccounts 核对账号的余额和积分，按数据库计算期望值并与接口返回值比较，结果逐个保存。
+// 指定账号或环境时要求操作人在涉及的全部环境中有核对权限，否则只核对有权限的环境；
+// 单个账号核对失败时记录失败原因并继续核对其他账号
+func (s *RiskBirdReconciliationService) ReconcileAccounts(req systemReq.ReconcileRiskBirdAccounts, trigger string) (list []system.RiskBirdReconciliation, err error) {
+	db := global.GVA_DB.Model(&system.RiskBirdAccount{})
+	if len(req.AccountIDs) > 0 {
+		db = db.Where("id IN ?", req.AccountIDs)
+	} else {
+		if req.Env != "" {
+			db = db.Where("env = ?", req.Env)
+		}
+		if req.Tag != "" {
+			db = db.Where("tags LIKE ?", "%\""+req.Tag+"\"%")
+		}
+	}
+	var accounts []system.RiskBirdAccount
+	if err = db.Order("env, id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	// 同一环境的账号共用数据库连接和客户端
+	explicit := len(req.AccountIDs) > 0 || req.Env != ""
+	byEnv := make(map[string][]system.RiskBirdAccount)
+	var envNames []string
+	for _, account := range accounts {
+		if _, ok := byEnv[account.Env]; !ok {
+			if err = checkRiskBirdScope(req.OperatorAuthorityID, account.Env, system.RiskBirdOperationReconcile); err != nil {
+				if explicit {
+					return nil, err
+				}
+				byEnv[account.Env] = nil
+				continue
+			}
+			envNames = append(envNames, account.Env)
+		}
+		if slices.Contains(envNames, account.Env) {
+			byEnv[account.Env] = append(byEnv[account.Env], account)
+		}
+	}
+	for _, name := range envNames {
+		list = append(list, s.reconcileEnv(name, byEnv[name], trigger)...)
+	}
+	return list, nil
+}
+
+// reconcileEnv 核对同一环境的账号
+func (s *RiskBirdReconciliationService) reconcileEnv(envName string, accounts []system.RiskBirdAccount, trigger string) (list []system.RiskBirdReconciliation) {
+	env, envErr := riskBirdEnv(envName)
+	var riskBirdDB *sql.DB
+	if envErr == nil {
+		if riskBirdDB, envErr = newRiskBirdDB(env); envErr == nil {
+			defer riskBirdDB.Close()
+		}
+	}
+	client := newRiskBirdClient(env)
+	table := riskBirdBalanceTable(global.GVA_CONFIG.RiskBird.BalanceTable)
+
+	for _, account := range accounts {
+		result := system.RiskBirdReconciliation{
+			AccountID: account.ID,
+			Env:       account.Env,
+			Phone:     account.Phone,
+			UserID:    account.UserID,
+			Trigger:   trigger,
+			CheckedAt: time.Now(),
+		}
+		err := envErr
+		if err == nil {
+			err = reconcileRiskBirdAccount(env, riskBirdDB, client, table, account, &result)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Matched = err == nil && len(result.Mismatches) == 0
+		if err := global.GVA_DB.Create(&result).Error; err != nil {
+			global.GVA_LOG.Error("保存RiskBird账务核对结果失败", zap.Uint("accountId", account.ID), zap.Error(err))
+		}
+		list = append(list, result)
+	}
+	return list
+}
+
+// reconcileRiskBirdAccount 登录账号读取接口返回的余额和积分，与数据库计算的期望值比较，没有密码的账号使用短信验证码登录
+func reconcileRiskBirdAccount(env config.RiskBirdEnv, db *sql.DB, client *request.RiskBirdAPIClient, table request.BalanceTable, account system.RiskBirdAccount, result *system.RiskBirdReconciliation) error {
+	session, err := loginRiskBirdAccount(env, db, client, account.Phone, account.Password)
+	if err != nil {
+		return err
+	}
+	token := session.Token
+	result.UserID = session.UserID
+	if result.APIBalance, err = client.GetBalance(token); err != nil {
+		return fmt.Errorf("获取用户余额失败: %w", err)
+	}
+	if result.APIPoints, err = client.GetPointOverview(token); err != nil {
+		return fmt.Errorf("获取用户积分信息失败: %w", err)
+	}
+
+	balanceRows, dbBalance, err := request.ListBalanceRows(db, table, result.UserID)
+	if err != nil {
+		return fmt.Errorf("查询余额账户失败: %w", err)
+	}
+	pointRows, dbPoints, err := request.ListAvailablePointAcquisitions(db, result.UserID, time.Now())
+	if err != nil {
+		return fmt.Errorf("查询积分记录失败: %w", err)
+	}
+	result.DBBalance, result.DBPoints = dbBalance, dbPoints
+
+	if result.APIBalance != result.DBBalance {
+		result.Mismatches = append(result.Mismatches, system.RiskBirdLedgerMismatch{
+			Item: system.RiskBirdLedgerBalance,
+			API:  result.APIBalance.String(),
+			DB:   result.DBBalance.String(),
+			Rows: balanceRows,
+		})
+	}
+	if result.APIPoints != result.DBPoints {
+		result.Mismatches = append(result.Mismatches, system.RiskBirdLedgerMismatch{
+			Item: system.RiskBirdLedgerPoints,
+			API:  fmt.Sprint(result.APIPoints),
+			DB:   fmt.Sprint(result.DBPoints),
+			Rows: pointRows,
+		})
+	}
+	return nil
+}
+
+// riskBirdBalanceTable 补全余额账户表结构的默认值
+func riskBirdBalanceTable(cfg config.RiskBirdBalanceTable) request.BalanceTable {
+	table := request.BalanceTable{
+		Table:          cfg.Table,
+		UserColumn:     cfg.UserColumn,
+		BalanceColumns: cfg.BalanceColumns,
+	}
+	if table.Table == "" {
+		table.Table = "recharge_account"
+	}
+	if table.UserColumn == "" {
+		table.UserColumn = "user_id"
+	}
+	if len(table.BalanceColumns) == 0 {
+		table.BalanceColumns = []string{"balance", "gift_balance"}
+	}
+	return table
+}
+
+// RegisterReconcile 在 GVA_Timer 中注册每晚的账号库账务核对
+func (s *RiskBirdReconciliationService) RegisterReconcile() error {
+	// 重新加载配置时会再次注册，先移除已注册的任务，避免重复核对和重复发送验证码
+	global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, riskBirdReconcileTaskName)
+	spec := global.GVA_CONFIG.RiskBird.ReconcileSpec
+	if spec == "off" {
+		return nil
+	}
+	if spec == "" {
+		spec = riskBirdReconcileSpec
+	}
+	_, err := global.GVA_Timer.AddTaskByFunc(RiskBirdCronName, spec, func() {
+		s.reconcileAll()
+	}, riskBirdReconcileTaskName)
+	return err
+}
+
+// reconcileAll 核对账号库全部账号并清理过期的核对结果，由定时任务调用
+func (s *RiskBirdReconciliationService) reconcileAll() {
+	list, err := s.ReconcileAccounts(systemReq.ReconcileRiskBirdAccounts{OperatorAuthorityID: system.RiskBirdInternalAuthorityID}, "cron")
+	if err != nil {
+		global.GVA_LOG.Error("RiskBird账务核对失败", zap.Error(err))
+		return
+	}
+	for _, result := range list {
+		if !result.Matched {
+			global.GVA_LOG.Warn("RiskBird账务不一致",
+				zap.String("env", result.Env),
+				zap.Uint("accountId", result.AccountID),
+				zap.String("error", result.Error),
+				zap.Any("mismatches", result.Mismatches))
+		}
+	}
+	notifyRiskBirdReconcileMismatch(list)
+	err = global.GVA_DB.Where("checked_at < ?", time.Now().Add(-riskBirdReconcileRetention)).Delete(&system.RiskBirdReconciliation{}).Error
+	if err != nil {
+		global.GVA_LOG.Error("清理RiskBird账务核对结果失败", zap.Error(err))
+	}
+}
+
+// GetReconciliationList 分页获取账务核对结果，只返回操作人角色可以查看的环境
+func (s *RiskBirdReconciliationService) GetReconciliationList(info systemReq.RiskBirdReconciliationSearch, authorityID uint) (list []system.RiskBirdReconciliation, total int64, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdReconciliation{}).Where("env IN ?", envs)
+	if info.AccountID != 0 {
+		db = db.Where("account_id = ?", info.AccountID)
+	}
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Matched != nil {
+		db = db.Where("matched = ?", *info.Matched)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
@@ -0,0 +1,35 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+)
+
+func TestRegisterReconcileReplacesTask(t *testing.T) {
+	defer global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, riskBirdReconcileTaskName)
+
+	// 重新加载配置时再次注册，只保留一个任务
+	for range 2 {
+		if err := RiskBirdReconciliationServiceApp.RegisterReconcile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, riskBirdReconcileTaskName)
+	if _, ok := global.GVA_Timer.FindTask(RiskBirdCronName, riskBirdReconcileTaskName); ok {
+		t.Error("RegisterReconcile() registered the task more than once")
+	}
+
+	// 关闭后重新加载时移除已注册的任务
+	if err := RiskBirdReconciliationServiceApp.RegisterReconcile(); err != nil {
+		t.Fatal(err)
+	}
+	global.GVA_CONFIG.RiskBird.ReconcileSpec = "off"
+	defer func() { global.GVA_CONFIG.RiskBird.ReconcileSpec = "" }()
+	if err := RiskBirdReconciliationServiceApp.RegisterReconcile(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := global.GVA_Timer.FindTask(RiskBirdCronName, riskBirdReconcileTaskName); ok {
+		t.Error("RegisterReconcile() with spec off kept the task")
+	}
+}
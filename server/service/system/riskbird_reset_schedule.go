@@ -0,0 +1,380 @@
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// RiskBirdCronName RiskBird 相关定时任务在 GVA_Timer 中使用的 cron 名称
+const RiskBirdCronName = "RiskBird"
+
+type RiskBirdResetScheduleService struct{}
+
+var RiskBirdResetScheduleServiceApp = new(RiskBirdResetScheduleService)
+
+// 同一个计划同一时间只允许执行一次
+var riskBirdResetRunning sync.Map
+
+func resetScheduleTaskName(ID uint) string {
+	return fmt.Sprintf("riskbird-reset-%d", ID)
+}
+
+// fillNextRunAt 根据cron表达式计算下次执行时间
+func fillNextRunAt(schedule *system.RiskBirdResetSchedule) {
+	schedule.NextRunAt = nil
+	if !schedule.Enabled {
+		return
+	}
+	sched, err := cron.ParseStandard(schedule.Spec)
+	if err != nil {
+		return
+	}
+	next := sched.Next(time.Now())
+	schedule.NextRunAt = &next
+}
+
+// CreateResetSchedule 创建重置计划，schedule.AuthorityId 为操作人角色
+func (s *RiskBirdResetScheduleService) CreateResetSchedule(schedule *system.RiskBirdResetSchedule) (err error) {
+	if err = checkResetSchedule(*schedule); err != nil {
+		return err
+	}
+	if err = fillResetAccounts(schedule.Accounts); err != nil {
+		return err
+	}
+	if err = checkResetScheduleScope(schedule.AuthorityId, schedule.Accounts); err != nil {
+		return err
+	}
+	if err = global.GVA_DB.Create(schedule).Error; err != nil {
+		return err
+	}
+	return s.registerResetSchedule(*schedule)
+}
+
+// DeleteResetSchedule 删除重置计划
+func (s *RiskBirdResetScheduleService) DeleteResetSchedule(ID uint) (err error) {
+	err = global.GVA_DB.Delete(&system.RiskBirdResetSchedule{}, "id = ?", ID).Error
+	if err != nil {
+		return err
+	}
+	global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, resetScheduleTaskName(ID))
+	return nil
+}
+
+// UpdateResetSchedule 更新重置计划，schedule.AuthorityId 为操作人角色，之后按该角色执行计划
+func (s *RiskBirdResetScheduleService) UpdateResetSchedule(schedule system.RiskBirdResetSchedule) (err error) {
+	if err = checkResetSchedule(schedule); err != nil {
+		return err
+	}
+	if err = fillResetAccounts(schedule.Accounts); err != nil {
+		return err
+	}
+	if err = checkResetScheduleScope(schedule.AuthorityId, schedule.Accounts); err != nil {
+		return err
+	}
+	err = global.GVA_DB.Model(&system.RiskBirdResetSchedule{}).Where("id = ?", schedule.ID).
+		Select("name", "spec", "accounts", "recharge_amount", "gift_amount", "point_amount", "enabled", "authority_id").
+		Updates(&schedule).Error
+	if err != nil {
+		return err
+	}
+	return s.registerResetSchedule(schedule)
+}
+
+// GetResetSchedule 根据ID获取重置计划
+func (s *RiskBirdResetScheduleService) GetResetSchedule(ID uint) (schedule system.RiskBirdResetSchedule, err error) {
+	err = global.GVA_DB.Where("id = ?", ID).First(&schedule).Error
+	fillNextRunAt(&schedule)
+	return
+}
+
+// GetResetScheduleList 分页获取重置计划
+func (s *RiskBirdResetScheduleService) GetResetScheduleList(info systemReq.RiskBirdResetScheduleSearch) (list []system.RiskBirdResetSchedule, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdResetSchedule{})
+	if info.Name != "" {
+		db = db.Where("name LIKE ?", "%"+info.Name+"%")
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	for i := range list {
+		fillNextRunAt(&list[i])
+	}
+	return list, total, err
+}
+
+// GetResetRunList 分页获取重置执行记录
+func (s *RiskBirdResetScheduleService) GetResetRunList(info systemReq.RiskBirdResetRunSearch) (list []system.RiskBirdResetRun, total int64, err error) {
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdResetRun{})
+	if info.ScheduleID != 0 {
+		db = db.Where("schedule_id = ?", info.ScheduleID)
+	}
+	if info.Status != "" {
+		db = db.Where("status = ?", info.Status)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+// LoadResetSchedules 将所有启用的重置计划注册到 GVA_Timer
+func (s *RiskBirdResetScheduleService) LoadResetSchedules() error {
+	if err := migrateResetAccounts(); err != nil {
+		global.GVA_LOG.Error("迁移RiskBird重置计划账号失败", zap.Error(err))
+	}
+	var schedules []system.RiskBirdResetSchedule
+	if err := global.GVA_DB.Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		if err := s.registerResetSchedule(schedule); err != nil {
+			global.GVA_LOG.Error("注册RiskBird重置计划失败", zap.Uint("id", schedule.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// registerResetSchedule 在 GVA_Timer 中注册或移除计划对应的任务
+func (s *RiskBirdResetScheduleService) registerResetSchedule(schedule system.RiskBirdResetSchedule) error {
+	taskName := resetScheduleTaskName(schedule.ID)
+	global.GVA_Timer.RemoveTaskByName(RiskBirdCronName, taskName)
+	if !schedule.Enabled {
+		return nil
+	}
+	ID := schedule.ID
+	_, err := global.GVA_Timer.AddTaskByFunc(RiskBirdCronName, schedule.Spec, func() {
+		if _, err := s.RunResetSchedule(ID, "cron", system.RiskBirdInternalAuthorityID); err != nil {
+			global.GVA_LOG.Error("RiskBird重置计划执行失败", zap.Uint("id", ID), zap.Error(err))
+		}
+	}, taskName)
+	return err
+}
+
+// RunResetSchedule 执行重置计划，将计划中的账号依次重置为基准余额和积分。
+// authorityID 为触发执行的操作人角色，计划按创建或最近修改计划的操作人角色执行，两者都需要有权限
+func (s *RiskBirdResetScheduleService) RunResetSchedule(ID uint, trigger string, authorityID uint) (run system.RiskBirdResetRun, err error) {
+	if _, running := riskBirdResetRunning.LoadOrStore(ID, struct{}{}); running {
+		return run, errors.New("该重置计划正在执行中")
+	}
+	defer riskBirdResetRunning.Delete(ID)
+
+	var schedule system.RiskBirdResetSchedule
+	if err = global.GVA_DB.Where("id = ?", ID).First(&schedule).Error; err != nil {
+		return run, err
+	}
+	if err = checkResetScheduleScope(authorityID, schedule.Accounts); err != nil {
+		return run, err
+	}
+	if err = checkResetScheduleScope(schedule.AuthorityId, schedule.Accounts); err != nil {
+		return run, fmt.Errorf("重置计划的操作人角色无权执行: %w", err)
+	}
+
+	run = system.RiskBirdResetRun{
+		ScheduleID: schedule.ID,
+		Trigger:    trigger,
+		StartedAt:  time.Now(),
+	}
+	failed := 0
+	for _, item := range schedule.Accounts {
+		result := system.RiskBirdResetAccountResult{Env: item.Env, Phone: item.Phone}
+		account, err := getRiskBirdAccount(item.AccountID)
+		if err != nil {
+			result.BalanceError = fmt.Sprintf("账号库中不存在账号 %d", item.AccountID)
+			result.PointError = result.BalanceError
+			failed++
+			run.Results = append(run.Results, result)
+			continue
+		}
+		result.Env = account.Env
+		// 充值与积分流程会修改共享的产品配置，账号之间必须串行执行
+		err = UserBalanceServiceApp.ModifyUserBalance(systemReq.ModifyUserBalance{
+			Env:            account.Env,
+			Phone:          account.Phone,
+			Password:       account.Password,
+			SmsLogin:       account.Password == "",
+			RechargeAmount: schedule.RechargeAmount,
+			GiftAmount:     schedule.GiftAmount,
+
+			OperatorAuthorityID: schedule.AuthorityId,
+		})
+		if err != nil {
+			result.BalanceError = err.Error()
+		}
+		err = UserPointServiceApp.ModifyUserPoint(systemReq.ModifyUserPoint{
+			Env:         account.Env,
+			Phone:       account.Phone,
+			Password:    account.Password,
+			SmsLogin:    account.Password == "",
+			PointAmount: schedule.PointAmount,
+
+			OperatorAuthorityID: schedule.AuthorityId,
+		})
+		if err != nil {
+			result.PointError = err.Error()
+		}
+		if result.BalanceError != "" || result.PointError != "" {
+			failed++
+		}
+		run.Results = append(run.Results, result)
+	}
+	run.FinishedAt = time.Now()
+	switch {
+	case failed == 0:
+		run.Status = system.RiskBirdResetStatusSuccess
+	case failed < len(schedule.Accounts):
+		run.Status = system.RiskBirdResetStatusPartial
+	default:
+		run.Status = system.RiskBirdResetStatusFailed
+	}
+
+	if err = global.GVA_DB.Create(&run).Error; err != nil {
+		return run, err
+	}
+	notifyRiskBirdResetRun(schedule, run)
+	err = global.GVA_DB.Model(&system.RiskBirdResetSchedule{}).Where("id = ?", schedule.ID).Updates(map[string]interface{}{
+		"last_run_at": run.StartedAt,
+		"last_status": run.Status,
+	}).Error
+	return run, err
+}
+
+// checkResetScheduleScope 校验角色能否在计划中账号所属的环境中修改余额和积分
+func checkResetScheduleScope(authorityID uint, accounts []system.RiskBirdResetAccount) error {
+	var envs []string
+	for _, account := range accounts {
+		if !slices.Contains(envs, account.Env) {
+			envs = append(envs, account.Env)
+		}
+	}
+	for _, env := range envs {
+		for _, operation := range []string{system.RiskBirdOperationBalance, system.RiskBirdOperationPoint} {
+			if err := checkRiskBirdScope(authorityID, env, operation); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fillResetAccounts 按账号库填写计划中账号的环境和手机号
+func fillResetAccounts(accounts []system.RiskBirdResetAccount) error {
+	for i := range accounts {
+		account, err := getRiskBirdAccount(accounts[i].AccountID)
+		if err != nil {
+			return fmt.Errorf("账号库中不存在账号 %d", accounts[i].AccountID)
+		}
+		accounts[i].Env, accounts[i].Phone = account.Env, account.Phone
+	}
+	return nil
+}
+
+// legacyResetAccount 旧版本计划中保存的账号
+type legacyResetAccount struct {
+	AccountID uint   `json:"accountId"`
+	Phone     string `json:"phone"`
+	Password  string `json:"password"`
+}
+
+// migrateResetAccounts 旧版本的计划直接保存手机号和密码，迁移为引用账号库中的账号：
+// 默认环境中已登记的手机号直接引用，未登记的连同密码登记到账号库，迁移后计划中不再保存密码。
+// 单个计划迁移失败时记录日志并停用该计划，避免按未迁移的账号执行，其余计划继续迁移
+func migrateResetAccounts() error {
+	var rows []struct {
+		ID       uint
+		Accounts string
+	}
+	if err := global.GVA_DB.Model(&system.RiskBirdResetSchedule{}).Select("id", "accounts").Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		err := migrateResetScheduleAccounts(row.ID, row.Accounts)
+		if err == nil {
+			continue
+		}
+		global.GVA_LOG.Error("迁移RiskBird重置计划账号失败，已停用该计划", zap.Uint("id", row.ID), zap.Error(err))
+		if err = global.GVA_DB.Model(&system.RiskBirdResetSchedule{}).Where("id = ?", row.ID).Update("enabled", false).Error; err != nil {
+			global.GVA_LOG.Error("停用RiskBird重置计划失败", zap.Uint("id", row.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// migrateResetScheduleAccounts 迁移一个计划的账号列表，已迁移的计划不做修改
+func migrateResetScheduleAccounts(ID uint, raw string) error {
+	var legacy []legacyResetAccount
+	if err := json.Unmarshal([]byte(raw), &legacy); err != nil {
+		return fmt.Errorf("解析重置计划 %d 的账号失败: %w", ID, err)
+	}
+	if !slices.ContainsFunc(legacy, func(a legacyResetAccount) bool { return a.AccountID == 0 || a.Password != "" }) {
+		return nil
+	}
+	accounts := make([]system.RiskBirdResetAccount, 0, len(legacy))
+	for _, item := range legacy {
+		if item.AccountID != 0 {
+			accounts = append(accounts, system.RiskBirdResetAccount{AccountID: item.AccountID})
+			continue
+		}
+		account := system.RiskBirdAccount{Env: config.RiskBirdDefaultEnv, Phone: item.Phone}
+		err := global.GVA_DB.Where(&account).Attrs(system.RiskBirdAccount{
+			Password: item.Password,
+			Source:   system.RiskBirdAccountSourceManual,
+			Remark:   fmt.Sprintf("由重置计划 %d 迁移", ID),
+		}).FirstOrCreate(&account).Error
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, system.RiskBirdResetAccount{AccountID: account.ID})
+	}
+	if err := fillResetAccounts(accounts); err != nil {
+		return err
+	}
+	return global.GVA_DB.Model(&system.RiskBirdResetSchedule{}).Where("id = ?", ID).
+		Select("accounts").Updates(&system.RiskBirdResetSchedule{Accounts: accounts}).Error
+}
+
+// checkResetSchedule 校验重置计划参数
+func checkResetSchedule(schedule system.RiskBirdResetSchedule) error {
+	if _, err := cron.ParseStandard(schedule.Spec); err != nil {
+		return fmt.Errorf("cron表达式不合法: %w", err)
+	}
+	if len(schedule.Accounts) == 0 {
+		return errors.New("重置账号列表不能为空")
+	}
+	for _, account := range schedule.Accounts {
+		if account.AccountID == 0 {
+			return errors.New("重置账号需要从账号库中选择")
+		}
+	}
+	if schedule.RechargeAmount < 0 || schedule.GiftAmount < 0 || schedule.PointAmount < 0 {
+		return errors.New("基准余额和积分不能为负数")
+	}
+	if schedule.PointAmount%riskBirdPointsPerYuan != 0 {
+		return errors.New("基准积分必须是5的倍数")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestMigrateResetAccountsDisablesFailedSchedules(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&system.RiskBirdResetSchedule{}, &system.RiskBirdAccount{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB, oldLog := global.GVA_DB, global.GVA_LOG
+	global.GVA_DB, global.GVA_LOG = db, zap.NewNop()
+	defer func() { global.GVA_DB, global.GVA_LOG = oldDB, oldLog }()
+
+	schedules := []system.RiskBirdResetSchedule{{Name: "broken", Enabled: true}, {Name: "legacy", Enabled: true}}
+	if err = db.Create(&schedules).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 第一个计划的账号列表无法解析，第二个是只保存了手机号的旧版本计划
+	db.Exec("UPDATE riskbird_reset_schedules SET accounts = ? WHERE id = ?", "{", schedules[0].ID)
+	db.Exec("UPDATE riskbird_reset_schedules SET accounts = ? WHERE id = ?", `[{"phone":"13800000000"}]`, schedules[1].ID)
+
+	if err = migrateResetAccounts(); err != nil {
+		t.Fatal(err)
+	}
+	var broken, legacy system.RiskBirdResetSchedule
+	db.Select("id", "enabled").First(&broken, schedules[0].ID)
+	if broken.Enabled {
+		t.Error("schedule with malformed accounts should be disabled")
+	}
+	if err = db.First(&legacy, schedules[1].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !legacy.Enabled || len(legacy.Accounts) != 1 || legacy.Accounts[0].AccountID == 0 || legacy.Accounts[0].Phone != "13800000000" {
+		t.Errorf("legacy schedule = %+v, want migrated and enabled", legacy)
+	}
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	"gorm.io/gorm"
+)
+
+type RiskBirdScopeService struct{}
+
+var RiskBirdScopeServiceApp = new(RiskBirdScopeService)
+
+// SetAuthorityScopes 替换角色的 RiskBird 环境权限，Scopes 为空时角色不受限制
+func (s *RiskBirdScopeService) SetAuthorityScopes(adminAuthorityID uint, req systemReq.SetRiskBirdScopes) error {
+	if err := AuthorityServiceApp.CheckAuthorityIDAuth(adminAuthorityID, req.AuthorityId); err != nil {
+		return err
+	}
+	scopes := make([]system.RiskBirdAuthorityScope, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		env, err := riskBirdEnv(scope.Env)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(scopes, func(s system.RiskBirdAuthorityScope) bool { return s.Env == env.Name }) {
+			return fmt.Errorf("RiskBird环境 %s 重复配置", env.Name)
+		}
+		for _, operation := range scope.Operations {
+			if !slices.Contains(system.RiskBirdOperations, operation) {
+				return fmt.Errorf("未知的RiskBird操作类型: %s", operation)
+			}
+		}
+		scopes = append(scopes, system.RiskBirdAuthorityScope{AuthorityId: req.AuthorityId, Env: env.Name, Operations: scope.Operations})
+	}
+	return global.GVA_DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("authority_id = ?", req.AuthorityId).Delete(&system.RiskBirdAuthorityScope{}).Error; err != nil {
+			return err
+		}
+		if len(scopes) == 0 {
+			return nil
+		}
+		return tx.Create(&scopes).Error
+	})
+}
+
+// GetAuthorityScopes 获取角色已配置的 RiskBird 环境权限，返回空表示不限制
+func (s *RiskBirdScopeService) GetAuthorityScopes(authorityID uint) (scopes []system.RiskBirdAuthorityScope, err error) {
+	err = global.GVA_DB.Where("authority_id = ?", authorityID).Find(&scopes).Error
+	return scopes, err
+}
+
+// GetAllowedEnvs 获取角色可以使用的环境及各环境允许的操作，未配置环境权限时返回全部环境和操作
+func (s *RiskBirdScopeService) GetAllowedEnvs(authorityID uint) (allowed []system.RiskBirdAuthorityScope, err error) {
+	scopes, err := s.GetAuthorityScopes(authorityID)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range global.GVA_CONFIG.RiskBird.EnvNames() {
+		if len(scopes) == 0 {
+			allowed = append(allowed, system.RiskBirdAuthorityScope{AuthorityId: authorityID, Env: name, Operations: system.RiskBirdOperations})
+			continue
+		}
+		for _, scope := range scopes {
+			if scope.Env == name && len(scope.Operations) > 0 {
+				allowed = append(allowed, scope)
+			}
+		}
+	}
+	return allowed, nil
+}
+
+// checkRiskBirdScope 校验角色能否在环境中执行操作。
+// 只有 RiskBirdInternalAuthorityID 表示内部调用不做限制，未填写操作人角色时拒绝；角色未配置环境权限时不限制
+func checkRiskBirdScope(authorityID uint, envName, operation string) error {
+	if authorityID == system.RiskBirdInternalAuthorityID {
+		return nil
+	}
+	if authorityID == 0 {
+		return errors.New("缺少操作人角色，无法校验RiskBird环境权限")
+	}
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return err
+	}
+	scopes, err := RiskBirdScopeServiceApp.GetAuthorityScopes(authorityID)
+	if err != nil {
+		return err
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+	for _, scope := range scopes {
+		if scope.Env == env.Name && slices.Contains(scope.Operations, operation) {
+			return nil
+		}
+	}
+	return fmt.Errorf("当前角色无权在RiskBird环境 %s 中执行 %s 操作", env.Name, operation)
+}
+
+// riskBirdScopedEnvs 角色可以查看的环境，角色在环境中有任意一种操作权限即可查看该环境的记录
+func riskBirdScopedEnvs(authorityID uint) ([]string, error) {
+	if authorityID == system.RiskBirdInternalAuthorityID {
+		return global.GVA_CONFIG.RiskBird.EnvNames(), nil
+	}
+	if authorityID == 0 {
+		return nil, errors.New("缺少操作人角色，无法校验RiskBird环境权限")
+	}
+	allowed, err := RiskBirdScopeServiceApp.GetAllowedEnvs(authorityID)
+	if err != nil {
+		return nil, err
+	}
+	envs := make([]string, 0, len(allowed))
+	for _, scope := range allowed {
+		envs = append(envs, scope.Env)
+	}
+	return envs, nil
+}
@@ -0,0 +1,17 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+)
+
+func TestCheckRiskBirdScopeOperator(t *testing.T) {
+	if err := checkRiskBirdScope(system.RiskBirdInternalAuthorityID, "", system.RiskBirdOperationBalance); err != nil {
+		t.Errorf("checkRiskBirdScope() internal error = %v, want nil", err)
+	}
+	// 未填写操作人角色时不再视为内部调用
+	if err := checkRiskBirdScope(0, "", system.RiskBirdOperationBalance); err == nil {
+		t.Error("checkRiskBirdScope() without authority want error")
+	}
+}
@@ -0,0 +1,178 @@
+package system
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+)
+
+type RiskBirdSyntheticOrderService struct{}
+
+var RiskBirdSyntheticOrderServiceApp = new(RiskBirdSyntheticOrderService)
+
+// recordSyntheticOrder 记录流程创建的合成订单，记录失败不影响流程本身
+func recordSyntheticOrder(order system.RiskBirdSyntheticOrder) {
+	// 命令行工具未配置管理后台数据库时不记录
+	if order.OrderNo == "" || global.GVA_DB == nil {
+		return
+	}
+	order.Status = system.RiskBirdOrderStatusActive
+	if err := global.GVA_DB.Create(&order).Error; err != nil {
+		global.GVA_LOG.Error("记录RiskBird合成订单失败", zap.String("orderNo", order.OrderNo), zap.Error(err))
+	}
+}
+
+// GetSyntheticOrderList 分页获取合成订单，只返回操作人角色可以查看的环境
+func (s *RiskBirdSyntheticOrderService) GetSyntheticOrderList(info systemReq.RiskBirdSyntheticOrderSearch, authorityID uint) (list []system.RiskBirdSyntheticOrder, total int64, err error) {
+	envs, err := riskBirdScopedEnvs(authorityID)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit := info.PageSize
+	offset := info.PageSize * (info.Page - 1)
+	db := global.GVA_DB.Model(&system.RiskBirdSyntheticOrder{}).Where("env IN ?", envs)
+	if info.Env != "" {
+		db = db.Where("env = ?", info.Env)
+	}
+	if info.Phone != "" {
+		db = db.Where("phone = ?", info.Phone)
+	}
+	if info.Kind != "" {
+		db = db.Where("kind = ?", info.Kind)
+	}
+	if info.Status != "" {
+		db = db.Where("status = ?", info.Status)
+	}
+	err = db.Count(&total).Error
+	if err != nil {
+		return
+	}
+	if limit != 0 {
+		db = db.Limit(limit).Offset(offset)
+	}
+	err = db.Order("id desc").Find(&list).Error
+	return list, total, err
+}
+
+// CleanupOrders 在 RiskBird 数据库中隐藏、取消或删除合成订单
+func (s *RiskBirdSyntheticOrderService) CleanupOrders(req systemReq.CleanupRiskBirdOrders) (result systemRes.RiskBirdOrderCleanupResult, err error) {
+	result.Action = req.Action
+	env, err := riskBirdEnv(req.Env)
+	if err != nil {
+		return result, err
+	}
+	if err = checkRiskBirdScope(req.OperatorAuthorityID, env.Name, system.RiskBirdOperationOrderCleanup); err != nil {
+		return result, err
+	}
+
+	var orders []system.RiskBirdSyntheticOrder
+	db := global.GVA_DB.Where("env = ? AND status = ?", env.Name, system.RiskBirdOrderStatusActive)
+	if req.Phone != "" {
+		db = db.Where("phone = ?", req.Phone)
+	}
+	if len(req.IDs) > 0 {
+		db = db.Where("id in ?", req.IDs)
+	}
+	if err = db.Find(&orders).Error; err != nil {
+		return result, err
+	}
+	if len(orders) == 0 {
+		return result, nil
+	}
+
+	riskBirdDB, err := newRiskBirdDB(env)
+	if err != nil {
+		global.GVA_LOG.Error("连接RiskBird数据库失败", zap.Error(err))
+		return result, errors.New("连接数据库失败")
+	}
+	defer riskBirdDB.Close()
+
+	orderTable, preOrderTable := riskBirdOrderTables(global.GVA_CONFIG.RiskBird.OrderTables)
+	byTable := map[*request.OrderTable][]system.RiskBirdSyntheticOrder{}
+	for _, order := range orders {
+		table := &orderTable
+		if order.Kind == system.RiskBirdOrderKindReportPreOrder || order.Kind == system.RiskBirdOrderKindRechargePreOrder {
+			table = &preOrderTable
+		}
+		byTable[table] = append(byTable[table], order)
+	}
+
+	for table, tableOrders := range byTable {
+		orderNos := make([]string, 0, len(tableOrders))
+		ids := make([]uint, 0, len(tableOrders))
+		for _, order := range tableOrders {
+			orderNos = append(orderNos, order.OrderNo)
+			ids = append(ids, order.ID)
+		}
+		affected, err := request.CleanupOrders(riskBirdDB, *table, req.Action, orderNos)
+		if err != nil {
+			global.GVA_LOG.Error("清理RiskBird合成订单失败", zap.String("table", table.Table), zap.Error(err))
+			return result, err
+		}
+		result.Affected += affected
+
+		now := time.Now()
+		err = global.GVA_DB.Model(&system.RiskBirdSyntheticOrder{}).Where("id in ?", ids).Updates(map[string]interface{}{
+			"status":       system.RiskBirdOrderStatusCleaned,
+			"clean_action": req.Action,
+			"cleaned_at":   now,
+		}).Error
+		if err != nil {
+			return result, err
+		}
+		for _, order := range tableOrders {
+			result.Orders = append(result.Orders, systemRes.RiskBirdCleanedOrder{
+				Phone:   order.Phone,
+				Kind:    order.Kind,
+				OrderNo: order.OrderNo,
+			})
+		}
+	}
+	return result, nil
+}
+
+// riskBirdOrderTables 返回订单表和预订单表结构，未配置的字段使用默认值
+func riskBirdOrderTables(cfg config.RiskBirdOrderTables) (order request.OrderTable, preOrder request.OrderTable) {
+	base := request.OrderTable{
+		OrderNoColumn: cfg.OrderNoColumn,
+		HiddenColumn:  cfg.HiddenColumn,
+		StatusColumn:  cfg.StatusColumn,
+		CancelStatus:  cfg.CancelStatus,
+		UserColumn:    cfg.UserColumn,
+		TimeColumn:    cfg.TimeColumn,
+	}
+	if base.OrderNoColumn == "" {
+		base.OrderNoColumn = "order_no"
+	}
+	if base.HiddenColumn == "" {
+		base.HiddenColumn = "is_deleted"
+	}
+	if base.StatusColumn == "" {
+		base.StatusColumn = "status"
+	}
+	if base.CancelStatus == "" {
+		base.CancelStatus = "cancel"
+	}
+	if base.UserColumn == "" {
+		base.UserColumn = "user_id"
+	}
+	if base.TimeColumn == "" {
+		base.TimeColumn = "create_time"
+	}
+	order, preOrder = base, base
+	order.Table, preOrder.Table = cfg.OrderTable, cfg.PreOrderTable
+	if order.Table == "" {
+		order.Table = "p_order"
+	}
+	if preOrder.Table == "" {
+		preOrder.Table = "p_pre_order"
+	}
+	return order, preOrder
+}
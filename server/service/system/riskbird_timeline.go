@@ -0,0 +1,415 @@
+package system
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
+	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// riskBirdTimelineLimit 时间线最多返回的事件数，每个来源同样最多读取这么多条
+	riskBirdTimelineLimit = 1000
+	// riskBirdTimelineDays 未指定开始时间时查询的天数
+	riskBirdTimelineDays = 30
+)
+
+// riskBirdTimelineQuery 解析后的时间线查询条件
+type riskBirdTimelineQuery struct {
+	phone   string
+	env     string
+	account system.RiskBirdAccount
+	envs    []string // 操作人角色可以查看的环境
+	start   time.Time
+	end     time.Time
+}
+
+// GetAccountTimeline 合并流程执行记录、代登录审计、合成订单、point_acquisition 记录、重置、核对和配置恢复，按时间倒序返回账号的变更时间线。
+// 单个来源读取失败时记录到 Warnings，不影响其余来源。只返回操作人角色可以查看的环境中的事件
+func (s *RiskBirdAccountService) GetAccountTimeline(info systemReq.RiskBirdAccountTimelineSearch, authorityID uint) (timeline systemRes.RiskBirdAccountTimeline, err error) {
+	q, err := newRiskBirdTimelineQuery(info, authorityID)
+	if err != nil {
+		return timeline, err
+	}
+	timeline.Phone, timeline.StartTime, timeline.EndTime = q.phone, q.start, q.end
+
+	var events []systemRes.RiskBirdTimelineEvent
+	collect := func(source string, fn func(q riskBirdTimelineQuery) ([]systemRes.RiskBirdTimelineEvent, error)) {
+		list, err := fn(q)
+		if err != nil {
+			global.GVA_LOG.Error("读取账号时间线失败", zap.String("source", source), zap.Error(err))
+			timeline.Warnings = append(timeline.Warnings, fmt.Sprintf("%s: %v", source, err))
+		}
+		events = append(events, list...)
+	}
+	collect(systemRes.RiskBirdTimelineFlow, riskBirdFlowEvents)
+	collect(systemRes.RiskBirdTimelineImpersonation, riskBirdImpersonationEvents)
+	collect(systemRes.RiskBirdTimelineOrder, riskBirdOrderEvents)
+	collect(systemRes.RiskBirdTimelineReset, riskBirdResetEvents)
+	collect(systemRes.RiskBirdTimelineReconcile, riskBirdReconcileEvents)
+	collect(systemRes.RiskBirdTimelineConfigRestore, func(q riskBirdTimelineQuery) ([]systemRes.RiskBirdTimelineEvent, error) {
+		return riskBirdConfigRestoreEvents(q, riskBirdTimelineEnvs(q, events))
+	})
+	// 未指定环境时只读取出现过该手机号的环境的数据库，避免逐个连接全部环境
+	for _, envName := range riskBirdTimelineEnvs(q, events) {
+		collect(systemRes.RiskBirdTimelinePointAcquisition+"@"+envName, func(q riskBirdTimelineQuery) ([]systemRes.RiskBirdTimelineEvent, error) {
+			return riskBirdPointAcquisitionEvents(q, envName)
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.After(events[j].Time)
+	})
+	if len(events) > riskBirdTimelineLimit {
+		events, timeline.Truncated = events[:riskBirdTimelineLimit], true
+	}
+	fillRiskBirdOperatorNames(events)
+	timeline.Events = events
+	return timeline, nil
+}
+
+func newRiskBirdTimelineQuery(info systemReq.RiskBirdAccountTimelineSearch, authorityID uint) (q riskBirdTimelineQuery, err error) {
+	if q.envs, err = riskBirdScopedEnvs(authorityID); err != nil {
+		return q, err
+	}
+	q.phone = info.Phone
+	if info.AccountID != 0 {
+		if q.account, err = RiskBirdAccountServiceApp.GetAccount(info.AccountID); err != nil {
+			return q, err
+		}
+		if !slices.Contains(q.envs, q.account.Env) {
+			return q, fmt.Errorf("当前角色无权查看RiskBird环境 %s 的记录", q.account.Env)
+		}
+		q.phone = q.account.Phone
+	}
+	if q.phone == "" {
+		return q, errors.New("请指定账号或手机号")
+	}
+	if info.Env != "" {
+		env, err := riskBirdEnv(info.Env)
+		if err != nil {
+			return q, err
+		}
+		if !slices.Contains(q.envs, env.Name) {
+			return q, fmt.Errorf("当前角色无权查看RiskBird环境 %s 的记录", env.Name)
+		}
+		q.env = env.Name
+	}
+	q.end = time.Now()
+	if info.EndTime != nil {
+		q.end = *info.EndTime
+	}
+	q.start = q.end.AddDate(0, 0, -riskBirdTimelineDays)
+	if info.StartTime != nil {
+		q.start = *info.StartTime
+	}
+	if !q.start.Before(q.end) {
+		return q, errors.New("开始时间必须早于结束时间")
+	}
+	return q, nil
+}
+
+// scope 按手机号、环境和时间范围筛选本地记录
+func (q riskBirdTimelineQuery) scope(timeColumn string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("phone = ?", q.phone).Where("env IN ?", q.envs).Where(timeColumn+" >= ? AND "+timeColumn+" < ?", q.start, q.end)
+		if q.env != "" {
+			db = db.Where("env = ?", q.env)
+		}
+		return db.Order(timeColumn + " desc").Limit(riskBirdTimelineLimit)
+	}
+}
+
+func riskBirdFlowEvents(q riskBirdTimelineQuery) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	var runs []system.RiskBirdFlowRun
+	if err = global.GVA_DB.Omit("events").Scopes(q.scope("started_at")).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		summary := fmt.Sprintf("%s流程%s", riskBirdFlowName(run.Flow), run.Status)
+		if run.Error != "" {
+			summary += ": " + run.Error
+		}
+		events = append(events, systemRes.RiskBirdTimelineEvent{
+			Time:       run.StartedAt,
+			Env:        run.Env,
+			Source:     systemRes.RiskBirdTimelineFlow,
+			Action:     run.Flow,
+			Summary:    summary,
+			RefID:      int64(run.ID),
+			OperatorID: run.UserID,
+			Detail:     map[string]interface{}{"status": run.Status, "finishedAt": run.FinishedAt},
+		})
+	}
+	return events, nil
+}
+
+func riskBirdImpersonationEvents(q riskBirdTimelineQuery) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	var logs []system.RiskBirdImpersonationLog
+	if err = global.GVA_DB.Scopes(q.scope("created_at")).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		summary := fmt.Sprintf("%s流程代登录用户%d", riskBirdFlowName(log.Flow), log.TargetUserID)
+		if !log.Success {
+			summary += "失败: " + log.Error
+		}
+		events = append(events, systemRes.RiskBirdTimelineEvent{
+			Time:       log.CreatedAt,
+			Env:        log.Env,
+			Source:     systemRes.RiskBirdTimelineImpersonation,
+			Action:     log.Mode,
+			Summary:    summary,
+			RefID:      int64(log.ID),
+			OperatorID: log.OperatorID,
+			Detail:     map[string]interface{}{"flow": log.Flow, "success": log.Success, "authorityId": log.AuthorityID},
+		})
+	}
+	return events, nil
+}
+
+// riskBirdOrderEvents 订单创建和清理分别作为一条事件
+func riskBirdOrderEvents(q riskBirdTimelineQuery) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	var orders []system.RiskBirdSyntheticOrder
+	db := global.GVA_DB.Where("phone = ?", q.phone).Where("env IN ?", q.envs).
+		Where("(created_at >= ? AND created_at < ?) OR (cleaned_at >= ? AND cleaned_at < ?)", q.start, q.end, q.start, q.end)
+	if q.env != "" {
+		db = db.Where("env = ?", q.env)
+	}
+	if err = db.Order("created_at desc").Limit(riskBirdTimelineLimit).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		detail := map[string]interface{}{"orderNo": order.OrderNo, "amount": order.Amount, "flow": order.Flow, "status": order.Status}
+		if !order.CreatedAt.Before(q.start) && order.CreatedAt.Before(q.end) {
+			events = append(events, systemRes.RiskBirdTimelineEvent{
+				Time:    order.CreatedAt,
+				Env:     order.Env,
+				Source:  systemRes.RiskBirdTimelineOrder,
+				Action:  order.Kind,
+				Summary: fmt.Sprintf("%s流程创建订单%s，金额%s", riskBirdFlowName(order.Flow), order.OrderNo, order.Amount),
+				RefID:   int64(order.ID),
+				Detail:  detail,
+			})
+		}
+		if order.CleanedAt != nil && !order.CleanedAt.Before(q.start) && order.CleanedAt.Before(q.end) {
+			events = append(events, systemRes.RiskBirdTimelineEvent{
+				Time:    *order.CleanedAt,
+				Env:     order.Env,
+				Source:  systemRes.RiskBirdTimelineOrder,
+				Action:  "clean",
+				Summary: fmt.Sprintf("清理订单%s，方式%s", order.OrderNo, order.CleanAction),
+				RefID:   int64(order.ID),
+				Detail:  detail,
+			})
+		}
+	}
+	return events, nil
+}
+
+// riskBirdResetEvents 按执行结果中记录的环境筛选，早期未记录环境的结果按默认环境处理
+func riskBirdResetEvents(q riskBirdTimelineQuery) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	var runs []system.RiskBirdResetRun
+	err = global.GVA_DB.Where("started_at >= ? AND started_at < ?", q.start, q.end).
+		Where("results LIKE ?", "%"+q.phone+"%").
+		Order("started_at desc").Limit(riskBirdTimelineLimit).Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		for _, result := range run.Results {
+			env := result.Env
+			if env == "" {
+				env = config.RiskBirdDefaultEnv
+			}
+			if result.Phone != q.phone || !slices.Contains(q.envs, env) || q.env != "" && env != q.env {
+				continue
+			}
+			summary := fmt.Sprintf("重置计划%d恢复基准余额和积分", run.ScheduleID)
+			var errs []string
+			for _, msg := range []string{result.BalanceError, result.PointError} {
+				if msg != "" {
+					errs = append(errs, msg)
+				}
+			}
+			if len(errs) > 0 {
+				summary += "失败: " + strings.Join(errs, "; ")
+			}
+			events = append(events, systemRes.RiskBirdTimelineEvent{
+				Time:    run.StartedAt,
+				Env:     env,
+				Source:  systemRes.RiskBirdTimelineReset,
+				Action:  run.Trigger,
+				Summary: summary,
+				RefID:   int64(run.ID),
+				Detail:  result,
+			})
+		}
+	}
+	return events, nil
+}
+
+func riskBirdReconcileEvents(q riskBirdTimelineQuery) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	var list []system.RiskBirdReconciliation
+	if err = global.GVA_DB.Omit("mismatches").Scopes(q.scope("checked_at")).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, item := range list {
+		summary := fmt.Sprintf("余额%s，积分%d", item.APIBalance, item.APIPoints)
+		switch {
+		case item.Error != "":
+			summary = "核对失败: " + item.Error
+		case !item.Matched:
+			summary += fmt.Sprintf("，与数据库不一致(余额%s，积分%d)", item.DBBalance, item.DBPoints)
+		}
+		events = append(events, systemRes.RiskBirdTimelineEvent{
+			Time:    item.CheckedAt,
+			Env:     item.Env,
+			Source:  systemRes.RiskBirdTimelineReconcile,
+			Action:  item.Trigger,
+			Summary: summary,
+			RefID:   int64(item.ID),
+			Detail:  map[string]interface{}{"matched": item.Matched, "apiBalance": item.APIBalance, "dbBalance": item.DBBalance, "apiPoints": item.APIPoints, "dbPoints": item.DBPoints},
+		})
+	}
+	return events, nil
+}
+
+// riskBirdConfigRestoreEvents 价格配置恢复影响环境内全部账号，只返回账号所在环境的记录
+func riskBirdConfigRestoreEvents(q riskBirdTimelineQuery, envs []string) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	if len(envs) == 0 {
+		return nil, nil
+	}
+	var list []system.RiskBirdConfigRestore
+	err = global.GVA_DB.Omit("changes").Where("env IN ?", envs).
+		Where("created_at >= ? AND created_at < ?", q.start, q.end).
+		Order("created_at desc").Limit(riskBirdTimelineLimit).Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list {
+		summary := fmt.Sprintf("价格配置恢复到基线%d", item.BaselineID)
+		if item.Error != "" {
+			summary += "失败: " + item.Error
+		}
+		events = append(events, systemRes.RiskBirdTimelineEvent{
+			Time:       item.CreatedAt,
+			Env:        item.Env,
+			Source:     systemRes.RiskBirdTimelineConfigRestore,
+			Action:     item.Status,
+			Summary:    summary,
+			RefID:      int64(item.ID),
+			OperatorID: item.UserID,
+		})
+	}
+	return events, nil
+}
+
+// riskBirdPointAcquisitionEvents 读取 RiskBird 数据库中该用户在时间范围内创建的积分获取记录
+func riskBirdPointAcquisitionEvents(q riskBirdTimelineQuery, envName string) (events []systemRes.RiskBirdTimelineEvent, err error) {
+	env, err := riskBirdEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	db, err := newRiskBirdDB(env)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	userID := q.account.UserID
+	if userID == 0 || q.account.Env != env.Name {
+		userID, _, err = request.FindUser(db, riskBirdUserTable(env.Impersonation), 0, q.phone)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	list, err := request.ListPointAcquisitionsBetween(db, userID, q.start, q.end, riskBirdTimelineLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list {
+		summary := fmt.Sprintf("获得%d积分，剩余%d", item.Points, item.LeftPoints)
+		if item.AuditStatus == request.PointAcquisitionAuditPending {
+			summary += "，待审核"
+		}
+		events = append(events, systemRes.RiskBirdTimelineEvent{
+			Time:    item.CreateTime,
+			Env:     env.Name,
+			Source:  systemRes.RiskBirdTimelinePointAcquisition,
+			Action:  "acquire",
+			Summary: summary,
+			RefID:   item.ID,
+			Detail:  item,
+		})
+	}
+	return events, nil
+}
+
+// riskBirdTimelineEnvs 指定环境时只查该环境，否则取账号所在环境和本地记录中出现过的环境
+func riskBirdTimelineEnvs(q riskBirdTimelineQuery, events []systemRes.RiskBirdTimelineEvent) []string {
+	if q.env != "" {
+		return []string{q.env}
+	}
+	var envs []string
+	if q.account.Env != "" {
+		envs = append(envs, q.account.Env)
+	}
+	for _, event := range events {
+		if event.Env != "" && !slices.Contains(envs, event.Env) {
+			envs = append(envs, event.Env)
+		}
+	}
+	return envs
+}
+
+// fillRiskBirdOperatorNames 填充操作人昵称
+func fillRiskBirdOperatorNames(events []systemRes.RiskBirdTimelineEvent) {
+	var ids []uint
+	for _, event := range events {
+		if event.OperatorID != 0 && !slices.Contains(ids, event.OperatorID) {
+			ids = append(ids, event.OperatorID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	var users []system.SysUser
+	if err := global.GVA_DB.Select("id", "nick_name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		global.GVA_LOG.Error("查询操作人失败", zap.Error(err))
+		return
+	}
+	names := make(map[uint]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.NickName
+	}
+	for i := range events {
+		events[i].OperatorName = names[events[i].OperatorID]
+	}
+}
+
+// riskBirdFlowName 流程的中文名称
+func riskBirdFlowName(flow string) string {
+	switch flow {
+	case system.RiskBirdFlowBalance:
+		return "修改余额"
+	case system.RiskBirdFlowPoint:
+		return "修改积分"
+	}
+	return flow
+}
@@ -0,0 +1,66 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/config"
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRiskBirdResetEventsEnv(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&system.RiskBirdResetRun{}); err != nil {
+		t.Fatal(err)
+	}
+	oldDB := global.GVA_DB
+	global.GVA_DB = db
+	defer func() { global.GVA_DB = oldDB }()
+
+	now := time.Now()
+	// 早期记录没有环境，按默认环境处理
+	runs := []system.RiskBirdResetRun{
+		{ScheduleID: 1, StartedAt: now, Results: []system.RiskBirdResetAccountResult{{Env: "staging", Phone: "13800000000"}}},
+		{ScheduleID: 2, StartedAt: now.Add(-time.Minute), Results: []system.RiskBirdResetAccountResult{{Phone: "13800000000"}}},
+	}
+	if err = db.Create(&runs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		env  string
+		envs []string
+		want []string
+	}{
+		{"全部环境", "", []string{config.RiskBirdDefaultEnv, "staging"}, []string{"staging", config.RiskBirdDefaultEnv}},
+		{"指定环境", "staging", []string{config.RiskBirdDefaultEnv, "staging"}, []string{"staging"}},
+		{"无权查看的环境", "", []string{config.RiskBirdDefaultEnv}, []string{config.RiskBirdDefaultEnv}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q := riskBirdTimelineQuery{phone: "13800000000", env: tt.env, envs: tt.envs, start: now.Add(-time.Hour), end: now.Add(time.Hour)}
+			events, err := riskBirdResetEvents(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, event := range events {
+				got = append(got, event.Env)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("riskBirdResetEvents() envs = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("riskBirdResetEvents() envs = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -5,175 +5,172 @@ import (
 	"fmt"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
-	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
-	"go.uber.org/zap"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 )
 
 type UserBalanceService struct{}
 
 var UserBalanceServiceApp = new(UserBalanceService)
 
-// ModifyUserBalance 修改外部系统用户余额
+// ModifyUserBalance 同步修改外部系统用户余额，和异步流程一样保存流程执行记录
 func (s *UserBalanceService) ModifyUserBalance(req systemReq.ModifyUserBalance) error {
-	// 验证金额
-	if req.RechargeAmount < 0 || req.GiftAmount < 0 {
-		return errors.New("修改后的金额不能为负数")
+	if err := checkModifyUserBalance(req); err != nil {
+		return err
 	}
-
-	// 连接 RiskBird 数据库
-	riskBirdDB, err := request.NewRiskBirdDB(request.RiskBirdDBConfig{
-		Host:     global.GVA_CONFIG.RiskBird.DB.Host,
-		Port:     global.GVA_CONFIG.RiskBird.DB.Port,
-		User:     global.GVA_CONFIG.RiskBird.DB.User,
-		Password: global.GVA_CONFIG.RiskBird.DB.Password,
-		Database: global.GVA_CONFIG.RiskBird.DB.Database,
-	})
+	resume, err := riskBirdResumeCheckpoint(system.RiskBirdFlowBalance, req.Env, req.ResumeRunID)
 	if err != nil {
-		global.GVA_LOG.Error("连接RiskBird数据库失败", zap.Error(err))
-		return errors.New("连接数据库失败")
+		return err
 	}
-	defer riskBirdDB.Close()
-
-	// 创建 RiskBird API 客户端
-	riskBirdClient := request.NewRiskBirdAPIClient(global.GVA_CONFIG.RiskBird.API.BaseUrl)
+	return RiskBirdFlowServiceApp.runSync(system.RiskBirdFlowBalance, req.Env, req.Phone, req.OperatorID, req.ResumeRunID, func(progress *riskBirdProgress) (riskBirdPipelineResult, error) {
+		return s.modifyUserBalance(req, progress, riskBirdPipelineOptions{Resume: resume})
+	})
+}
 
-	// 1. 用户登录
-	loginResp, err := riskBirdClient.Login(req.Phone, req.Password)
-	if err != nil {
-		global.GVA_LOG.Error("RiskBird用户登录失败",
-			zap.String("phone", req.Phone),
-			zap.String("error_message", err.Error()))
-		return errors.New("请输入正确的手机号和密码")
+// PlanModifyUserBalance 预演修改余额，只执行登录和查询余额，返回将要执行的步骤和预计余额
+func (s *UserBalanceService) PlanModifyUserBalance(req systemReq.ModifyUserBalance) (plan systemRes.RiskBirdFlowPlan, err error) {
+	if err = checkModifyUserBalance(req); err != nil {
+		return plan, err
 	}
-
-	token := loginResp["token"].(string)
-	global.GVA_LOG.Info("RiskBird用户登录成功", zap.String("phone", req.Phone))
-	fmt.Println(token)
-
-	// 2. 获取当前余额
-	currentBalance, err := riskBirdClient.GetBalance(token)
+	resume, err := riskBirdResumeCheckpoint(system.RiskBirdFlowBalance, req.Env, req.ResumeRunID)
 	if err != nil {
-		global.GVA_LOG.Error("获取用户余额失败", zap.Error(err))
-		return errors.New("获取用户余额失败")
+		return plan, err
 	}
-
-	// 3. 如果余额大于0，先花光当前余额
-	if currentBalance > 0 {
-		// 3.1 修改企业信用报告导出价格为当前余额（使其能被花光）
-		err = request.UpdateProductCfg(riskBirdDB, 12, currentBalance)
-		if err != nil {
-			global.GVA_LOG.Error("修改产品配置失败", zap.Error(err))
-			return errors.New("修改产品配置失败")
-		}
-
-		// 3.2 创建企业信用报告预订单
-		preOrderPayload := map[string]interface{}{
-			"productCode":     "paid_report",
-			"productNum":      "2",
-			"sendEmail":       "",
-			"totalAmount":     currentBalance,
-			"transactionType": "C",
-			"tradeType":       "JSAPI",
-			"selectConditionData": map[string]interface{}{
-				"entName":     "乐视网信息技术（北京）股份有限公司",
-				"entid":       "7jShe5V5mqx",
-				"fileType":    "pdf,word",
-				"groupIdList": "9,2,5,6,7,8,",
-			},
-		}
-
-		preOrderNo, err := riskBirdClient.CreatePreOrder(token, preOrderPayload)
-		if err != nil {
-			global.GVA_LOG.Error("创建企业信用报告预订单失败", zap.Error(err))
-			return errors.New("创建企业信用报告预订单失败")
-		}
-
-		// 3.3 创建企业信用报告订单
-		reportOrderPayload := map[string]interface{}{
-			"balanceAmount":     currentBalance,
-			"payAmount":         "0.00",
-			"payMethod":         "balance",
-			"productNum":        "2",
-			"totalAmount":       currentBalance,
-			"tradeType":         "JSAPI",
-			"unifiedPreOrderNo": preOrderNo,
-		}
-
-		reportOrderNo, err := riskBirdClient.CreateOrder(token, reportOrderPayload)
-		if err != nil {
-			global.GVA_LOG.Error("创建企业信用报告订单失败", zap.Error(err))
-			return errors.New("创建企业信用报告订单失败")
-		}
-
-		// 3.4 更新报告订单状态为成功
-		err = riskBirdClient.UpdateOrder(token, reportOrderNo, "success")
-		if err != nil {
-			global.GVA_LOG.Error("更新企业信用报告订单失败", zap.Error(err))
-			return err
-		}
-
-		global.GVA_LOG.Info(fmt.Sprintf("已花光用户当前余额，总金额：%.2f元", currentBalance))
-
-		// 3.5 恢复企业信用报告导出价格为默认值5元
-		err = request.UpdateProductCfg(riskBirdDB, 12, 5)
-		if err != nil {
-			global.GVA_LOG.Error("恢复产品配置失败", zap.Error(err))
-			return errors.New("恢复产品配置失败")
-		}
+	result, err := s.modifyUserBalance(req, nil, riskBirdPipelineOptions{DryRun: true, Resume: resume})
+	if err != nil {
+		return plan, err
 	}
+	plan = riskBirdFlowPlan(system.RiskBirdFlowBalance, req.Env, result)
+	// 先花光当前余额，再充值指定金额和赠送金额
+	current, expected := result.Vars.Balance, req.RechargeAmount+req.GiftAmount
+	plan.CurrentBalance, plan.ExpectedBalance = &current, &expected
+	return plan, nil
+}
 
-	// 4. 充值指定金额
-	// 4.1 修改充值套餐金额
-	err = request.UpdateRechargeProduct(riskBirdDB, 5, req.RechargeAmount, req.GiftAmount)
+// ModifyAccountBalance 使用账号库中登记的账号修改余额
+func (s *UserBalanceService) ModifyAccountBalance(req systemReq.ModifyRiskBirdAccountBalance) error {
+	account, err := getRiskBirdAccount(req.AccountID)
 	if err != nil {
-		global.GVA_LOG.Error("修改充值套餐失败", zap.Error(err))
-		return errors.New("修改充值套餐失败")
+		return err
 	}
+	return s.ModifyUserBalance(systemReq.ModifyUserBalance{
+		Env:            account.Env,
+		Phone:          account.Phone,
+		Password:       account.Password,
+		SmsLogin:       account.Password == "",
+		RechargeAmount: req.RechargeAmount,
+		GiftAmount:     req.GiftAmount,
+
+		OperatorAuthorityID: req.OperatorAuthorityID,
+	})
+}
 
-	// 4.2 创建充值预订单
-	rechargePreOrderPayload := map[string]any{
-		"productCode":     "",
-		"productNum":      1,
-		"totalAmount":     req.RechargeAmount,
-		"transactionType": "P",
-		"selectConditionData": map[string]any{
-			"productId": "5",
-		},
+// checkModifyUserBalance 校验修改余额请求
+func checkModifyUserBalance(req systemReq.ModifyUserBalance) error {
+	if req.RechargeAmount < 0 || req.GiftAmount < 0 {
+		return errors.New("修改后的金额不能为负数")
 	}
-
-	rechargePreOrderNo, err := riskBirdClient.CreatePreOrder(token, rechargePreOrderPayload)
-	if err != nil {
-		global.GVA_LOG.Error("创建充值预订单失败", zap.Error(err))
-		return errors.New("创建充值预订单失败")
+	if err := checkRiskBirdScope(req.OperatorAuthorityID, req.Env, system.RiskBirdOperationBalance); err != nil {
+		return err
 	}
+	return checkRiskBirdLogin(req.Env, balanceLogin(req))
+}
 
-	// 4.3 创建充值订单
-	rechargeOrderPayload := map[string]interface{}{
-		"balanceAmount":     0,
-		"payAmount":         req.RechargeAmount,
-		"payMethod":         "webpay",
-		"productNum":        1,
-		"productCode":       "",
-		"totalAmount":       req.RechargeAmount,
-		"tradeType":         "JSAPI",
-		"unifiedPreOrderNo": rechargePreOrderNo,
+func balanceLogin(req systemReq.ModifyUserBalance) riskBirdLogin {
+	return riskBirdLogin{
+		Flow:         system.RiskBirdFlowBalance,
+		Phone:        req.Phone,
+		Password:     req.Password,
+		SmsLogin:     req.SmsLogin,
+		Impersonate:  req.Impersonate,
+		TargetUserID: req.TargetUserID,
+		OperatorID:   req.OperatorID,
+		AuthorityID:  req.OperatorAuthorityID,
 	}
+}
 
-	rechargeOrderNo, err := riskBirdClient.CreateOrder(token, rechargeOrderPayload)
-	if err != nil {
-		global.GVA_LOG.Error("创建充值订单失败", zap.Error(err))
-		return errors.New("创建充值订单失败")
+// riskBirdBalancePipeline 修改余额流程：余额大于0时先购买企业信用报告花光余额，再按指定金额充值
+func riskBirdBalancePipeline(req systemReq.ModifyUserBalance) riskBirdPipeline {
+	login := balanceLogin(req)
+	hasBalance := func(st *riskBirdFlowState) bool { return st.Vars.Balance > 0 }
+	balance := func(st *riskBirdFlowState) common.Money { return st.Vars.Balance }
+	recharge := func(*riskBirdFlowState) common.Money { return req.RechargeAmount }
+
+	reportPreOrder := riskBirdCreateOrderStep("创建企业信用报告预订单", system.RiskBirdOrderKindReportPreOrder, balance,
+		func(st *riskBirdFlowState) map[string]interface{} {
+			return riskBirdReportPreOrderPayload(st.Vars.Balance)
+		})
+	reportOrder := riskBirdCreateOrderStep("创建企业信用报告订单", system.RiskBirdOrderKindReportOrder, balance,
+		func(st *riskBirdFlowState) map[string]interface{} {
+			return riskBirdReportOrderPayload(st.Vars.Balance, st.Vars.Orders[system.RiskBirdOrderKindReportPreOrder], true)
+		})
+	reportPrice := riskBirdReportPriceStep(balance)
+	updateReport := riskBirdUpdateOrderStep("更新企业信用报告订单状态", system.RiskBirdOrderKindReportOrder)
+	restorePrice := riskBirdRestoreReportPriceStep()
+	for _, unit := range []*riskBirdStepUnit{&reportPrice, &reportPreOrder, &reportOrder, &updateReport, &restorePrice} {
+		unit.When = hasBalance
 	}
 
-	// 4.4 更新充值订单状态为成功
-	err = riskBirdClient.UpdateOrder(token, rechargeOrderNo, "success")
-	if err != nil {
-		global.GVA_LOG.Error("更新充值订单失败", zap.Error(err))
-		return err
+	return riskBirdPipeline{
+		Flow: system.RiskBirdFlowBalance,
+		Steps: []riskBirdStepUnit{
+			riskBirdLoginStep(login),
+			riskBirdBalanceLookupStep(login),
+			// 余额大于0时修改报告价格为当前余额并下单，花光余额
+			reportPrice,
+			reportPreOrder,
+			reportOrder,
+			updateReport,
+			restorePrice,
+			// 充值指定金额
+			riskBirdRechargeProductStep(req.RechargeAmount, req.GiftAmount),
+			riskBirdCreateOrderStep("创建充值预订单", system.RiskBirdOrderKindRechargePreOrder, recharge,
+				func(*riskBirdFlowState) map[string]interface{} {
+					return map[string]interface{}{
+						"productCode":     "",
+						"productNum":      1,
+						"totalAmount":     req.RechargeAmount,
+						"transactionType": "P",
+						"selectConditionData": map[string]interface{}{
+							"productId": "5",
+						},
+					}
+				}),
+			riskBirdCreateOrderStep("创建充值订单", system.RiskBirdOrderKindRechargeOrder, recharge,
+				func(st *riskBirdFlowState) map[string]interface{} {
+					return map[string]interface{}{
+						"balanceAmount":     0,
+						"payAmount":         req.RechargeAmount,
+						"payMethod":         "webpay",
+						"productNum":        1,
+						"productCode":       "",
+						"totalAmount":       req.RechargeAmount,
+						"tradeType":         "JSAPI",
+						"unifiedPreOrderNo": st.Vars.Orders[system.RiskBirdOrderKindRechargePreOrder],
+					}
+				}),
+			riskBirdUpdateOrderStep("更新充值订单状态", system.RiskBirdOrderKindRechargeOrder),
+		},
 	}
+}
 
-	global.GVA_LOG.Info(fmt.Sprintf("已成功为用户充值，充值金额：%.2f元，赠送金额：%.2f元", req.RechargeAmount, req.GiftAmount))
+// modifyUserBalance 修改余额流程，progress 不为 nil 时记录每个步骤的进度
+func (s *UserBalanceService) modifyUserBalance(req systemReq.ModifyUserBalance, progress *riskBirdProgress, opts riskBirdPipelineOptions) (riskBirdPipelineResult, error) {
+	result, err := runRiskBirdFlow(riskBirdBalancePipeline(req), req.Env, balanceLogin(req), progress, opts)
+	if err == nil && !opts.DryRun {
+		global.GVA_LOG.Info(fmt.Sprintf("已成功为用户充值，充值金额：%s元，赠送金额：%s元", req.RechargeAmount, req.GiftAmount))
+	}
+	return result, err
+}
 
-	return nil
+// riskBirdUserID 从登录响应中读取 RiskBird 用户ID，读取不到时返回0
+func riskBirdUserID(loginResp map[string]interface{}) int64 {
+	user, ok := loginResp["user"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	id, _ := user["id"].(float64)
+	return int64(id)
 }
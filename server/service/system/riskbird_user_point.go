@@ -6,196 +6,243 @@ import (
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	systemReq "github.com/flipped-aurora/gin-vue-admin/server/model/system/request"
+	systemRes "github.com/flipped-aurora/gin-vue-admin/server/model/system/response"
 	"github.com/flipped-aurora/gin-vue-admin/server/utils/request"
-	"go.uber.org/zap"
 )
 
+// riskBirdPointsPerYuan 购买企业信用报告时每1元获得的积分
+const riskBirdPointsPerYuan = 5
+
+// riskBirdPointValidity 直接新增的积分记录有效期
+const riskBirdPointValidity = 365 * 24 * time.Hour
+
 type UserPointService struct{}
 
 var UserPointServiceApp = new(UserPointService)
 
-// ModifyUserPoint 修改外部系统用户积分
+// ModifyUserPoint 同步修改外部系统用户积分，和异步流程一样保存流程执行记录
 func (s *UserPointService) ModifyUserPoint(req systemReq.ModifyUserPoint) error {
-	// 验证积分
-	if req.PointAmount < 0 {
-		return errors.New("修改后的积分不能为负数")
-	}
-	if req.PointAmount%5 != 0 {
-		return errors.New("修改后的积分必须是5的倍数")
+	if err := checkModifyUserPoint(&req); err != nil {
+		return err
 	}
-
-	// 连接 RiskBird 数据库
-	riskBirdDB, err := request.NewRiskBirdDB(request.RiskBirdDBConfig{
-		Host:     global.GVA_CONFIG.RiskBird.DB.Host,
-		Port:     global.GVA_CONFIG.RiskBird.DB.Port,
-		User:     global.GVA_CONFIG.RiskBird.DB.User,
-		Password: global.GVA_CONFIG.RiskBird.DB.Password,
-		Database: global.GVA_CONFIG.RiskBird.DB.Database,
-	})
+	resume, err := riskBirdResumeCheckpoint(system.RiskBirdFlowPoint, req.Env, req.ResumeRunID)
 	if err != nil {
-		global.GVA_LOG.Error("连接RiskBird数据库失败", zap.Error(err))
-		return errors.New("连接数据库失败")
+		return err
 	}
-	defer riskBirdDB.Close()
-
-	// 创建 RiskBird API 客户端
-	riskBirdClient := request.NewRiskBirdAPIClient(global.GVA_CONFIG.RiskBird.API.BaseUrl)
+	return RiskBirdFlowServiceApp.runSync(system.RiskBirdFlowPoint, req.Env, req.Phone, req.OperatorID, req.ResumeRunID, func(progress *riskBirdProgress) (riskBirdPipelineResult, error) {
+		return s.modifyUserPoint(req, progress, riskBirdPipelineOptions{Resume: resume})
+	})
+}
 
-	// 1. 用户登录
-	loginResp, err := riskBirdClient.Login(req.Phone, req.Password)
-	if err != nil {
-		global.GVA_LOG.Error("RiskBird用户登录失败",
-			zap.String("phone", req.Phone),
-			zap.String("error_message", err.Error()))
-		return errors.New("请输入正确的手机号和密码")
+// PlanModifyUserPoint 预演修改积分，只执行登录和查询积分，返回将要执行的步骤和预计积分
+func (s *UserPointService) PlanModifyUserPoint(req systemReq.ModifyUserPoint) (plan systemRes.RiskBirdFlowPlan, err error) {
+	if err = checkModifyUserPoint(&req); err != nil {
+		return plan, err
 	}
-
-	token := loginResp["token"].(string)
-	userID := int64(loginResp["user"].(map[string]interface{})["id"].(float64))
-
-	global.GVA_LOG.Info("RiskBird用户登录成功", zap.String("phone", req.Phone))
-
-	// 2. 获取用户当前可用积分
-	availablePoints, err := riskBirdClient.GetPointOverview(token)
+	resume, err := riskBirdResumeCheckpoint(system.RiskBirdFlowPoint, req.Env, req.ResumeRunID)
 	if err != nil {
-		global.GVA_LOG.Error("获取用户积分信息失败", zap.Error(err))
-		return errors.New("获取用户积分信息失败")
+		return plan, err
 	}
-
-	// 3. 如果用户有可用积分，先使其失效
-	if availablePoints > 0 {
-		// 设置积分失效时间为昨天
-		expireTime := time.Now().AddDate(0, 0, -1)
-
-		err = request.UpdatePointExpireTime(riskBirdDB, userID, expireTime)
-		if err != nil {
-			global.GVA_LOG.Error("修改积分失效时间失败", zap.Error(err))
-			return errors.New("修改积分失效时间失败")
-		}
-
-		// 调用积分失效定时任务接口使积分失效
-		err = riskBirdClient.ExpirePoint(token)
-		if err != nil {
-			global.GVA_LOG.Error("调用积分失效定时任务接口失败", zap.Error(err))
-			return errors.New("调用积分失效定时任务接口失败")
-		}
-
-		global.GVA_LOG.Info(fmt.Sprintf("已使用户可用积分失效，失效积分数：%d分", availablePoints))
-	}
-
-	// 4. 如果修改后的积分为0，直接返回
-	if req.PointAmount == 0 {
-		global.GVA_LOG.Info(fmt.Sprintf("用户%s的积分已修改为0分", req.Phone))
-		return nil
-	}
-
-	// 5. 计算支付金额（每5积分对应1元）
-	payAmount := float64(req.PointAmount) / 5
-
-	// 设置积分获取时间为昨天
-	pointTime := time.Now().AddDate(0, 0, -1)
-
-	// 5.1 修改企业信用报告导出价格为支付金额
-	err = request.UpdateProductCfg(riskBirdDB, 12, payAmount)
+	result, err := s.modifyUserPoint(req, nil, riskBirdPipelineOptions{DryRun: true, Resume: resume})
 	if err != nil {
-		global.GVA_LOG.Error("修改产品配置失败", zap.Error(err))
-		return errors.New("修改产品配置失败")
-	}
-
-	// 5.2 创建企业信用报告预订单
-	preOrderPayload := map[string]interface{}{
-		"productCode":     "paid_report",
-		"productNum":      "2",
-		"sendEmail":       "",
-		"totalAmount":     payAmount,
-		"transactionType": "C",
-		"tradeType":       "JSAPI",
-		"selectConditionData": map[string]interface{}{
-			"entName":     "乐视网信息技术（北京）股份有限公司",
-			"entid":       "7jShe5V5mqx",
-			"fileType":    "pdf,word",
-			"groupIdList": "9,2,5,6,7,8,",
-		},
+		return plan, err
 	}
+	plan = riskBirdFlowPlan(system.RiskBirdFlowPoint, req.Env, result)
+	current, expected := result.Vars.Points, req.PointAmount
+	plan.CurrentPoints, plan.ExpectedPoints = &current, &expected
+	return plan, nil
+}
 
-	preOrderNo, err := riskBirdClient.CreatePreOrder(token, preOrderPayload)
+// ModifyAccountPoint 使用账号库中登记的账号修改积分
+func (s *UserPointService) ModifyAccountPoint(req systemReq.ModifyRiskBirdAccountPoint) error {
+	account, err := getRiskBirdAccount(req.AccountID)
 	if err != nil {
-		global.GVA_LOG.Error("创建企业信用报告预订单失败", zap.Error(err))
-		return errors.New("创建企业信用报告预订单失败")
+		return err
 	}
+	return s.ModifyUserPoint(systemReq.ModifyUserPoint{
+		Env:         account.Env,
+		Phone:       account.Phone,
+		Password:    account.Password,
+		SmsLogin:    account.Password == "",
+		PointAmount: req.PointAmount,
+		Strategy:    req.Strategy,
 
-	// 5.3 创建企业信用报告订单
-	reportOrderPayload := map[string]interface{}{
-		"balanceAmount":     0,
-		"payAmount":         payAmount,
-		"payMethod":         "webpay",
-		"productNum":        "2",
-		"totalAmount":       payAmount,
-		"tradeType":         "JSAPI",
-		"unifiedPreOrderNo": preOrderNo,
-	}
+		OperatorAuthorityID: req.OperatorAuthorityID,
+	})
+}
 
-	reportOrderNo, err := riskBirdClient.CreateOrder(token, reportOrderPayload)
-	if err != nil {
-		global.GVA_LOG.Error("创建企业信用报告订单失败", zap.Error(err))
-		return errors.New("创建企业信用报告订单失败")
+// checkModifyUserPoint 校验修改积分请求，未指定修改方式时使用下单方式
+func checkModifyUserPoint(req *systemReq.ModifyUserPoint) error {
+	if req.PointAmount < 0 {
+		return errors.New("修改后的积分不能为负数")
 	}
-
-	// 5.4 更新报告订单状态为成功
-	err = riskBirdClient.UpdateOrder(token, reportOrderNo, "success")
-	if err != nil {
-		global.GVA_LOG.Error("更新企业信用报告订单失败", zap.Error(err))
+	if err := checkRiskBirdScope(req.OperatorAuthorityID, req.Env, system.RiskBirdOperationPoint); err != nil {
 		return err
 	}
-
-	global.GVA_LOG.Info(fmt.Sprintf("已完成企业信用报告导出支付，支付金额：%.2f元，待入账积分：%d分", payAmount, req.PointAmount))
-
-	// 5.5 恢复企业信用报告导出价格为默认值5元
-	err = request.UpdateProductCfg(riskBirdDB, 12, 5)
-	if err != nil {
-		global.GVA_LOG.Error("恢复产品配置失败", zap.Error(err))
-		return errors.New("恢复产品配置失败")
+	if req.Strategy == "" {
+		req.Strategy = systemReq.ModifyUserPointStrategyOrder
 	}
-
-	// 6. 等待5秒，确保积分获取记录已创建
-	time.Sleep(5 * time.Second)
-
-	// 7. 查询最新的积分获取记录ID并修改其发生时间
-	pointAcquisitionID, err := request.GetLatestPointAcquisitionID(riskBirdDB, userID)
-	if err != nil {
-		global.GVA_LOG.Error("查询积分获取记录失败", zap.Error(err))
-		return errors.New("查询积分获取记录失败")
+	switch req.Strategy {
+	case systemReq.ModifyUserPointStrategyOrder:
+		if req.PointAmount%riskBirdPointsPerYuan != 0 {
+			return errors.New("修改后的积分必须是5的倍数")
+		}
+		// 下单获得的积分需要后台审核，支付前确认配置了管理员账号
+		if req.PointAmount > 0 {
+			env, err := riskBirdEnv(req.Env)
+			if err != nil {
+				return err
+			}
+			if err = checkRiskBirdAdminAccount(env); err != nil {
+				return err
+			}
+		}
+	case systemReq.ModifyUserPointStrategyDirect:
+	default:
+		return fmt.Errorf("不支持的积分修改方式: %s", req.Strategy)
 	}
+	return checkRiskBirdLogin(req.Env, pointLogin(*req))
+}
 
-	err = request.UpdatePointAcquisitionTime(riskBirdDB, pointAcquisitionID, pointTime)
-	if err != nil {
-		global.GVA_LOG.Error("修改积分获取时间失败", zap.Error(err))
-		return errors.New("修改积分获取时间失败")
+func pointLogin(req systemReq.ModifyUserPoint) riskBirdLogin {
+	return riskBirdLogin{
+		Flow:         system.RiskBirdFlowPoint,
+		Phone:        req.Phone,
+		Password:     req.Password,
+		SmsLogin:     req.SmsLogin,
+		Impersonate:  req.Impersonate,
+		TargetUserID: req.TargetUserID,
+		OperatorID:   req.OperatorID,
+		AuthorityID:  req.OperatorAuthorityID,
 	}
+}
 
-	// 8. 调用积分审核日度定时任务
-	err = riskBirdClient.PointAuditDay(token)
-	if err != nil {
-		global.GVA_LOG.Error("调用积分日审核定时任务接口失败", zap.Error(err))
-		return errors.New("调用积分日审核定时任务接口失败")
+// riskBirdPointPipeline 修改积分流程。下单方式先使现有积分失效，再通过在线支付购买企业信用报告获得积分并审核；
+// 直接方式新增或扣减 point_acquisition 记录，使可用积分等于目标积分
+func riskBirdPointPipeline(req systemReq.ModifyUserPoint) riskBirdPipeline {
+	login := pointLogin(req)
+	steps := []riskBirdStepUnit{
+		riskBirdLoginStep(login),
+		riskBirdPointLookupStep(login),
 	}
-
-	// 9. 登录后台管理系统进行积分审核
-	adminToken, err := riskBirdClient.AdminLogin("zengdong", "zengdong@123")
-	if err != nil {
-		global.GVA_LOG.Error("管理员登录失败", zap.Error(err))
-		return errors.New("管理员登录失败")
+	if req.Strategy == systemReq.ModifyUserPointStrategyDirect {
+		return riskBirdPipeline{Flow: system.RiskBirdFlowPoint, Steps: append(steps, riskBirdDirectPointSteps(req.PointAmount)...)}
 	}
 
-	// 10. 对当前用户进行积分审核
-	err = riskBirdClient.AuditPointAcquisition(adminToken, pointAcquisitionID)
-	if err != nil {
-		global.GVA_LOG.Error("积分审核失败", zap.Error(err))
-		return errors.New("积分审核失败")
-	}
+	hasPoints := func(st *riskBirdFlowState) bool { return st.Vars.Points > 0 }
+	steps = append(steps, riskBirdExpirePointsSteps(hasPoints)...)
+	if req.PointAmount == 0 {
+		return riskBirdPipeline{Flow: system.RiskBirdFlowPoint, Steps: steps}
+	}
+
+	// 每5积分对应1元
+	payAmount := common.Money(req.PointAmount * common.MoneyScale / riskBirdPointsPerYuan)
+	pay := func(*riskBirdFlowState) common.Money { return payAmount }
+	steps = append(steps,
+		riskBirdReportPriceStep(pay),
+		riskBirdCreateOrderStep("创建企业信用报告预订单", system.RiskBirdOrderKindReportPreOrder, pay,
+			func(*riskBirdFlowState) map[string]interface{} {
+				return riskBirdReportPreOrderPayload(payAmount)
+			}),
+		riskBirdCreateOrderStep("创建企业信用报告订单", system.RiskBirdOrderKindReportOrder, pay,
+			func(st *riskBirdFlowState) map[string]interface{} {
+				return riskBirdReportOrderPayload(payAmount, st.Vars.Orders[system.RiskBirdOrderKindReportPreOrder], false)
+			}),
+		riskBirdUpdateOrderStep("更新企业信用报告订单状态", system.RiskBirdOrderKindReportOrder),
+		riskBirdRestoreReportPriceStep(),
+		// 等待 RiskBird 生成积分获取记录后改为昨天获得，再通过日审核和后台审核入账
+		riskBirdWaitStep("等待积分获取记录创建", 5*time.Second, nil),
+		riskBirdPointTimeStep(nil),
+		riskBirdPointAuditDayStep(nil),
+		riskBirdAdminLoginStep(nil),
+		riskBirdAuditPointStep(nil),
+	)
+	return riskBirdPipeline{Flow: system.RiskBirdFlowPoint, Steps: steps}
+}
 
-	global.GVA_LOG.Info(fmt.Sprintf("用户%s的积分已修改为%d分，移动端用户请重新登录后查看最新积分", req.Phone, req.PointAmount))
+// riskBirdDirectPointSteps 直接修改积分记录的步骤，最后通过积分概览接口确认修改结果
+func riskBirdDirectPointSteps(target int64) []riskBirdStepUnit {
+	delta := func(st *riskBirdFlowState) int64 { return target - st.Vars.Points }
+	return []riskBirdStepUnit{
+		{
+			Name:  "新增积分获取记录",
+			When:  func(st *riskBirdFlowState) bool { return delta(st) > 0 },
+			Error: "新增积分获取记录失败",
+			Input: func(st *riskBirdFlowState) map[string]interface{} {
+				// 积分获取时间设置为昨天，审核状态直接为通过
+				pointTime := time.Now().AddDate(0, 0, -1)
+				expireTime := pointTime.Add(riskBirdPointValidity)
+				return map[string]interface{}{
+					"points":     delta(st),
+					"pointTime":  pointTime,
+					"expireTime": expireTime,
+					"insert": map[string]interface{}{
+						"table": "point_acquisition",
+						"values": map[string]interface{}{
+							"user_id": st.Vars.UserID, "points": delta(st), "left_points": delta(st),
+							"audit_status": request.PointAcquisitionAuditApproved, "point_time": pointTime, "expire_time": expireTime,
+						},
+					},
+				}
+			},
+			Run: func(st *riskBirdFlowState, input map[string]interface{}) (map[string]interface{}, error) {
+				id, err := request.InsertPointAcquisition(st.DB, st.Vars.UserID, delta(st), input["pointTime"].(time.Time), input["expireTime"].(time.Time))
+				return map[string]interface{}{"pointAcquisitionId": id}, err
+			},
+		},
+		{
+			Name: "扣减剩余积分",
+			When: func(st *riskBirdFlowState) bool { return delta(st) < 0 },
+			Input: func(st *riskBirdFlowState) map[string]interface{} {
+				return map[string]interface{}{
+					"points": -delta(st),
+					"update": riskBirdRowUpdate("point_acquisition", map[string]interface{}{"user_id": st.Vars.UserID, "left_points >": 0, "expire_time >": time.Now()},
+						map[string]interface{}{"left_points": fmt.Sprintf("按失效时间从早到晚共扣减%d分", -delta(st))}),
+				}
+			},
+			Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+				deducted, err := request.DeductLeftPoints(st.DB, st.Vars.UserID, -delta(st))
+				output := map[string]interface{}{"deducted": deducted}
+				if err != nil {
+					return output, errors.New("扣减用户剩余积分失败")
+				}
+				if deducted != -delta(st) {
+					return output, fmt.Errorf("用户剩余积分记录不足，需扣减%d分，实际扣减%d分", -delta(st), deducted)
+				}
+				return output, nil
+			},
+		},
+		{
+			Name: "校验修改后积分",
+			Input: func(*riskBirdFlowState) map[string]interface{} {
+				return map[string]interface{}{"expected": target}
+			},
+			Run: func(st *riskBirdFlowState, _ map[string]interface{}) (map[string]interface{}, error) {
+				points, err := st.Client.GetPointOverview(st.Token)
+				if err != nil {
+					return nil, errors.New("获取用户积分信息失败")
+				}
+				output := map[string]interface{}{"points": points}
+				if points != target {
+					return output, fmt.Errorf("积分修改后校验失败，期望%d分，实际%d分", target, points)
+				}
+				return output, nil
+			},
+			Simulate: func(*riskBirdFlowState, map[string]interface{}) map[string]interface{} {
+				return map[string]interface{}{"points": target}
+			},
+		},
+	}
+}
 
-	return nil
+// modifyUserPoint 修改积分流程，progress 不为 nil 时记录每个步骤的进度
+func (s *UserPointService) modifyUserPoint(req systemReq.ModifyUserPoint, progress *riskBirdProgress, opts riskBirdPipelineOptions) (riskBirdPipelineResult, error) {
+	result, err := runRiskBirdFlow(riskBirdPointPipeline(req), req.Env, pointLogin(req), progress, opts)
+	if err == nil && !opts.DryRun {
+		global.GVA_LOG.Info(fmt.Sprintf("用户%s的积分已修改为%d分，移动端用户请重新登录后查看最新积分", result.Vars.Phone, req.PointAmount))
+	}
+	return result, err
 }
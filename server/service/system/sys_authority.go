@@ -97,6 +97,21 @@ func (authorityService *AuthorityService) CopyAuthority(adminAuthorityID uint, c
 			return
 		}
 	}
+	var scopes []system.RiskBirdAuthorityScope
+	err = global.GVA_DB.Find(&scopes, "authority_id = ?", copyInfo.OldAuthorityId).Error
+	if err != nil {
+		return
+	}
+	if len(scopes) > 0 {
+		for i := range scopes {
+			scopes[i].AuthorityId = copyInfo.Authority.AuthorityId
+		}
+		err = global.GVA_DB.Create(&scopes).Error
+
+		if err != nil {
+			return
+		}
+	}
 	paths := CasbinServiceApp.GetPolicyPathByAuthorityId(copyInfo.OldAuthorityId)
 	err = CasbinServiceApp.UpdateCasbin(adminAuthorityID, copyInfo.Authority.AuthorityId, paths)
 	if err != nil {
@@ -166,6 +181,9 @@ func (authorityService *AuthorityService) DeleteAuthority(auth *system.SysAuthor
 		if err = tx.Where("authority_id = ?", auth.AuthorityId).Delete(&[]system.SysAuthorityBtn{}).Error; err != nil {
 			return err
 		}
+		if err = tx.Where("authority_id = ?", auth.AuthorityId).Delete(&[]system.RiskBirdAuthorityScope{}).Error; err != nil {
+			return err
+		}
 
 		authorityId := strconv.Itoa(int(auth.AuthorityId))
 
@@ -592,6 +592,9 @@ func (sysExportTemplateService *SysExportTemplateService) ImportExcel(templateID
 	if err != nil {
 		return err
 	}
+	if _, ok := global.GVA_CONFIG.RiskBird.EnvByDBAlias(template.DBName); ok {
+		return errors.New("RiskBird业务库为只读，仅支持导出和预览，不支持导入")
+	}
 
 	src, err := file.Open()
 	if err != nil {
@@ -671,3 +674,16 @@ func getColumnName(n int) string {
 	}
 	return columnName
 }
+
+// CheckRiskBirdExportScope 模板使用 RiskBird 业务库时，校验操作人角色能否导出该环境的数据
+func (sysExportTemplateService *SysExportTemplateService) CheckRiskBirdExportScope(templateID string, authorityID uint) error {
+	var template system.SysExportTemplate
+	if err := global.GVA_DB.Select("db_name").First(&template, "template_id = ?", templateID).Error; err != nil {
+		return err
+	}
+	env, ok := global.GVA_CONFIG.RiskBird.EnvByDBAlias(template.DBName)
+	if !ok {
+		return nil
+	}
+	return checkRiskBirdScope(authorityID, env.Name, system.RiskBirdOperationExport)
+}
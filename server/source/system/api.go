@@ -78,6 +78,9 @@ func (i *initApi) InitializeData(ctx context.Context) (context.Context, error) {
 		{ApiGroup: "角色", Method: "PUT", Path: "/authority/updateAuthority", Description: "更新角色信息"},
 		{ApiGroup: "角色", Method: "POST", Path: "/authority/getAuthorityList", Description: "获取角色列表"},
 		{ApiGroup: "角色", Method: "POST", Path: "/authority/setDataAuthority", Description: "设置角色资源权限"},
+		{ApiGroup: "角色", Method: "POST", Path: "/authority/setRiskBirdScopes", Description: "设置角色RiskBird环境权限"},
+		{ApiGroup: "角色", Method: "GET", Path: "/authority/getRiskBirdScopes", Description: "获取角色RiskBird环境权限"},
+		{ApiGroup: "角色", Method: "GET", Path: "/authority/getRiskBirdAllowedEnvs", Description: "获取当前用户可用的RiskBird环境"},
 
 		{ApiGroup: "casbin", Method: "POST", Path: "/casbin/updateCasbin", Description: "更改角色api权限"},
 		{ApiGroup: "casbin", Method: "POST", Path: "/casbin/getPolicyPathByAuthorityId", Description: "获取权限列表"},
@@ -215,6 +218,75 @@ func (i *initApi) InitializeData(ctx context.Context) (context.Context, error) {
 		{ApiGroup: "版本控制", Method: "POST", Path: "/sysVersion/importVersion", Description: "同步版本"},
 		{ApiGroup: "版本控制", Method: "DELETE", Path: "/sysVersion/deleteSysVersion", Description: "删除版本"},
 		{ApiGroup: "版本控制", Method: "DELETE", Path: "/sysVersion/deleteSysVersionByIds", Description: "批量删除版本"},
+
+		{ApiGroup: "RiskBird账号重置", Method: "POST", Path: "/riskbird/resetSchedule/createResetSchedule", Description: "新建重置计划"},
+		{ApiGroup: "RiskBird账号重置", Method: "DELETE", Path: "/riskbird/resetSchedule/deleteResetSchedule", Description: "删除重置计划"},
+		{ApiGroup: "RiskBird账号重置", Method: "PUT", Path: "/riskbird/resetSchedule/updateResetSchedule", Description: "更新重置计划"},
+		{ApiGroup: "RiskBird账号重置", Method: "POST", Path: "/riskbird/resetSchedule/runResetSchedule", Description: "立即执行重置计划"},
+		{ApiGroup: "RiskBird账号重置", Method: "GET", Path: "/riskbird/resetSchedule/findResetSchedule", Description: "根据ID获取重置计划"},
+		{ApiGroup: "RiskBird账号重置", Method: "GET", Path: "/riskbird/resetSchedule/getResetScheduleList", Description: "获取重置计划列表"},
+		{ApiGroup: "RiskBird账号重置", Method: "GET", Path: "/riskbird/resetSchedule/getResetRunList", Description: "获取重置执行记录"},
+
+		{ApiGroup: "RiskBird定时任务", Method: "POST", Path: "/riskbird/job/triggerJob", Description: "触发定时任务"},
+		{ApiGroup: "RiskBird定时任务", Method: "GET", Path: "/riskbird/job/getJobCatalog", Description: "获取定时任务目录"},
+		{ApiGroup: "RiskBird定时任务", Method: "GET", Path: "/riskbird/job/findJobRecord", Description: "根据ID获取调用记录"},
+		{ApiGroup: "RiskBird定时任务", Method: "GET", Path: "/riskbird/job/getJobRecordList", Description: "获取调用记录列表"},
+
+		{ApiGroup: "RiskBird积分审核", Method: "POST", Path: "/riskbird/pointAudit/auditPointAcquisitions", Description: "批量审核积分获取记录"},
+		{ApiGroup: "RiskBird积分审核", Method: "GET", Path: "/riskbird/pointAudit/getPendingPointList", Description: "获取待审核积分获取记录"},
+
+		{ApiGroup: "RiskBird合成订单", Method: "POST", Path: "/riskbird/syntheticOrder/cleanupOrders", Description: "清理合成订单"},
+		{ApiGroup: "RiskBird合成订单", Method: "GET", Path: "/riskbird/syntheticOrder/getSyntheticOrderList", Description: "获取合成订单列表"},
+
+		{ApiGroup: "RiskBird账号库", Method: "POST", Path: "/riskbird/account/createAccount", Description: "登记账号"},
+		{ApiGroup: "RiskBird账号库", Method: "DELETE", Path: "/riskbird/account/deleteAccount", Description: "删除账号"},
+		{ApiGroup: "RiskBird账号库", Method: "PUT", Path: "/riskbird/account/updateAccount", Description: "更新账号"},
+		{ApiGroup: "RiskBird账号库", Method: "POST", Path: "/riskbird/account/provisionAccount", Description: "注册并登记测试账号"},
+		{ApiGroup: "RiskBird账号库", Method: "GET", Path: "/riskbird/account/findAccount", Description: "根据ID获取账号"},
+		{ApiGroup: "RiskBird账号库", Method: "GET", Path: "/riskbird/account/getAccountList", Description: "获取账号列表"},
+		{ApiGroup: "RiskBird账号库", Method: "GET", Path: "/riskbird/account/getAccountTimeline", Description: "获取账号变更时间线"},
+
+		{ApiGroup: "RiskBird流程进度", Method: "POST", Path: "/riskbird/flow/startModifyUserBalance", Description: "异步修改用户余额"},
+		{ApiGroup: "RiskBird流程进度", Method: "POST", Path: "/riskbird/flow/startModifyUserPoint", Description: "异步修改用户积分"},
+		{ApiGroup: "RiskBird流程进度", Method: "GET", Path: "/riskbird/flow/streamRunEvents", Description: "订阅流程步骤事件"},
+		{ApiGroup: "RiskBird流程进度", Method: "GET", Path: "/riskbird/flow/findFlowRun", Description: "根据ID获取流程执行记录"},
+		{ApiGroup: "RiskBird流程进度", Method: "GET", Path: "/riskbird/flow/getFlowRunList", Description: "获取流程执行记录列表"},
+
+		{ApiGroup: "API密钥", Method: "POST", Path: "/apiKey/createApiKey", Description: "创建API密钥"},
+		{ApiGroup: "API密钥", Method: "POST", Path: "/apiKey/revokeApiKey", Description: "吊销API密钥"},
+		{ApiGroup: "API密钥", Method: "GET", Path: "/apiKey/getApiKeyList", Description: "获取API密钥列表"},
+		{ApiGroup: "API密钥", Method: "GET", Path: "/apiKey/getApiKeyUsageList", Description: "获取API密钥调用记录"},
+
+		{ApiGroup: "RiskBird环境健康检查", Method: "GET", Path: "/riskbird/health/checkEnv", Description: "立即检查环境"},
+		{ApiGroup: "RiskBird环境健康检查", Method: "GET", Path: "/riskbird/health/getLatestReports", Description: "获取各环境最近一次检查报告"},
+		{ApiGroup: "RiskBird环境健康检查", Method: "GET", Path: "/riskbird/health/getHealthReportList", Description: "获取检查报告列表"},
+
+		{ApiGroup: "RiskBird配置比对", Method: "GET", Path: "/riskbird/config/diffConfig", Description: "比对RiskBird价格和充值套餐配置"},
+		{ApiGroup: "RiskBird配置比对", Method: "POST", Path: "/riskbird/config/createBaseline", Description: "从环境快照创建配置基线"},
+		{ApiGroup: "RiskBird配置比对", Method: "DELETE", Path: "/riskbird/config/deleteBaseline", Description: "删除配置基线"},
+		{ApiGroup: "RiskBird配置比对", Method: "GET", Path: "/riskbird/config/findBaseline", Description: "根据ID获取配置基线"},
+		{ApiGroup: "RiskBird配置比对", Method: "GET", Path: "/riskbird/config/getBaselineList", Description: "获取配置基线列表"},
+		{ApiGroup: "RiskBird配置比对", Method: "POST", Path: "/riskbird/config/restoreBaseline", Description: "按基线恢复环境配置"},
+		{ApiGroup: "RiskBird配置比对", Method: "GET", Path: "/riskbird/config/getRestoreList", Description: "获取配置恢复记录"},
+
+		{ApiGroup: "RiskBird代登录审计", Method: "GET", Path: "/riskbird/impersonation/getImpersonationLogList", Description: "获取代登录审计记录"},
+
+		{ApiGroup: "RiskBird账务核对", Method: "POST", Path: "/riskbird/reconcile/reconcileAccounts", Description: "核对账号余额和积分"},
+		{ApiGroup: "RiskBird账务核对", Method: "GET", Path: "/riskbird/reconcile/getReconciliationList", Description: "获取账务核对结果列表"},
+
+		{ApiGroup: "RiskBird批量造数", Method: "POST", Path: "/riskbird/generator/createJob", Description: "创建造数任务"},
+		{ApiGroup: "RiskBird批量造数", Method: "POST", Path: "/riskbird/generator/resumeJob", Description: "继续执行造数任务"},
+		{ApiGroup: "RiskBird批量造数", Method: "POST", Path: "/riskbird/generator/stopJob", Description: "停止造数任务"},
+		{ApiGroup: "RiskBird批量造数", Method: "GET", Path: "/riskbird/generator/findJob", Description: "根据ID获取造数任务"},
+		{ApiGroup: "RiskBird批量造数", Method: "GET", Path: "/riskbird/generator/getJobList", Description: "获取造数任务列表"},
+		{ApiGroup: "RiskBird批量造数", Method: "GET", Path: "/riskbird/generator/getItemList", Description: "获取造数账号清单"},
+		{ApiGroup: "RiskBird批量造数", Method: "GET", Path: "/riskbird/generator/exportManifest", Description: "导出造数账号清单"},
+
+		{ApiGroup: "RiskBird完成通知", Method: "GET", Path: "/riskbird/notify/getPreference", Description: "获取通知偏好"},
+		{ApiGroup: "RiskBird完成通知", Method: "PUT", Path: "/riskbird/notify/setPreference", Description: "设置通知偏好"},
+		{ApiGroup: "RiskBird完成通知", Method: "POST", Path: "/riskbird/notify/sendTestNotification", Description: "发送测试通知"},
+
+		{ApiGroup: "系统服务", Method: "GET", Path: "/metrics", Description: "获取Prometheus指标"},
 	}
 	if err := db.Create(&entities).Error; err != nil {
 		return ctx, errors.Wrap(err, sysModel.SysApi{}.TableName()+"表数据初始化失败!")
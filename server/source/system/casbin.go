@@ -65,6 +65,9 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "888", V1: "/authority/deleteAuthority", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/authority/getAuthorityList", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/authority/setDataAuthority", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/authority/setRiskBirdScopes", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/authority/getRiskBirdScopes", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/authority/getRiskBirdAllowedEnvs", V2: "GET"},
 
 		{Ptype: "p", V0: "888", V1: "/menu/getMenu", V2: "POST"},
 		{Ptype: "p", V0: "888", V1: "/menu/getMenuList", V2: "POST"},
@@ -215,6 +218,66 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "888", V1: "/sysVersion/deleteSysVersion", V2: "DELETE"},
 		{Ptype: "p", V0: "888", V1: "/sysVersion/deleteSysVersionByIds", V2: "DELETE"},
 
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/createResetSchedule", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/deleteResetSchedule", V2: "DELETE"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/updateResetSchedule", V2: "PUT"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/runResetSchedule", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/findResetSchedule", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/getResetScheduleList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/resetSchedule/getResetRunList", V2: "GET"},
+
+		{Ptype: "p", V0: "888", V1: "/riskbird/job/triggerJob", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/job/getJobCatalog", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/job/findJobRecord", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/job/getJobRecordList", V2: "GET"},
+
+		{Ptype: "p", V0: "888", V1: "/riskbird/pointAudit/auditPointAcquisitions", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/pointAudit/getPendingPointList", V2: "GET"},
+
+		{Ptype: "p", V0: "888", V1: "/riskbird/syntheticOrder/cleanupOrders", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/syntheticOrder/getSyntheticOrderList", V2: "GET"},
+
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/createAccount", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/deleteAccount", V2: "DELETE"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/updateAccount", V2: "PUT"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/provisionAccount", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/findAccount", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/getAccountList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/account/getAccountTimeline", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/flow/startModifyUserBalance", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/flow/startModifyUserPoint", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/flow/streamRunEvents", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/flow/findFlowRun", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/flow/getFlowRunList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/health/checkEnv", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/health/getLatestReports", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/health/getHealthReportList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/diffConfig", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/createBaseline", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/deleteBaseline", V2: "DELETE"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/findBaseline", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/getBaselineList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/restoreBaseline", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/config/getRestoreList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/impersonation/getImpersonationLogList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/reconcile/reconcileAccounts", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/reconcile/getReconciliationList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/createJob", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/resumeJob", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/stopJob", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/findJob", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/getJobList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/getItemList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/generator/exportManifest", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/notify/getPreference", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/notify/setPreference", V2: "PUT"},
+		{Ptype: "p", V0: "888", V1: "/riskbird/notify/sendTestNotification", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/metrics", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/apiKey/createApiKey", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/apiKey/revokeApiKey", V2: "POST"},
+		{Ptype: "p", V0: "888", V1: "/apiKey/getApiKeyList", V2: "GET"},
+		{Ptype: "p", V0: "888", V1: "/apiKey/getApiKeyUsageList", V2: "GET"},
+
 		{Ptype: "p", V0: "8881", V1: "/user/admin_register", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/api/createApi", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/api/getApiList", V2: "POST"},
@@ -226,6 +289,7 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "8881", V1: "/authority/deleteAuthority", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/authority/getAuthorityList", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/authority/setDataAuthority", V2: "POST"},
+		{Ptype: "p", V0: "8881", V1: "/authority/getRiskBirdAllowedEnvs", V2: "GET"},
 		{Ptype: "p", V0: "8881", V1: "/menu/getMenu", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/menu/getMenuList", V2: "POST"},
 		{Ptype: "p", V0: "8881", V1: "/menu/addBaseMenu", V2: "POST"},
@@ -267,6 +331,7 @@ func (i *initCasbin) InitializeData(ctx context.Context) (context.Context, error
 		{Ptype: "p", V0: "9528", V1: "/authority/deleteAuthority", V2: "POST"},
 		{Ptype: "p", V0: "9528", V1: "/authority/getAuthorityList", V2: "POST"},
 		{Ptype: "p", V0: "9528", V1: "/authority/setDataAuthority", V2: "POST"},
+		{Ptype: "p", V0: "9528", V1: "/authority/getRiskBirdAllowedEnvs", V2: "GET"},
 
 		{Ptype: "p", V0: "9528", V1: "/menu/getMenu", V2: "POST"},
 		{Ptype: "p", V0: "9528", V1: "/menu/getMenuList", V2: "POST"},
@@ -3,19 +3,36 @@ package request
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
 	"go.uber.org/zap"
 )
 
+// DefaultRiskBirdAdminBaseURL 未配置时使用的后台管理系统接口地址
+const DefaultRiskBirdAdminBaseURL = "http://mgrtest.riskbird.com/prod-api"
+
+// 积分审核结果
+const (
+	RiskBirdAuditApprove = 1 // 审核通过
+	RiskBirdAuditReject  = 2 // 审核驳回
+)
+
 // RiskBirdAPIClient 外部 RiskBird 系统 API 客户端
 type RiskBirdAPIClient struct {
-	BaseURL string
-	Client  *http.Client
+	Env          string // 环境名称，用作指标标签
+	BaseURL      string
+	AdminBaseURL string
+	Client       *http.Client
+	Retry        map[string]RetryPolicy // 幂等接口的重试策略，未配置的接口不重试
+	Breaker      *CircuitBreaker        // 环境熔断器，为 nil 时不熔断
 }
 
 // LoginResponse 登录响应结构
@@ -31,7 +48,7 @@ type LoginResponse struct {
 type BalanceResponse struct {
 	Code int `json:"code"`
 	Data struct {
-		TotalBalance float64 `json:"totalBalance"`
+		TotalBalance common.Money `json:"totalBalance"`
 	} `json:"data"`
 	Msg string `json:"msg"`
 }
@@ -57,10 +74,12 @@ type OrderResponse struct {
 // NewRiskBirdAPIClient 创建 RiskBird API 客户端
 func NewRiskBirdAPIClient(baseURL string) *RiskBirdAPIClient {
 	return &RiskBirdAPIClient{
-		BaseURL: baseURL,
+		BaseURL:      baseURL,
+		AdminBaseURL: DefaultRiskBirdAdminBaseURL,
 		Client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		Retry: DefaultRetryPolicies(),
 	}
 }
 
@@ -72,11 +91,9 @@ func (c *RiskBirdAPIClient) Login(mobile, password string) (map[string]interface
 	params.Add("password", password)
 	urlStr := fmt.Sprintf("%s/loginByPass?%s", c.BaseURL, params.Encode())
 
-	global.GVA_LOG.Info("调用RiskBird登录接口",
-		zap.String("url", urlStr),
-		zap.String("mobile", mobile))
+	global.GVA_LOG.Info("调用RiskBird登录接口", zap.String("mobile", mobile))
 
-	resp, err := c.Client.Post(urlStr, "application/json", nil)
+	resp, err := c.do(EndpointLogin, newEmptyPost(urlStr))
 	if err != nil {
 		global.GVA_LOG.Error("RiskBird登录请求失败", zap.Error(err))
 		return nil, err
@@ -107,16 +124,116 @@ func (c *RiskBirdAPIClient) Login(mobile, password string) (map[string]interface
 	return result["data"].(map[string]interface{}), nil
 }
 
-// GetBalance 获取用户余额
-func (c *RiskBirdAPIClient) GetBalance(token string) (float64, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recharge/account/balance", c.BaseURL), nil)
+// ProbeUserAPI 检查用户端接口是否可用，以空账号调用登录接口，能返回业务状态码即认为接口正常。
+// 探测不经过熔断器，熔断期间也能反映环境的真实状态
+func (c *RiskBirdAPIClient) ProbeUserAPI() error {
+	resp, err := c.Client.Post(fmt.Sprintf("%s/loginByPass", c.BaseURL), "application/json", nil)
 	if err != nil {
-		return 0, err
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("响应解析失败: %w", err)
+	}
+	if _, ok := result["code"]; !ok {
+		return fmt.Errorf("响应缺少业务状态码")
+	}
+	return nil
+}
+
+// SendSmsCode 发送短信验证码
+func (c *RiskBirdAPIClient) SendSmsCode(path, mobile string) error {
+	params := url.Values{}
+	params.Add("mobile", mobile)
+	urlStr := fmt.Sprintf("%s%s?%s", c.BaseURL, path, params.Encode())
+
+	global.GVA_LOG.Info("调用RiskBird发送验证码接口", zap.String("path", path))
+
+	if _, err := c.postForResult(EndpointSendSmsCode, urlStr); err != nil {
+		global.GVA_LOG.Error("RiskBird发送验证码失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// LoginBySms 使用短信验证码登录，验证码使用后即失效，失败时不重试
+func (c *RiskBirdAPIClient) LoginBySms(path, mobile, code string) (map[string]interface{}, error) {
+	params := url.Values{}
+	params.Add("mobile", mobile)
+	params.Add("code", code)
+	urlStr := fmt.Sprintf("%s%s?%s", c.BaseURL, path, params.Encode())
+
+	global.GVA_LOG.Info("调用RiskBird验证码登录接口", zap.String("path", path), zap.String("mobile", mobile))
+
+	result, err := c.postForResult(EndpointSmsLogin, urlStr)
+	if err != nil {
+		global.GVA_LOG.Error("RiskBird验证码登录失败", zap.Error(err))
+		return nil, err
+	}
+	data, ok := result["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("验证码登录响应缺少用户信息")
+	}
+	return data, nil
+}
+
+// Register 使用短信验证码注册用户
+func (c *RiskBirdAPIClient) Register(path, mobile, code, password string) (map[string]interface{}, error) {
+	params := url.Values{}
+	params.Add("mobile", mobile)
+	params.Add("code", code)
+	params.Add("password", password)
+	urlStr := fmt.Sprintf("%s%s?%s", c.BaseURL, path, params.Encode())
+
+	global.GVA_LOG.Info("调用RiskBird注册接口", zap.String("path", path))
+
+	result, err := c.postForResult(EndpointRegister, urlStr)
+	if err != nil {
+		global.GVA_LOG.Error("RiskBird注册失败", zap.Error(err))
+		return nil, err
 	}
+	data, _ := result["data"].(map[string]interface{})
+	return data, nil
+}
 
-	req.Header.Set("Authorization", token)
+// postForResult 以无请求体的 POST 调用用户端接口，并校验响应中的业务状态码
+func (c *RiskBirdAPIClient) postForResult(endpoint, urlStr string) (map[string]interface{}, error) {
+	resp, err := c.do(endpoint, newEmptyPost(urlStr))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	resp, err := c.Client.Do(req)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if code, ok := result["code"].(float64); ok && int(code) != 20000 {
+		return nil, fmt.Errorf("%v", result["msg"])
+	}
+	return result, nil
+}
+
+// GetBalance 获取用户余额
+func (c *RiskBirdAPIClient) GetBalance(token string) (common.Money, error) {
+	resp, err := c.do(EndpointBalance, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/recharge/account/balance", c.BaseURL), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		return req, nil
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -141,16 +258,20 @@ func (c *RiskBirdAPIClient) CreatePreOrder(token string, payload map[string]inte
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/payment/createPreOrder", c.BaseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Authorization", token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.Client.Do(req)
-	if err != nil {
+	resp, err := c.do(EndpointCreatePreOrder, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/payment/createPreOrder", c.BaseURL), bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	// 创建类接口不自动重试，结果未知时由调用方检查是否已创建
+	if err := outcomeUnknown(resp, err); err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
 		return "", err
 	}
 	defer resp.Body.Close()
@@ -174,16 +295,20 @@ func (c *RiskBirdAPIClient) CreateOrder(token string, payload map[string]interfa
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/payment/createOrder", c.BaseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Authorization", token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.Client.Do(req)
-	if err != nil {
+	resp, err := c.do(EndpointCreateOrder, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/payment/createOrder", c.BaseURL), bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	// 创建类接口不自动重试，结果未知时由调用方检查是否已创建
+	if err := outcomeUnknown(resp, err); err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
 		return "", err
 	}
 	defer resp.Body.Close()
@@ -212,15 +337,15 @@ func (c *RiskBirdAPIClient) UpdateOrder(token string, orderNo string, status str
 		return err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/payment/updateOrder", c.BaseURL), bytes.NewBuffer(body))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Authorization", token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(EndpointUpdateOrder, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/payment/updateOrder", c.BaseURL), bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return err
 	}
@@ -235,14 +360,14 @@ func (c *RiskBirdAPIClient) UpdateOrder(token string, orderNo string, status str
 
 // GetPointOverview 获取用户积分信息
 func (c *RiskBirdAPIClient) GetPointOverview(token string) (int64, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/user/point/overview", c.BaseURL), nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(EndpointPointOverview, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/user/point/overview", c.BaseURL), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		return req, nil
+	})
 	if err != nil {
 		global.GVA_LOG.Error("获取积分信息请求失败", zap.Error(err))
 		return 0, err
@@ -273,14 +398,14 @@ func (c *RiskBirdAPIClient) GetPointOverview(token string) (int64, error) {
 
 // ExpirePoint 使积分失效
 func (c *RiskBirdAPIClient) ExpirePoint(token string) error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/guest/job/expirePoint", c.BaseURL), nil)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(EndpointJob, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/guest/job/expirePoint", c.BaseURL), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		return req, nil
+	})
 	if err != nil {
 		global.GVA_LOG.Error("积分失效请求失败", zap.Error(err))
 		return err
@@ -297,14 +422,14 @@ func (c *RiskBirdAPIClient) ExpirePoint(token string) error {
 
 // PointAuditDay 积分日审核定时任务
 func (c *RiskBirdAPIClient) PointAuditDay(token string) error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/guest/job/pointAuditDay", c.BaseURL), nil)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Authorization", token)
-
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(EndpointJob, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/guest/job/pointAuditDay", c.BaseURL), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", token)
+		return req, nil
+	})
 	if err != nil {
 		global.GVA_LOG.Error("积分日审核定时任务请求失败", zap.Error(err))
 		return err
@@ -319,18 +444,52 @@ func (c *RiskBirdAPIClient) PointAuditDay(token string) error {
 	return nil
 }
 
+// TriggerJob 调用 RiskBird 定时任务接口，返回 HTTP 状态码和原始响应内容
+func (c *RiskBirdAPIClient) TriggerJob(method, path string, params map[string]string) (int, []byte, error) {
+	if method == "" {
+		method = http.MethodGet
+	}
+	query := url.Values{}
+	for k, v := range params {
+		query.Add(k, v)
+	}
+	urlStr := fmt.Sprintf("%s%s", c.BaseURL, path)
+	if len(query) > 0 {
+		urlStr = fmt.Sprintf("%s?%s", urlStr, query.Encode())
+	}
+
+	resp, err := c.do(EndpointJob, func() (*http.Request, error) {
+		return http.NewRequest(method, urlStr, nil)
+	})
+	if err != nil {
+		global.GVA_LOG.Error("定时任务接口请求失败", zap.String("path", path), zap.Error(err))
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		global.GVA_LOG.Error("定时任务接口HTTP状态错误", zap.String("path", path), zap.Int("status", resp.StatusCode))
+		return resp.StatusCode, body, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, body, nil
+}
+
 // AdminLogin 管理员登录
 func (c *RiskBirdAPIClient) AdminLogin(username, password string) (string, error) {
 	params := url.Values{}
 	params.Add("username", username)
 	params.Add("password", password)
-	urlStr := fmt.Sprintf("http://mgrtest.riskbird.com/prod-api/account/login?%s", params.Encode())
+	urlStr := fmt.Sprintf("%s/account/login?%s", c.AdminBaseURL, params.Encode())
 
-	global.GVA_LOG.Info("调用RiskBird管理员登录接口",
-		zap.String("url", urlStr),
-		zap.String("username", username))
+	global.GVA_LOG.Info("调用RiskBird管理员登录接口", zap.String("username", username))
 
-	resp, err := c.Client.Post(urlStr, "application/json", nil)
+	resp, err := c.do(EndpointAdminLogin, newEmptyPost(urlStr))
 	if err != nil {
 		global.GVA_LOG.Error("管理员登录请求失败", zap.Error(err))
 		return "", err
@@ -359,12 +518,65 @@ func (c *RiskBirdAPIClient) AdminLogin(username, password string) (string, error
 	return token, nil
 }
 
+// ImpersonateUser 使用后台管理员token调用后台接口为指定用户签发token
+func (c *RiskBirdAPIClient) ImpersonateUser(adminToken, path string, userID int64) (string, error) {
+	return c.issueUserToken(fmt.Sprintf("%s%s", c.AdminBaseURL, path), userID, "Authorization", adminToken)
+}
+
+// IssueTestToken 调用测试环境专用接口为指定用户签发token，secret 通过 X-Test-Secret 请求头发送
+func (c *RiskBirdAPIClient) IssueTestToken(path, secret string, userID int64) (string, error) {
+	return c.issueUserToken(fmt.Sprintf("%s%s", c.BaseURL, path), userID, "X-Test-Secret", secret)
+}
+
+// issueUserToken 以 userId 参数调用签发接口，返回 data.token
+func (c *RiskBirdAPIClient) issueUserToken(baseURL string, userID int64, header, value string) (string, error) {
+	params := url.Values{}
+	params.Add("userId", strconv.FormatInt(userID, 10))
+	urlStr := fmt.Sprintf("%s?%s", baseURL, params.Encode())
+
+	global.GVA_LOG.Info("调用RiskBird代登录接口", zap.String("url", urlStr))
+
+	resp, err := c.do(EndpointImpersonate, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(header, value)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		global.GVA_LOG.Error("代登录请求失败", zap.Error(err))
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		global.GVA_LOG.Error("代登录HTTP状态错误", zap.Int("status", resp.StatusCode))
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var result LoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		global.GVA_LOG.Error("代登录响应解析失败", zap.Error(err))
+		return "", err
+	}
+	if result.Code != 20000 {
+		global.GVA_LOG.Error("代登录失败", zap.Int("code", result.Code), zap.String("msg", result.Msg))
+		return "", fmt.Errorf("代登录失败: %s", result.Msg)
+	}
+	if result.Data.Token == "" {
+		return "", errors.New("代登录接口未返回token")
+	}
+	return result.Data.Token, nil
+}
+
 // AuditPointAcquisition 对积分获取记录进行审核
-func (c *RiskBirdAPIClient) AuditPointAcquisition(adminToken string, pointAcquisitionID int64) error {
+func (c *RiskBirdAPIClient) AuditPointAcquisition(adminToken string, pointAcquisitionIDs []int64, auditResult int, auditType int) error {
 	payload := map[string]interface{}{
-		"auditResult": 1,
-		"ids":         []int64{pointAcquisitionID},
-		"type":        2,
+		"auditResult": auditResult,
+		"ids":         pointAcquisitionIDs,
+		"type":        auditType,
 	}
 
 	body, err := json.Marshal(payload)
@@ -372,15 +584,15 @@ func (c *RiskBirdAPIClient) AuditPointAcquisition(adminToken string, pointAcquis
 		return err
 	}
 
-	req, err := http.NewRequest("POST", "http://mgrtest.riskbird.com/prod-api/admin/point/acquisition/audit/operate", bytes.NewBuffer(body))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Authorization", adminToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(EndpointAuditPoint, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", fmt.Sprintf("%s/admin/point/acquisition/audit/operate", c.AdminBaseURL), bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", adminToken)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		global.GVA_LOG.Error("积分审核请求失败", zap.Error(err))
 		return err
@@ -392,5 +604,16 @@ func (c *RiskBirdAPIClient) AuditPointAcquisition(adminToken string, pointAcquis
 		return fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		global.GVA_LOG.Error("积分审核响应解析失败", zap.Error(err))
+		return err
+	}
+
+	if code, ok := result["code"].(float64); ok && int(code) != 20000 {
+		global.GVA_LOG.Error("积分审核失败", zap.Any("msg", result["msg"]))
+		return fmt.Errorf("code %d", int(code))
+	}
+
 	return nil
 }
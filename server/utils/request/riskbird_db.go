@@ -3,7 +3,11 @@ package request
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/flipped-aurora/gin-vue-admin/server/model/common"
+	"github.com/flipped-aurora/gin-vue-admin/server/model/system"
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -16,6 +20,19 @@ type RiskBirdDBConfig struct {
 	Database string
 }
 
+// 造数流程依赖的 RiskBird 固定数据
+const (
+	ReportPriceCfgID  = 12 // p_product_cfg 中报告价格配置的ID
+	RechargeProductID = 5  // p_recharge_product 中用于充值的商品ID
+)
+
+// RiskBirdRequiredColumns 造数流程读写的表和字段，表结构变化时健康检查会报告缺失的字段
+var RiskBirdRequiredColumns = map[string][]string{
+	"p_product_cfg":      {"id", "cfg_value"},
+	"p_recharge_product": {"id", "amount", "gift_amount"},
+	"point_acquisition":  {"id", "user_id", "points", "left_points", "audit_status", "point_time", "expire_time", "create_time"},
+}
+
 // NewRiskBirdDB 创建RiskBird数据库连接
 func NewRiskBirdDB(config RiskBirdDBConfig) (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
@@ -29,37 +46,465 @@ func NewRiskBirdDB(config RiskBirdDBConfig) (*sql.DB, error) {
 }
 
 // UpdateProductCfg 修改产品配置价格
-func UpdateProductCfg(db *sql.DB, id int, value float64) error {
+func UpdateProductCfg(db *sql.DB, id int, value common.Money) (err error) {
+	defer observeRiskBirdDB("update_product_cfg", time.Now(), &err)
 	sql := "UPDATE p_product_cfg SET cfg_value = ? WHERE id = ?"
-	_, err := db.Exec(sql, value, id)
+	_, err = db.Exec(sql, value, id)
 	return err
 }
 
 // UpdateRechargeProduct 修改充值套餐
-func UpdateRechargeProduct(db *sql.DB, id int, amount, giftAmount float64) error {
+func UpdateRechargeProduct(db *sql.DB, id int, amount, giftAmount common.Money) (err error) {
+	defer observeRiskBirdDB("update_recharge_product", time.Now(), &err)
 	sql := "UPDATE p_recharge_product SET amount = ?, gift_amount = ? WHERE id = ?"
-	_, err := db.Exec(sql, amount, giftAmount, id)
+	_, err = db.Exec(sql, amount, giftAmount, id)
 	return err
 }
 
 // UpdatePointExpireTime 修改积分失效时间
-func UpdatePointExpireTime(db *sql.DB, userID int64, expireTime interface{}) error {
+func UpdatePointExpireTime(db *sql.DB, userID int64, expireTime interface{}) (err error) {
+	defer observeRiskBirdDB("update_point_expire_time", time.Now(), &err)
 	sql := "UPDATE point_acquisition SET expire_time = ? WHERE user_id = ? AND left_points > 0"
-	_, err := db.Exec(sql, expireTime, userID)
+	_, err = db.Exec(sql, expireTime, userID)
 	return err
 }
 
 // GetLatestPointAcquisitionID 获取最新的积分获取记录ID
-func GetLatestPointAcquisitionID(db *sql.DB, userID int64) (int64, error) {
-	var id int64
+func GetLatestPointAcquisitionID(db *sql.DB, userID int64) (id int64, err error) {
+	defer observeRiskBirdDB("get_latest_point_acquisition_id", time.Now(), &err)
 	sql := "SELECT id FROM point_acquisition WHERE user_id = ? ORDER BY create_time DESC LIMIT 1"
-	err := db.QueryRow(sql, userID).Scan(&id)
+	err = db.QueryRow(sql, userID).Scan(&id)
 	return id, err
 }
 
 // UpdatePointAcquisitionTime 修改积分获取时间
-func UpdatePointAcquisitionTime(db *sql.DB, pointAcquisitionID int64, pointTime interface{}) error {
+func UpdatePointAcquisitionTime(db *sql.DB, pointAcquisitionID int64, pointTime interface{}) (err error) {
+	defer observeRiskBirdDB("update_point_acquisition_time", time.Now(), &err)
 	sql := "UPDATE point_acquisition SET point_time = ? WHERE id = ?"
-	_, err := db.Exec(sql, pointTime, pointAcquisitionID)
+	_, err = db.Exec(sql, pointTime, pointAcquisitionID)
 	return err
 }
+
+// 积分获取记录审核状态
+const (
+	PointAcquisitionAuditPending  = 0
+	PointAcquisitionAuditApproved = 1
+)
+
+// PointAcquisition 积分获取记录
+type PointAcquisition struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"userId"`
+	Points      int64     `json:"points"`
+	LeftPoints  int64     `json:"leftPoints"`
+	AuditStatus int       `json:"auditStatus"`
+	PointTime   time.Time `json:"pointTime"`
+	ExpireTime  time.Time `json:"expireTime"`
+	CreateTime  time.Time `json:"createTime"`
+}
+
+// scanPointAcquisition 读取一条积分获取记录，时间字段为 NULL 时保留零值
+func scanPointAcquisition(rows *sql.Rows) (item PointAcquisition, err error) {
+	var pointTime, expireTime, createTime sql.NullTime
+	err = rows.Scan(&item.ID, &item.UserID, &item.Points, &item.LeftPoints, &item.AuditStatus,
+		&pointTime, &expireTime, &createTime)
+	item.PointTime, item.ExpireTime, item.CreateTime = pointTime.Time, expireTime.Time, createTime.Time
+	return item, err
+}
+
+// ListPendingPointAcquisitions 分页查询待审核的积分获取记录，userID 为0时查询全部用户
+func ListPendingPointAcquisitions(db *sql.DB, userID int64, limit, offset int) (list []PointAcquisition, total int64, err error) {
+	defer observeRiskBirdDB("list_pending_point_acquisitions", time.Now(), &err)
+	where := "audit_status = ?"
+	args := []interface{}{PointAcquisitionAuditPending}
+	if userID != 0 {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+
+	if err := db.QueryRow("SELECT COUNT(*) FROM point_acquisition WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sql := "SELECT id, user_id, points, left_points, audit_status, point_time, expire_time, create_time FROM point_acquisition WHERE " +
+		where + " ORDER BY create_time DESC LIMIT ? OFFSET ?"
+	rows, err := db.Query(sql, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPointAcquisition(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, item)
+	}
+	return list, total, rows.Err()
+}
+
+// ListAvailablePointAcquisitions 查询用户在 now 时未过期且有剩余积分的记录，返回记录和剩余积分合计
+func ListAvailablePointAcquisitions(db *sql.DB, userID int64, now time.Time) (list []PointAcquisition, total int64, err error) {
+	defer observeRiskBirdDB("list_available_point_acquisitions", time.Now(), &err)
+	sql := "SELECT id, user_id, points, left_points, audit_status, point_time, expire_time, create_time FROM point_acquisition " +
+		"WHERE user_id = ? AND left_points > 0 AND expire_time > ? ORDER BY expire_time, id"
+	rows, err := db.Query(sql, userID, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPointAcquisition(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, item)
+		total += item.LeftPoints
+	}
+	return list, total, rows.Err()
+}
+
+// ListPointAcquisitionsBetween 查询用户在 [start, end) 内创建的积分获取记录，按创建时间倒序，最多返回 limit 条
+func ListPointAcquisitionsBetween(db *sql.DB, userID int64, start, end time.Time, limit int) (list []PointAcquisition, err error) {
+	defer observeRiskBirdDB("list_point_acquisitions_between", time.Now(), &err)
+	sql := "SELECT id, user_id, points, left_points, audit_status, point_time, expire_time, create_time FROM point_acquisition " +
+		"WHERE user_id = ? AND create_time >= ? AND create_time < ? ORDER BY create_time DESC, id DESC LIMIT ?"
+	rows, err := db.Query(sql, userID, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPointAcquisition(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return list, rows.Err()
+}
+
+// GetPendingPointAcquisitionIDs 查询用户全部待审核的积分获取记录ID
+func GetPendingPointAcquisitionIDs(db *sql.DB, userID int64) (ids []int64, err error) {
+	defer observeRiskBirdDB("get_pending_point_acquisition_ids", time.Now(), &err)
+	sql := "SELECT id FROM point_acquisition WHERE audit_status = ? AND user_id = ? ORDER BY id"
+	rows, err := db.Query(sql, PointAcquisitionAuditPending, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// InsertPointAcquisition 直接新增一条已审核通过的积分获取记录，返回记录ID
+func InsertPointAcquisition(db *sql.DB, userID, points int64, pointTime, expireTime time.Time) (id int64, err error) {
+	defer observeRiskBirdDB("insert_point_acquisition", time.Now(), &err)
+	sql := "INSERT INTO point_acquisition (user_id, points, left_points, audit_status, point_time, expire_time, create_time) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := db.Exec(sql, userID, points, points, PointAcquisitionAuditApproved, pointTime, expireTime, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// DeductLeftPoints 按失效时间从早到晚扣减用户未失效的剩余积分，返回实际扣减的积分
+func DeductLeftPoints(db *sql.DB, userID, points int64) (deducted int64, err error) {
+	defer observeRiskBirdDB("deduct_left_points", time.Now(), &err)
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id, left_points FROM point_acquisition WHERE user_id = ? AND left_points > 0 AND expire_time > ? ORDER BY expire_time, id FOR UPDATE",
+		userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	type leftPoints struct {
+		id     int64
+		points int64
+	}
+	var list []leftPoints
+	for rows.Next() {
+		var item leftPoints
+		if err := rows.Scan(&item.id, &item.points); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		list = append(list, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, item := range list {
+		if deducted == points {
+			break
+		}
+		n := min(item.points, points-deducted)
+		if _, err := tx.Exec("UPDATE point_acquisition SET left_points = left_points - ? WHERE id = ?", n, item.id); err != nil {
+			return 0, err
+		}
+		deducted += n
+	}
+	return deducted, tx.Commit()
+}
+
+// 合成订单清理方式
+const (
+	OrderCleanupHide   = "hide"
+	OrderCleanupCancel = "cancel"
+	OrderCleanupDelete = "delete"
+)
+
+// OrderTable 订单表结构
+type OrderTable struct {
+	Table         string
+	OrderNoColumn string
+	HiddenColumn  string
+	StatusColumn  string
+	CancelStatus  string
+	UserColumn    string
+	TimeColumn    string
+}
+
+// CleanupOrders 按订单号隐藏、取消或删除订单，返回受影响的行数
+func CleanupOrders(db *sql.DB, table OrderTable, action string, orderNos []string) (affected int64, err error) {
+	defer observeRiskBirdDB("cleanup_orders", time.Now(), &err)
+	if len(orderNos) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(orderNos)), ",")
+	args := make([]interface{}, 0, len(orderNos)+1)
+
+	var sql string
+	switch action {
+	case OrderCleanupHide:
+		sql = fmt.Sprintf("UPDATE %s SET %s = 1 WHERE %s IN (%s)", table.Table, table.HiddenColumn, table.OrderNoColumn, placeholders)
+	case OrderCleanupCancel:
+		sql = fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s IN (%s)", table.Table, table.StatusColumn, table.OrderNoColumn, placeholders)
+		args = append(args, table.CancelStatus)
+	case OrderCleanupDelete:
+		sql = fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", table.Table, table.OrderNoColumn, placeholders)
+	default:
+		return 0, fmt.Errorf("不支持的清理方式: %s", action)
+	}
+	for _, orderNo := range orderNos {
+		args = append(args, orderNo)
+	}
+
+	result, err := db.Exec(sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// SmsCodeTable 短信验证码表结构
+type SmsCodeTable struct {
+	Table        string
+	MobileColumn string
+	CodeColumn   string
+	TimeColumn   string
+}
+
+// GetLatestSmsCode 查询手机号在指定时间之后收到的最新验证码
+func GetLatestSmsCode(db *sql.DB, table SmsCodeTable, mobile string, since time.Time) (code string, err error) {
+	defer observeRiskBirdDB("get_latest_sms_code", time.Now(), &err)
+	sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? AND %s >= ? ORDER BY %s DESC LIMIT 1",
+		table.CodeColumn, table.Table, table.MobileColumn, table.TimeColumn, table.TimeColumn)
+	err = db.QueryRow(sql, mobile, since).Scan(&code)
+	return code, err
+}
+
+// GetTableColumns 查询当前数据库中指定表的全部字段名，表不存在时返回空列表
+func GetTableColumns(db *sql.DB, table string) (columns []string, err error) {
+	defer observeRiskBirdDB("get_table_columns", time.Now(), &err)
+	rows, err := db.Query("SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// RowExists 判断表中是否存在指定ID的记录，table 只能传入代码中的固定表名
+func RowExists(db *sql.DB, table string, id int) (exists bool, err error) {
+	defer observeRiskBirdDB("row_exists", time.Now(), &err)
+	var count int64
+	if err := db.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE id = ?", id).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindOrderNosSince 查询用户在指定时间之后创建的订单号，用于创建订单结果未知时的重复检测
+func FindOrderNosSince(db *sql.DB, table OrderTable, userID int64, since time.Time) (orderNos []string, err error) {
+	defer observeRiskBirdDB("find_order_nos_since", time.Now(), &err)
+	sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? AND %s >= ? ORDER BY %s DESC",
+		table.OrderNoColumn, table.Table, table.UserColumn, table.TimeColumn, table.TimeColumn)
+	rows, err := db.Query(sql, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var orderNo string
+		if err := rows.Scan(&orderNo); err != nil {
+			return nil, err
+		}
+		orderNos = append(orderNos, orderNo)
+	}
+	return orderNos, rows.Err()
+}
+
+// UserTable 用户表结构
+type UserTable struct {
+	Table        string
+	MobileColumn string
+}
+
+// FindUser 按用户ID或手机号查找用户，userID 不为0时按ID查找，返回用户ID和手机号
+func FindUser(db *sql.DB, table UserTable, userID int64, mobile string) (id int64, userMobile string, err error) {
+	defer observeRiskBirdDB("find_user", time.Now(), &err)
+	sql := fmt.Sprintf("SELECT id, %s FROM %s WHERE ", table.MobileColumn, table.Table)
+	var arg interface{}
+	if userID != 0 {
+		sql, arg = sql+"id = ?", userID
+	} else {
+		sql, arg = sql+table.MobileColumn+" = ?", mobile
+	}
+	err = db.QueryRow(sql+" LIMIT 1", arg).Scan(&id, &userMobile)
+	return id, userMobile, err
+}
+
+// BalanceTable 余额账户表结构
+type BalanceTable struct {
+	Table          string
+	UserColumn     string
+	BalanceColumns []string
+}
+
+// ListBalanceRows 查询用户的余额账户记录，返回每条记录的全部字段和余额字段合计
+func ListBalanceRows(db *sql.DB, table BalanceTable, userID int64) (list []map[string]string, total common.Money, err error) {
+	defer observeRiskBirdDB("list_balance_rows", time.Now(), &err)
+	rows, err := db.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", table.Table, table.UserColumn), userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
+		}
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			row[column] = values[i].String
+		}
+		for _, column := range table.BalanceColumns {
+			value, ok := row[column]
+			if !ok {
+				return nil, 0, fmt.Errorf("%s 表没有 %s 字段", table.Table, column)
+			}
+			if value == "" {
+				continue
+			}
+			amount, err := common.ParseMoney(value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%s.%s 金额格式错误: %w", table.Table, column, err)
+			}
+			total += amount
+		}
+		list = append(list, row)
+	}
+	return list, total, rows.Err()
+}
+
+// ListProductCfgs 查询 p_product_cfg 全部配置
+func ListProductCfgs(db *sql.DB) (list []system.RiskBirdProductCfg, err error) {
+	defer observeRiskBirdDB("list_product_cfgs", time.Now(), &err)
+	rows, err := db.Query("SELECT id, cfg_value FROM p_product_cfg ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item system.RiskBirdProductCfg
+		var value sql.NullString
+		if err := rows.Scan(&item.ID, &value); err != nil {
+			return nil, err
+		}
+		item.CfgValue = value.String
+		list = append(list, item)
+	}
+	return list, rows.Err()
+}
+
+// ListRechargeProducts 查询 p_recharge_product 全部充值套餐
+func ListRechargeProducts(db *sql.DB) (list []system.RiskBirdRechargeProduct, err error) {
+	defer observeRiskBirdDB("list_recharge_products", time.Now(), &err)
+	rows, err := db.Query("SELECT id, amount, gift_amount FROM p_recharge_product ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item system.RiskBirdRechargeProduct
+		if err := rows.Scan(&item.ID, &item.Amount, &item.GiftAmount); err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return list, rows.Err()
+}
+
+// RestoreConfigRows 在一个事务中写回配置值和充值套餐金额，只更新已存在的记录
+func RestoreConfigRows(db *sql.DB, productCfgs []system.RiskBirdProductCfg, rechargeProducts []system.RiskBirdRechargeProduct) (err error) {
+	defer observeRiskBirdDB("restore_config_rows", time.Now(), &err)
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, item := range productCfgs {
+		if _, err := tx.Exec("UPDATE p_product_cfg SET cfg_value = ? WHERE id = ?", item.CfgValue, item.ID); err != nil {
+			return err
+		}
+	}
+	for _, item := range rechargeProducts {
+		if _, err := tx.Exec("UPDATE p_recharge_product SET amount = ?, gift_amount = ? WHERE id = ?", item.Amount, item.GiftAmount, item.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
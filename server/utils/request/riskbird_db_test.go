@@ -0,0 +1,41 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestListPendingPointAcquisitionsNullTimes(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gdb.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE point_acquisition (id INTEGER PRIMARY KEY, user_id INTEGER, points INTEGER, left_points INTEGER,
+		audit_status INTEGER, point_time DATETIME NULL, expire_time DATETIME NULL, create_time DATETIME NULL)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RiskBird 刚生成的待审核记录可能还没有积分获取时间和失效时间
+	_, err = db.Exec("INSERT INTO point_acquisition (id, user_id, points, left_points, audit_status) VALUES (1, 7, 10, 10, ?)", PointAcquisitionAuditPending)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, total, err := ListPendingPointAcquisitions(db, 7, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(list) != 1 {
+		t.Fatalf("ListPendingPointAcquisitions() total = %d, len = %d, want 1", total, len(list))
+	}
+	if item := list[0]; item.ID != 1 || !item.PointTime.IsZero() || !item.ExpireTime.IsZero() || !item.CreateTime.IsZero() {
+		t.Errorf("ListPendingPointAcquisitions() item = %+v, want zero times", item)
+	}
+}
@@ -0,0 +1,74 @@
+package request
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RiskBird 接口和数据库操作指标，标签中不能出现手机号等用户数据
+var (
+	riskBirdAPIRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "riskbird_api_requests_total",
+		Help: "RiskBird 接口调用次数，重试时每次调用分别计数，code 为 HTTP 状态码，网络错误为 error",
+	}, []string{"env", "endpoint", "code"})
+	riskBirdAPIDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "riskbird_api_request_duration_seconds",
+		Help:    "RiskBird 接口单次调用耗时",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"env", "endpoint"})
+	riskBirdAPIErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "riskbird_api_errors_total",
+		Help: "RiskBird 接口调用失败次数，reason 为 network/http_4xx/http_5xx/circuit_open",
+	}, []string{"env", "endpoint", "reason"})
+
+	riskBirdDBQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "riskbird_db_queries_total",
+		Help: "RiskBird 数据库操作次数",
+	}, []string{"op"})
+	riskBirdDBDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "riskbird_db_query_duration_seconds",
+		Help:    "RiskBird 数据库操作耗时",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"op"})
+	riskBirdDBErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "riskbird_db_errors_total",
+		Help: "RiskBird 数据库操作失败次数，查询无结果不计为失败",
+	}, []string{"op"})
+)
+
+// observeRiskBirdAPI 记录一次接口调用，resp 为 nil 表示请求未完成
+func observeRiskBirdAPI(env, endpoint string, start time.Time, resp *http.Response, err error) {
+	riskBirdAPIDuration.WithLabelValues(env, endpoint).Observe(time.Since(start).Seconds())
+	if err != nil {
+		riskBirdAPIRequests.WithLabelValues(env, endpoint, "error").Inc()
+		riskBirdAPIErrors.WithLabelValues(env, endpoint, "network").Inc()
+		return
+	}
+	riskBirdAPIRequests.WithLabelValues(env, endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+	switch {
+	case resp.StatusCode >= 500:
+		riskBirdAPIErrors.WithLabelValues(env, endpoint, "http_5xx").Inc()
+	case resp.StatusCode >= 400:
+		riskBirdAPIErrors.WithLabelValues(env, endpoint, "http_4xx").Inc()
+	}
+}
+
+// observeRiskBirdCircuitOpen 记录一次因熔断未发出的调用
+func observeRiskBirdCircuitOpen(env, endpoint string) {
+	riskBirdAPIErrors.WithLabelValues(env, endpoint, "circuit_open").Inc()
+}
+
+// observeRiskBirdDB 记录一次数据库操作，在数据库辅助函数中以 defer 调用
+func observeRiskBirdDB(op string, start time.Time, err *error) {
+	riskBirdDBQueries.WithLabelValues(op).Inc()
+	riskBirdDBDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	if *err != nil && !errors.Is(*err, sql.ErrNoRows) {
+		riskBirdDBErrors.WithLabelValues(op).Inc()
+	}
+}
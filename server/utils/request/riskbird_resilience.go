@@ -0,0 +1,290 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"go.uber.org/zap"
+)
+
+// 接口名称，用作指标标签和重试策略的键。login/balance/point-overview/admin-login 为幂等接口，默认重试；
+// job 会触发任意定时任务，超时或5xx时任务可能已执行，只有按接口配置后才重试
+const (
+	EndpointLogin          = "login"
+	EndpointBalance        = "balance"
+	EndpointPointOverview  = "point-overview"
+	EndpointJob            = "job"
+	EndpointAdminLogin     = "admin-login"
+	EndpointSendSmsCode    = "send-sms-code"
+	EndpointRegister       = "register"
+	EndpointSmsLogin       = "sms-login"
+	EndpointCreatePreOrder = "create-pre-order"
+	EndpointCreateOrder    = "create-order"
+	EndpointUpdateOrder    = "update-order"
+	EndpointAuditPoint     = "audit-point"
+	EndpointImpersonate    = "impersonate"
+)
+
+var (
+	// ErrCircuitOpen 环境熔断中，调用未发出
+	ErrCircuitOpen = errors.New("RiskBird环境熔断中，暂停调用")
+	// ErrOutcomeUnknown 请求超时或返回5xx，无法确定 RiskBird 是否已处理，创建类接口不能直接重试
+	ErrOutcomeUnknown = errors.New("请求结果未知")
+)
+
+// RetryPolicy 重试策略
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy 幂等接口未配置重试策略时使用
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// DefaultRetryPolicies 默认对幂等接口启用重试，定时任务接口不在其中
+func DefaultRetryPolicies() map[string]RetryPolicy {
+	return map[string]RetryPolicy{
+		EndpointLogin:         DefaultRetryPolicy,
+		EndpointBalance:       DefaultRetryPolicy,
+		EndpointPointOverview: DefaultRetryPolicy,
+		EndpointAdminLogin:    DefaultRetryPolicy,
+	}
+}
+
+// Delay 第 attempt 次失败后的等待时间
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.Backoff << (attempt - 1)
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d <= 0) {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常放行
+	BreakerOpen     = "open"      // 熔断中，直接失败
+	BreakerHalfOpen = "half_open" // 熔断期已过，放行一次试探调用
+)
+
+// BreakerSnapshot 熔断器当前状态
+type BreakerSnapshot struct {
+	State     string     `json:"state"`     // 状态
+	Failures  int        `json:"failures"`  // 连续失败次数
+	OpenedAt  *time.Time `json:"openedAt"`  // 最近一次熔断时间
+	LastError string     `json:"lastError"` // 最近一次失败原因
+}
+
+// CircuitBreaker 按环境统计连续失败次数的熔断器
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	openFor   time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	lastError string
+}
+
+// NewCircuitBreaker 创建熔断器，threshold 为连续失败次数阈值，openFor 为熔断时长
+func NewCircuitBreaker(threshold int, openFor time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, openFor: openFor, state: BreakerClosed}
+}
+
+// Allow 判断是否放行本次调用，半开状态下同一时间只放行一次试探调用
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.openFor {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// Success 记录一次成功调用，半开状态下恢复为正常
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure 记录一次失败调用，连续失败达到阈值或试探调用失败时熔断
+func (b *CircuitBreaker) Failure(reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastError = reason
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// Snapshot 返回熔断器当前状态
+func (b *CircuitBreaker) Snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	snapshot := BreakerSnapshot{State: b.state, Failures: b.failures, LastError: b.lastError}
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	// 熔断期已过但尚未有调用时按半开展示
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.openFor {
+		snapshot.State = BreakerHalfOpen
+	}
+	return snapshot
+}
+
+// riskBirdBreakers 各环境的熔断器，同一环境的全部客户端共用
+var riskBirdBreakers = struct {
+	sync.Mutex
+	m map[string]*CircuitBreaker
+}{m: make(map[string]*CircuitBreaker)}
+
+// GetCircuitBreaker 获取环境的熔断器，不存在时按给定参数创建
+func GetCircuitBreaker(env string, threshold int, openFor time.Duration) *CircuitBreaker {
+	riskBirdBreakers.Lock()
+	defer riskBirdBreakers.Unlock()
+	b, ok := riskBirdBreakers.m[env]
+	if !ok {
+		b = NewCircuitBreaker(threshold, openFor)
+		riskBirdBreakers.m[env] = b
+	}
+	return b
+}
+
+// do 发送请求并记录指标，endpoint 配置了重试策略时对网络错误和5xx按退避重试，并记录熔断器状态。
+// newRequest 每次调用都重新构造请求，保证请求体可以重复发送
+func (c *RiskBirdAPIClient) do(endpoint string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	policy, ok := c.Retry[endpoint]
+	if !ok || policy.MaxAttempts < 1 {
+		policy = RetryPolicy{MaxAttempts: 1}
+	}
+	for attempt := 1; ; attempt++ {
+		// 先构造请求再占用熔断器，构造失败时不会占住半开状态的试探名额
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if c.Breaker != nil {
+			if err := c.Breaker.Allow(); err != nil {
+				observeRiskBirdCircuitOpen(c.Env, endpoint)
+				return nil, err
+			}
+		}
+		start := time.Now()
+		resp, err := c.Client.Do(req)
+		err = redactRequestError(err)
+		observeRiskBirdAPI(c.Env, endpoint, start, resp, err)
+		var reason string
+		switch {
+		case err != nil:
+			reason = requestFailureReason(err)
+		case resp.StatusCode >= 500:
+			reason = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		}
+		if c.Breaker != nil {
+			if reason == "" {
+				c.Breaker.Success()
+			} else {
+				c.Breaker.Failure(reason)
+			}
+		}
+		if reason == "" || attempt >= policy.MaxAttempts {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		wait := policy.Delay(attempt)
+		global.GVA_LOG.Warn("RiskBird接口调用失败，准备重试",
+			zap.String("endpoint", endpoint),
+			zap.Int("attempt", attempt),
+			zap.Duration("wait", wait),
+			zap.String("reason", reason))
+		time.Sleep(wait)
+	}
+}
+
+// redactRequestError 去掉网络错误中请求地址的查询参数和用户信息。
+// 登录、注册等接口通过查询参数传递密码和验证码，错误会被写入日志、熔断器和健康检查结果
+func redactRequestError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	redacted := *urlErr
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+		u.RawQuery, u.User, u.Fragment = "", nil, ""
+		redacted.URL = u.String()
+	} else {
+		redacted.URL = ""
+	}
+	return &redacted
+}
+
+// requestFailureReason 熔断器和重试日志记录的失败原因，只记录错误类别，不记录原始错误
+func requestFailureReason(err error) string {
+	var netErr interface{ Timeout() bool }
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "请求超时"
+	case errors.Is(err, context.Canceled):
+		return "请求已取消"
+	default:
+		return "网络错误"
+	}
+}
+
+// newEmptyPost 构造无请求体的 POST 请求，与 http.Client.Post 设置相同的请求头
+func newEmptyPost(urlStr string) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}
+}
+
+// outcomeUnknown 判断创建类请求的结果是否不确定，不确定时包装为 ErrOutcomeUnknown
+func outcomeUnknown(resp *http.Response, err error) error {
+	if errors.Is(err, ErrCircuitOpen) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutcomeUnknown, err)
+	}
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%w: HTTP %d", ErrOutcomeUnknown, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flipped-aurora/gin-vue-admin/server/global"
+	"go.uber.org/zap"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*RiskBirdAPIClient, *int32) {
+	global.GVA_LOG = zap.NewNop()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	client := NewRiskBirdAPIClient(server.URL)
+	for endpoint := range client.Retry {
+		client.Retry[endpoint] = RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	}
+	return client, &calls
+}
+
+func TestRetryIdempotentCall(t *testing.T) {
+	var failures int32 = 2
+	client, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":20000,"data":{"totalBalance":12.30}}`))
+	})
+	balance, err := client.GetBalance("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 1230 || *calls != 3 {
+		t.Errorf("GetBalance() got = %v after %d calls, want 12.30 after 3 calls", balance, *calls)
+	}
+}
+
+func TestCreateCallNotRetried(t *testing.T) {
+	client, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	_, err := client.CreateOrder("token", map[string]interface{}{})
+	if !errors.Is(err, ErrOutcomeUnknown) {
+		t.Errorf("CreateOrder() error = %v, want %v", err, ErrOutcomeUnknown)
+	}
+	if *calls != 1 {
+		t.Errorf("CreateOrder() called %d times, want 1", *calls)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	client, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.Retry = nil
+	client.Breaker = NewCircuitBreaker(2, 20*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetBalance("token"); err == nil {
+			t.Fatal("GetBalance() want error")
+		}
+	}
+	if state := client.Breaker.Snapshot().State; state != BreakerOpen {
+		t.Fatalf("breaker state = %s, want %s", state, BreakerOpen)
+	}
+	if _, err := client.GetBalance("token"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("GetBalance() error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if *calls != 2 {
+		t.Errorf("server called %d times, want 2", *calls)
+	}
+
+	// 熔断期过后放行一次试探调用，试探失败重新熔断
+	time.Sleep(30 * time.Millisecond)
+	if state := client.Breaker.Snapshot().State; state != BreakerHalfOpen {
+		t.Fatalf("breaker state = %s, want %s", state, BreakerHalfOpen)
+	}
+	_, _ = client.GetBalance("token")
+	if state := client.Breaker.Snapshot().State; state != BreakerOpen || *calls != 3 {
+		t.Errorf("breaker state = %s after %d calls, want %s after 3 calls", state, *calls, BreakerOpen)
+	}
+}
+
+func TestRequestErrorRedacted(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	client.Retry = nil
+	client.Breaker = NewCircuitBreaker(5, time.Minute)
+	client.BaseURL = "http://127.0.0.1:1"
+	_, err := client.Login("13800000000", "secret-password")
+	if err == nil {
+		t.Fatal("Login() want error")
+	}
+	for name, got := range map[string]string{"error": err.Error(), "breaker": client.Breaker.Snapshot().LastError} {
+		if strings.Contains(got, "secret-password") || strings.Contains(got, "13800000000") {
+			t.Errorf("%s = %q, want credentials removed", name, got)
+		}
+	}
+}
+
+func TestBreakerProbeReleasedOnRequestError(t *testing.T) {
+	client, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":20000,"data":{"totalBalance":1}}`))
+	})
+	client.Retry = nil
+	client.Breaker = NewCircuitBreaker(1, 10*time.Millisecond)
+	client.Breaker.Failure("HTTP 503")
+	time.Sleep(20 * time.Millisecond)
+
+	// 半开状态下构造请求失败，不应占住试探名额
+	if _, err := client.do(EndpointBalance, func() (*http.Request, error) { return nil, errors.New("bad request") }); err == nil {
+		t.Fatal("do() want error")
+	}
+	if _, err := client.GetBalance("token"); err != nil {
+		t.Fatalf("GetBalance() error = %v, want probe allowed", err)
+	}
+	if state := client.Breaker.Snapshot().State; state != BreakerClosed || *calls != 1 {
+		t.Errorf("breaker state = %s after %d calls, want %s after 1 call", state, *calls, BreakerClosed)
+	}
+}
+
+func TestJobCallNotRetriedByDefault(t *testing.T) {
+	global.GVA_LOG = zap.NewNop()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	client := NewRiskBirdAPIClient(server.URL)
+	if status, _, _ := client.TriggerJob(http.MethodPost, "/guest/job/expirePoint", nil); status != http.StatusBadGateway {
+		t.Errorf("TriggerJob() status = %d, want %d", status, http.StatusBadGateway)
+	}
+	if calls != 1 {
+		t.Errorf("TriggerJob() called %d times, want 1", calls)
+	}
+}
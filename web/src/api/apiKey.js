@@ -0,0 +1,49 @@
+import service from '@/utils/request'
+
+// @Tags SysApiKey
+// @Summary 为服务账号创建API密钥，明文密钥只返回一次
+// @Security ApiKeyAuth
+// @Router /apiKey/createApiKey [post]
+export const createApiKey = (data) => {
+  return service({
+    url: '/apiKey/createApiKey',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags SysApiKey
+// @Summary 吊销API密钥
+// @Security ApiKeyAuth
+// @Router /apiKey/revokeApiKey [post]
+export const revokeApiKey = (data) => {
+  return service({
+    url: '/apiKey/revokeApiKey',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags SysApiKey
+// @Summary 分页获取API密钥
+// @Security ApiKeyAuth
+// @Router /apiKey/getApiKeyList [get]
+export const getApiKeyList = (params) => {
+  return service({
+    url: '/apiKey/getApiKeyList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags SysApiKey
+// @Summary 分页获取API密钥调用记录
+// @Security ApiKeyAuth
+// @Router /apiKey/getApiKeyUsageList [get]
+export const getApiKeyUsageList = (params) => {
+  return service({
+    url: '/apiKey/getApiKeyUsageList',
+    method: 'get',
+    params
+  })
+}
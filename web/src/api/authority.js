@@ -83,3 +83,38 @@ export const updateAuthority = (data) => {
     data
   })
 }
+
+// @Summary 设置角色RiskBird环境权限
+// @Security ApiKeyAuth
+// @accept application/json
+// @Produce application/json
+// @Param data body systemReq.SetRiskBirdScopes true "角色ID, 各环境允许的操作"
+// @Router /authority/setRiskBirdScopes [post]
+export const setRiskBirdScopes = (data) => {
+  return service({
+    url: '/authority/setRiskBirdScopes',
+    method: 'post',
+    data
+  })
+}
+
+// @Summary 获取角色RiskBird环境权限
+// @Security ApiKeyAuth
+// @Router /authority/getRiskBirdScopes [get]
+export const getRiskBirdScopes = (params) => {
+  return service({
+    url: '/authority/getRiskBirdScopes',
+    method: 'get',
+    params
+  })
+}
+
+// @Summary 获取当前用户可用的RiskBird环境
+// @Security ApiKeyAuth
+// @Router /authority/getRiskBirdAllowedEnvs [get]
+export const getRiskBirdAllowedEnvs = () => {
+  return service({
+    url: '/authority/getRiskBirdAllowedEnvs',
+    method: 'get'
+  })
+}
@@ -0,0 +1,85 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdAccount
+// @Summary 登记 RiskBird 账号
+// @Security ApiKeyAuth
+// @Router /riskbird/account/createAccount [post]
+export const createAccount = (data) => {
+  return service({
+    url: '/riskbird/account/createAccount',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdAccount
+// @Summary 删除登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @Router /riskbird/account/deleteAccount [delete]
+export const deleteAccount = (data) => {
+  return service({
+    url: '/riskbird/account/deleteAccount',
+    method: 'delete',
+    data
+  })
+}
+
+// @Tags RiskBirdAccount
+// @Summary 更新登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @Router /riskbird/account/updateAccount [put]
+export const updateAccount = (data) => {
+  return service({
+    url: '/riskbird/account/updateAccount',
+    method: 'put',
+    data
+  })
+}
+
+// @Tags RiskBirdAccount
+// @Summary 注册并登记 RiskBird 测试账号
+// @Security ApiKeyAuth
+// @Router /riskbird/account/provisionAccount [post]
+export const provisionAccount = (data) => {
+  return service({
+    url: '/riskbird/account/provisionAccount',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdAccount
+// @Summary 用id查询登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @Router /riskbird/account/findAccount [get]
+export const findAccount = (params) => {
+  return service({
+    url: '/riskbird/account/findAccount',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdAccount
+// @Summary 分页获取登记的 RiskBird 账号
+// @Security ApiKeyAuth
+// @Router /riskbird/account/getAccountList [get]
+export const getAccountList = (params) => {
+  return service({
+    url: '/riskbird/account/getAccountList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdAccount
+// @Summary 获取账号变更时间线
+// @Security ApiKeyAuth
+// @Router /riskbird/account/getAccountTimeline [get]
+export const getAccountTimeline = (params) => {
+  return service({
+    url: '/riskbird/account/getAccountTimeline',
+    method: 'get',
+    params
+  })
+}
@@ -0,0 +1,85 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdConfig
+// @Summary 比对RiskBird价格和充值套餐配置
+// @Security ApiKeyAuth
+// @Router /riskbird/config/diffConfig [get]
+export const diffConfig = (params) => {
+  return service({
+    url: '/riskbird/config/diffConfig',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdConfig
+// @Summary 从环境快照创建配置基线
+// @Security ApiKeyAuth
+// @Router /riskbird/config/createBaseline [post]
+export const createBaseline = (data) => {
+  return service({
+    url: '/riskbird/config/createBaseline',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdConfig
+// @Summary 删除配置基线
+// @Security ApiKeyAuth
+// @Router /riskbird/config/deleteBaseline [delete]
+export const deleteBaseline = (data) => {
+  return service({
+    url: '/riskbird/config/deleteBaseline',
+    method: 'delete',
+    data
+  })
+}
+
+// @Tags RiskBirdConfig
+// @Summary 根据ID获取配置基线
+// @Security ApiKeyAuth
+// @Router /riskbird/config/findBaseline [get]
+export const findBaseline = (params) => {
+  return service({
+    url: '/riskbird/config/findBaseline',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdConfig
+// @Summary 分页获取配置基线
+// @Security ApiKeyAuth
+// @Router /riskbird/config/getBaselineList [get]
+export const getBaselineList = (params) => {
+  return service({
+    url: '/riskbird/config/getBaselineList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdConfig
+// @Summary 按基线恢复环境配置
+// @Security ApiKeyAuth
+// @Router /riskbird/config/restoreBaseline [post]
+export const restoreBaseline = (data) => {
+  return service({
+    url: '/riskbird/config/restoreBaseline',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdConfig
+// @Summary 分页获取配置恢复记录
+// @Security ApiKeyAuth
+// @Router /riskbird/config/getRestoreList [get]
+export const getRestoreList = (params) => {
+  return service({
+    url: '/riskbird/config/getRestoreList',
+    method: 'get',
+    params
+  })
+}
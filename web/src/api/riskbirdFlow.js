@@ -0,0 +1,68 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdFlow
+// @Summary 异步修改用户余额
+// @Security ApiKeyAuth
+// @Router /riskbird/flow/startModifyUserBalance [post]
+export const startModifyUserBalance = (data) => {
+  return service({
+    url: '/riskbird/flow/startModifyUserBalance',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdFlow
+// @Summary 异步修改用户积分
+// @Security ApiKeyAuth
+// @Router /riskbird/flow/startModifyUserPoint [post]
+export const startModifyUserPoint = (data) => {
+  return service({
+    url: '/riskbird/flow/startModifyUserPoint',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdFlow
+// @Summary 订阅流程步骤事件，收到 finished 事件后自动关闭连接，返回关闭函数
+// @Security ApiKeyAuth
+// @Router /riskbird/flow/streamRunEvents [get]
+export const streamRunEvents = (id, onEvent) => {
+  const source = new EventSource(
+    `${import.meta.env.VITE_BASE_API}/riskbird/flow/streamRunEvents?ID=${id}`,
+    { withCredentials: true }
+  )
+  source.addEventListener('step', (e) => {
+    const event = JSON.parse(e.data)
+    onEvent(event)
+    if (event.event === 'finished') {
+      source.close()
+    }
+  })
+  return () => source.close()
+}
+
+// @Tags RiskBirdFlow
+// @Summary 用id查询流程执行记录
+// @Security ApiKeyAuth
+// @Router /riskbird/flow/findFlowRun [get]
+export const findFlowRun = (params) => {
+  return service({
+    url: '/riskbird/flow/findFlowRun',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdFlow
+// @Summary 分页获取流程执行记录
+// @Security ApiKeyAuth
+// @Router /riskbird/flow/getFlowRunList [get]
+export const getFlowRunList = (params) => {
+  return service({
+    url: '/riskbird/flow/getFlowRunList',
+    method: 'get',
+    params
+  })
+}
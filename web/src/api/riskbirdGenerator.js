@@ -0,0 +1,86 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdGenerator
+// @Summary 创建批量造数任务并开始执行
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/createJob [post]
+export const createJob = (data) => {
+  return service({
+    url: '/riskbird/generator/createJob',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdGenerator
+// @Summary 继续执行造数任务
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/resumeJob [post]
+export const resumeJob = (data) => {
+  return service({
+    url: '/riskbird/generator/resumeJob',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdGenerator
+// @Summary 停止造数任务
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/stopJob [post]
+export const stopJob = (data) => {
+  return service({
+    url: '/riskbird/generator/stopJob',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdGenerator
+// @Summary 用id查询造数任务
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/findJob [get]
+export const findJob = (params) => {
+  return service({
+    url: '/riskbird/generator/findJob',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdGenerator
+// @Summary 分页获取造数任务
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/getJobList [get]
+export const getJobList = (params) => {
+  return service({
+    url: '/riskbird/generator/getJobList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdGenerator
+// @Summary 分页获取造数任务的账号清单
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/getItemList [get]
+export const getItemList = (params) => {
+  return service({
+    url: '/riskbird/generator/getItemList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdGenerator
+// @Summary 导出造数任务的账号清单
+// @Security ApiKeyAuth
+// @Router /riskbird/generator/exportManifest [get]
+export const exportManifest = (params) => {
+  return service({
+    url: '/riskbird/generator/exportManifest',
+    method: 'get',
+    params,
+    responseType: 'blob'
+  })
+}
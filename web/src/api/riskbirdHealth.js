@@ -0,0 +1,36 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdHealth
+// @Summary 立即检查RiskBird环境
+// @Security ApiKeyAuth
+// @Router /riskbird/health/checkEnv [get]
+export const checkEnv = (params) => {
+  return service({
+    url: '/riskbird/health/checkEnv',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdHealth
+// @Summary 获取各环境最近一次健康检查报告
+// @Security ApiKeyAuth
+// @Router /riskbird/health/getLatestReports [get]
+export const getLatestReports = () => {
+  return service({
+    url: '/riskbird/health/getLatestReports',
+    method: 'get'
+  })
+}
+
+// @Tags RiskBirdHealth
+// @Summary 分页获取健康检查报告
+// @Security ApiKeyAuth
+// @Router /riskbird/health/getHealthReportList [get]
+export const getHealthReportList = (params) => {
+  return service({
+    url: '/riskbird/health/getHealthReportList',
+    method: 'get',
+    params
+  })
+}
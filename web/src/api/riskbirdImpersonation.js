@@ -0,0 +1,13 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdImpersonation
+// @Summary 分页获取代登录审计记录
+// @Security ApiKeyAuth
+// @Router /riskbird/impersonation/getImpersonationLogList [get]
+export const getImpersonationLogList = (params) => {
+  return service({
+    url: '/riskbird/impersonation/getImpersonationLogList',
+    method: 'get',
+    params
+  })
+}
@@ -0,0 +1,49 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdJob
+// @Summary 获取定时任务接口目录
+// @Security ApiKeyAuth
+// @Router /riskbird/job/getJobCatalog [get]
+export const getJobCatalog = (params) => {
+  return service({
+    url: '/riskbird/job/getJobCatalog',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdJob
+// @Summary 触发定时任务
+// @Security ApiKeyAuth
+// @Router /riskbird/job/triggerJob [post]
+export const triggerJob = (data) => {
+  return service({
+    url: '/riskbird/job/triggerJob',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdJob
+// @Summary 用id查询定时任务调用记录
+// @Security ApiKeyAuth
+// @Router /riskbird/job/findJobRecord [get]
+export const findJobRecord = (params) => {
+  return service({
+    url: '/riskbird/job/findJobRecord',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdJob
+// @Summary 分页获取定时任务调用记录
+// @Security ApiKeyAuth
+// @Router /riskbird/job/getJobRecordList [get]
+export const getJobRecordList = (params) => {
+  return service({
+    url: '/riskbird/job/getJobRecordList',
+    method: 'get',
+    params
+  })
+}
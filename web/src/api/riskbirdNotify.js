@@ -0,0 +1,35 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdNotify
+// @Summary 获取当前用户的通知偏好
+// @Security ApiKeyAuth
+// @Router /riskbird/notify/getPreference [get]
+export const getPreference = () => {
+  return service({
+    url: '/riskbird/notify/getPreference',
+    method: 'get'
+  })
+}
+
+// @Tags RiskBirdNotify
+// @Summary 设置当前用户的通知偏好
+// @Security ApiKeyAuth
+// @Router /riskbird/notify/setPreference [put]
+export const setPreference = (data) => {
+  return service({
+    url: '/riskbird/notify/setPreference',
+    method: 'put',
+    data
+  })
+}
+
+// @Tags RiskBirdNotify
+// @Summary 按当前用户的通知偏好发送测试通知
+// @Security ApiKeyAuth
+// @Router /riskbird/notify/sendTestNotification [post]
+export const sendTestNotification = () => {
+  return service({
+    url: '/riskbird/notify/sendTestNotification',
+    method: 'post'
+  })
+}
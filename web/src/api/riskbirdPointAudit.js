@@ -0,0 +1,25 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdPointAudit
+// @Summary 分页获取待审核的积分获取记录
+// @Security ApiKeyAuth
+// @Router /riskbird/pointAudit/getPendingPointList [get]
+export const getPendingPointList = (params) => {
+  return service({
+    url: '/riskbird/pointAudit/getPendingPointList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdPointAudit
+// @Summary 批量审核积分获取记录
+// @Security ApiKeyAuth
+// @Router /riskbird/pointAudit/auditPointAcquisitions [post]
+export const auditPointAcquisitions = (data) => {
+  return service({
+    url: '/riskbird/pointAudit/auditPointAcquisitions',
+    method: 'post',
+    data
+  })
+}
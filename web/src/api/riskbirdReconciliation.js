@@ -0,0 +1,25 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdReconciliation
+// @Summary 核对账号库中账号的余额和积分
+// @Security ApiKeyAuth
+// @Router /riskbird/reconcile/reconcileAccounts [post]
+export const reconcileAccounts = (data) => {
+  return service({
+    url: '/riskbird/reconcile/reconcileAccounts',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdReconciliation
+// @Summary 分页获取账务核对结果
+// @Security ApiKeyAuth
+// @Router /riskbird/reconcile/getReconciliationList [get]
+export const getReconciliationList = (params) => {
+  return service({
+    url: '/riskbird/reconcile/getReconciliationList',
+    method: 'get',
+    params
+  })
+}
@@ -0,0 +1,85 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdResetSchedule
+// @Summary 创建账号重置计划
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/createResetSchedule [post]
+export const createResetSchedule = (data) => {
+  return service({
+    url: '/riskbird/resetSchedule/createResetSchedule',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdResetSchedule
+// @Summary 删除账号重置计划
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/deleteResetSchedule [delete]
+export const deleteResetSchedule = (data) => {
+  return service({
+    url: '/riskbird/resetSchedule/deleteResetSchedule',
+    method: 'delete',
+    data
+  })
+}
+
+// @Tags RiskBirdResetSchedule
+// @Summary 更新账号重置计划
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/updateResetSchedule [put]
+export const updateResetSchedule = (data) => {
+  return service({
+    url: '/riskbird/resetSchedule/updateResetSchedule',
+    method: 'put',
+    data
+  })
+}
+
+// @Tags RiskBirdResetSchedule
+// @Summary 立即执行账号重置计划
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/runResetSchedule [post]
+export const runResetSchedule = (data) => {
+  return service({
+    url: '/riskbird/resetSchedule/runResetSchedule',
+    method: 'post',
+    data
+  })
+}
+
+// @Tags RiskBirdResetSchedule
+// @Summary 用id查询账号重置计划
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/findResetSchedule [get]
+export const findResetSchedule = (params) => {
+  return service({
+    url: '/riskbird/resetSchedule/findResetSchedule',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdResetSchedule
+// @Summary 分页获取账号重置计划列表
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/getResetScheduleList [get]
+export const getResetScheduleList = (params) => {
+  return service({
+    url: '/riskbird/resetSchedule/getResetScheduleList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdResetSchedule
+// @Summary 分页获取账号重置执行记录
+// @Security ApiKeyAuth
+// @Router /riskbird/resetSchedule/getResetRunList [get]
+export const getResetRunList = (params) => {
+  return service({
+    url: '/riskbird/resetSchedule/getResetRunList',
+    method: 'get',
+    params
+  })
+}
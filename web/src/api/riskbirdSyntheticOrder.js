@@ -0,0 +1,25 @@
+import service from '@/utils/request'
+
+// @Tags RiskBirdSyntheticOrder
+// @Summary 分页获取合成订单
+// @Security ApiKeyAuth
+// @Router /riskbird/syntheticOrder/getSyntheticOrderList [get]
+export const getSyntheticOrderList = (params) => {
+  return service({
+    url: '/riskbird/syntheticOrder/getSyntheticOrderList',
+    method: 'get',
+    params
+  })
+}
+
+// @Tags RiskBirdSyntheticOrder
+// @Summary 清理合成订单
+// @Security ApiKeyAuth
+// @Router /riskbird/syntheticOrder/cleanupOrders [post]
+export const cleanupOrders = (data) => {
+  return service({
+    url: '/riskbird/syntheticOrder/cleanupOrders',
+    method: 'post',
+    data
+  })
+}